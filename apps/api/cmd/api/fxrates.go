@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joho/godotenv"
+
+	"github.com/example/bookapi/internal/repo"
+	"github.com/example/bookapi/internal/service"
+)
+
+// fxRatesMaxBytes bounds the rates document read from a file or URL.
+const fxRatesMaxBytes = 1 << 20
+
+// runFXRates implements `api fx-rates`, which loads exchange rates from a
+// JSON file or URL into fx_rates.
+func runFXRates(args []string) error {
+	fs := flag.NewFlagSet("api fx-rates", flag.ExitOnError)
+	source := fs.String("source", "", `file or http(s) URL of a rates document: {"base": "EUR", "rates": {"USD": 1.0832}}`)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *source == "" {
+		return errors.New("-source is required")
+	}
+
+	_ = godotenv.Load()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	input, err := readFXRates(ctx, *source)
+	if err != nil {
+		return err
+	}
+
+	dsn := os.Getenv("DB_DSN")
+	if strings.TrimSpace(dsn) == "" {
+		return errors.New("DB_DSN is required")
+	}
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		return fmt.Errorf("connect to database: %w", err)
+	}
+	defer pool.Close()
+
+	if err := applyMigrations(ctx, pool); err != nil {
+		return fmt.Errorf("apply migrations: %w", err)
+	}
+
+	bookService := service.NewBookService(repo.NewBookRepository(pool),
+		service.WithFXRates(repo.NewFXRateRepository(pool), ""),
+	)
+	rates, err := bookService.LoadFXRates(ctx, input)
+	if err != nil {
+		var validationErr service.ValidationError
+		if errors.As(err, &validationErr) {
+			return fmt.Errorf("invalid rates: %v", validationErr.Fields)
+		}
+		return err
+	}
+	fmt.Printf("loaded %d rates against %s\n", len(rates), input.Base)
+	return nil
+}
+
+// readFXRates reads a rates document from a file or an http(s) URL. Rates
+// may be JSON numbers or strings; numbers are kept exactly as written.
+func readFXRates(ctx context.Context, source string) (service.FXRatesInput, error) {
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+		if err != nil {
+			return service.FXRatesInput{}, err
+		}
+		req.Header.Set("Accept", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return service.FXRatesInput{}, fmt.Errorf("fetch rates: %w", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return service.FXRatesInput{}, fmt.Errorf("fetch rates: %s", resp.Status)
+		}
+		return decodeFXRates(resp.Body)
+	}
+
+	f, err := os.Open(source)
+	if err != nil {
+		return service.FXRatesInput{}, err
+	}
+	defer f.Close()
+	return decodeFXRates(f)
+}
+
+func decodeFXRates(r io.Reader) (service.FXRatesInput, error) {
+	var document struct {
+		Base  string                 `json:"base"`
+		Rates map[string]json.Number `json:"rates"`
+	}
+	if err := json.NewDecoder(io.LimitReader(r, fxRatesMaxBytes)).Decode(&document); err != nil {
+		return service.FXRatesInput{}, fmt.Errorf("decode rates: %w", err)
+	}
+	input := service.FXRatesInput{Base: document.Base, Rates: make(map[string]string, len(document.Rates))}
+	for currency, rate := range document.Rates {
+		input.Rates[currency] = rate.String()
+	}
+	return input, nil
+}
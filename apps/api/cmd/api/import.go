@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/joho/godotenv"
+
+	"github.com/example/bookapi/internal/domain"
+	"github.com/example/bookapi/internal/importer"
+	"github.com/example/bookapi/internal/repo"
+	"github.com/example/bookapi/internal/service"
+)
+
+// runImport implements `api import`, which loads a CSV or NDJSON catalog
+// through BookService so every record gets the same validation as
+// POST /books.
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("api import", flag.ExitOnError)
+	file := fs.String("file", "", "catalog file to import")
+	format := fs.String("format", "", "file format: csv or ndjson (defaults to the file extension)")
+	mapping := fs.String("map", "", `columns to read fields from, e.g. "title=Book Title,price=RRP"; unmapped fields use their own name`)
+	dryRun := fs.Bool("dry-run", false, "validate the file and report what would change without writing anything")
+	progressPath := fs.String("progress", "", "file tracking imported records so an interrupted import resumes (defaults to FILE.progress)")
+	actor := fs.String("actor", "import", "actor recorded in the revision history")
+	tenant := fs.String("tenant", domain.DefaultTenant, "tenant whose catalog the books are imported into")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return errors.New("-file is required")
+	}
+	if !domain.ValidTenantID(*tenant) {
+		return fmt.Errorf("invalid -tenant %q", *tenant)
+	}
+
+	_ = godotenv.Load()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	source, err := filepath.Abs(*file)
+	if err != nil {
+		return err
+	}
+	if *format == "" {
+		*format = formatFromExtension(source)
+	}
+	columns, err := importer.ParseMapping(*mapping)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	reader, err := importer.NewReader(f, *format, columns)
+	if err != nil {
+		return err
+	}
+
+	dsn := os.Getenv("DB_DSN")
+	if strings.TrimSpace(dsn) == "" {
+		return errors.New("DB_DSN is required")
+	}
+	pool, err := repo.NewPool(ctx, dsn)
+	if err != nil {
+		return fmt.Errorf("connect to database: %w", err)
+	}
+	defer pool.Close()
+
+	if err := applyMigrations(ctx, pool); err != nil {
+		return fmt.Errorf("apply migrations: %w", err)
+	}
+
+	opts := importer.Options{DryRun: *dryRun}
+	if !*dryRun {
+		if *progressPath == "" {
+			*progressPath = source + ".progress"
+		}
+		if opts.Progress, err = importer.LoadProgress(*progressPath, source); err != nil {
+			return err
+		}
+	}
+
+	txManager := repo.NewTxManager(pool)
+	bookService := service.NewBookService(repo.NewBookRepository(pool),
+		service.WithOutbox(txManager, repo.NewOutboxRepository(pool)),
+		service.WithRevisions(txManager, repo.NewRevisionRepository(pool)),
+		service.WithAuthors(repo.NewAuthorRepository(pool)),
+		service.WithCategories(repo.NewCategoryRepository(pool)),
+		service.WithTags(repo.NewTagRepository(pool)),
+	)
+
+	ctx = domain.ContextWithTenant(domain.ContextWithActor(ctx, *actor), *tenant)
+	report, err := importer.Run(ctx, reader, bookService, opts)
+	if writeErr := report.Write(os.Stdout); writeErr != nil && err == nil {
+		err = writeErr
+	}
+	if err != nil && !*dryRun {
+		return fmt.Errorf("import stopped; run the same command again to resume: %w", err)
+	}
+	if err != nil {
+		return err
+	}
+	if len(report.Failures) > 0 {
+		return fmt.Errorf("%d invalid records were not imported", len(report.Failures))
+	}
+	return nil
+}
+
+func formatFromExtension(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ndjson", ".jsonl":
+		return importer.FormatNDJSON
+	default:
+		return importer.FormatCSV
+	}
+}
@@ -5,26 +5,29 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
-	"sort"
+	"strconv"
 	"strings"
 	"syscall"
+	"text/tabwriter"
 	"time"
 
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/service/sns"
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/danielgtaylor/huma/v2/adapters/humamux"
 	"github.com/gorilla/mux"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joho/godotenv"
 
+	"github.com/example/bookapi/internal/domain"
 	"github.com/example/bookapi/internal/http/handlers"
 	"github.com/example/bookapi/internal/http/middleware"
+	"github.com/example/bookapi/internal/http/problem"
 	"github.com/example/bookapi/internal/notifications"
+	"github.com/example/bookapi/internal/outbox"
 	"github.com/example/bookapi/internal/repo"
 	"github.com/example/bookapi/internal/repo/migrations"
 	"github.com/example/bookapi/internal/service"
@@ -32,7 +35,27 @@ import (
 
 const deploymentMarker = "book-api-fargate-marker-2025-11-24"
 
+const (
+	storePostgres = "postgres"
+	storeMemory   = "memory"
+)
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		if err := runImport(os.Args[2:]); err != nil {
+			slog.Error("import failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "fx-rates" {
+		if err := runFXRates(os.Args[2:]); err != nil {
+			slog.Error("loading fx rates failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	if err := run(os.Args[1:]); err != nil {
 		slog.Error("failed to run server", "error", err)
 		os.Exit(1)
@@ -42,29 +65,90 @@ func main() {
 func run(args []string) error {
 	fs := flag.NewFlagSet("api", flag.ExitOnError)
 	migrateOnly := fs.Bool("migrate", false, "apply database migrations and exit")
+	migrateStatus := fs.Bool("migrate-status", false, "print database migration status and exit")
+	migrateDown := fs.Int("migrate-down", 0, "roll back the last N applied migrations and exit")
+	migrateTo := fs.Int64("migrate-to", -1, "migrate up or down to VERSION and exit (0 rolls back everything)")
+	confirm := fs.Bool("confirm", false, "execute the plan printed by -migrate-down or -migrate-to instead of only showing it")
+	purgeDeletedDays := fs.Int("purge-deleted-days", -1, "permanently remove books soft-deleted more than N days ago and exit")
+	tenant := fs.String("tenant", domain.DefaultTenant, "tenant whose books -purge-deleted-days removes")
+	store := fs.String("store", "", "book storage backend: postgres or memory (defaults to $STORE, then postgres)")
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
+	if !domain.ValidTenantID(*tenant) {
+		return fmt.Errorf("invalid -tenant %q", *tenant)
+	}
 
 	slog.Info("deployment marker reached", "marker", deploymentMarker, "pid", os.Getpid())
 
 	_ = godotenv.Load()
 
 	port := envOrDefault("PORT", "8080")
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	rounding, err := domain.ParseRoundingMode(os.Getenv("FX_ROUNDING"))
+	if err != nil {
+		return fmt.Errorf("FX_ROUNDING: %w", err)
+	}
+
+	storeName := strings.TrimSpace(*store)
+	if storeName == "" {
+		storeName = envOrDefault("STORE", storePostgres)
+	}
+
+	switch storeName {
+	case storePostgres:
+	case storeMemory:
+		if *migrateOnly || *migrateStatus || *migrateDown > 0 || *migrateTo >= 0 || *purgeDeletedDays >= 0 {
+			return errors.New("migration and purge flags require -store=postgres")
+		}
+		slog.Warn("using in-memory book store; data is lost on exit")
+		books := repo.NewMemoryBookRepository()
+		options := []service.BookServiceOption{
+			service.WithAuthors(books.Authors()),
+			service.WithCategories(books.Categories()),
+			service.WithTags(books.Tags()),
+			service.WithFXRates(repo.NewMemoryFXRateRepository(), rounding),
+			service.WithRevisions(nil, repo.NewMemoryRevisionRepository()),
+			service.WithStockLedger(nil, repo.NewMemoryStockLedgerRepository()),
+		}
+		// Without the outbox, events are published from the request path;
+		// the async publisher keeps SNS out of request latency.
+		var publisher *notifications.AsyncBookEventPublisher
+		if next := configureBookEventPublisher(ctx); next != nil {
+			publisher = startAsyncPublisher(next)
+			options = append(options, service.WithBookEventPublisher(publisher))
+		}
+		err := serve(ctx, port, buildHTTPHandler(books, options...))
+		if publisher != nil {
+			drainAsyncPublisher(publisher)
+		}
+		return err
+	default:
+		return fmt.Errorf("unknown store %q (want %s or %s)", storeName, storePostgres, storeMemory)
+	}
+
 	dsn := os.Getenv("DB_DSN")
 	if strings.TrimSpace(dsn) == "" {
 		return errors.New("DB_DSN is required")
 	}
 
-	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
-	defer stop()
-
-	pool, err := pgxpool.New(ctx, dsn)
+	pool, err := repo.NewPool(ctx, dsn)
 	if err != nil {
 		return fmt.Errorf("connect to database: %w", err)
 	}
 	defer pool.Close()
 
+	if *migrateStatus {
+		return printMigrationStatus(ctx, pool, os.Stdout)
+	}
+
+	if *migrateDown > 0 || *migrateTo >= 0 {
+		return runMigrationPlan(ctx, pool, os.Stdout, *migrateDown, *migrateTo, *confirm)
+	}
+
 	if err := applyMigrations(ctx, pool); err != nil {
 		return fmt.Errorf("apply migrations: %w", err)
 	}
@@ -74,11 +158,97 @@ func run(args []string) error {
 		return nil
 	}
 
-	httpHandler := buildHTTPHandler(pool)
+	if *purgeDeletedDays >= 0 {
+		return purgeDeletedBooks(domain.ContextWithTenant(ctx, *tenant), pool, *purgeDeletedDays)
+	}
+
+	bypasses, err := repo.BypassesRowSecurity(ctx, pool)
+	if err != nil {
+		return err
+	}
+	if bypasses {
+		slog.Warn("database role bypasses row-level security; connect as a role without SUPERUSER or BYPASSRLS to isolate tenants")
+	}
 
+	outboxRepo := repo.NewOutboxRepository(pool)
+	relayDone := startOutboxRelay(ctx, outboxRepo)
+
+	txManager := repo.NewTxManager(pool)
+	httpHandler := buildHTTPHandler(repo.NewBookRepository(pool),
+		service.WithOutbox(txManager, outboxRepo),
+		service.WithRevisions(txManager, repo.NewRevisionRepository(pool)),
+		service.WithStockLedger(txManager, repo.NewStockLedgerRepository(pool)),
+		service.WithAuthors(repo.NewAuthorRepository(pool)),
+		service.WithCategories(repo.NewCategoryRepository(pool)),
+		service.WithTags(repo.NewTagRepository(pool)),
+		service.WithFXRates(repo.NewFXRateRepository(pool), rounding),
+	)
+	err = serve(ctx, port, httpHandler)
+	<-relayDone
+	return err
+}
+
+// startOutboxRelay runs the outbox relay in the background unless
+// OUTBOX_RELAY=external, in which case cmd/outbox_relay is expected to drain
+// the outbox. The returned channel is closed once the relay has stopped.
+//
+// The relay publishes directly rather than through the async publisher used
+// by the memory store: it deletes a row once the publisher returns, so a
+// queue in between would lose the events still queued at shutdown. Retries
+// and the events left at shutdown are kept in the outbox table instead.
+func startOutboxRelay(ctx context.Context, store outbox.Store) <-chan struct{} {
+	done := make(chan struct{})
+
+	mode := envOrDefault("OUTBOX_RELAY", "inprocess")
+	if mode != "inprocess" {
+		slog.Info("outbox relay not started in-process", "mode", mode)
+		close(done)
+		return done
+	}
+
+	publisher := configureBookEventPublisher(ctx)
+	if publisher == nil {
+		slog.Warn("no book event publisher configured; book events stay in the outbox until a relay can deliver them")
+		close(done)
+		return done
+	}
+
+	go func() {
+		defer close(done)
+		_ = outbox.NewRelay(store, publisher, slog.Default()).Run(ctx)
+	}()
+	return done
+}
+
+// startAsyncPublisher publishes through next in the background, configured
+// by the EVENT_PUBLISH_* and EVENT_SPILL_FILE environment variables.
+func startAsyncPublisher(next service.BookEventPublisher) *notifications.AsyncBookEventPublisher {
+	return notifications.NewAsyncBookEventPublisher(next, slog.Default(),
+		notifications.WithWorkers(envInt("EVENT_PUBLISH_WORKERS")),
+		notifications.WithQueueSize(envInt("EVENT_PUBLISH_QUEUE_SIZE")),
+		notifications.WithMaxAttempts(envInt("EVENT_PUBLISH_MAX_ATTEMPTS")),
+		notifications.WithSpillFile(strings.TrimSpace(os.Getenv("EVENT_SPILL_FILE"))),
+	)
+}
+
+// drainAsyncPublisher gives queued events a bounded time to be published
+// once the server has stopped; the rest are spilled.
+func drainAsyncPublisher(publisher *notifications.AsyncBookEventPublisher) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := publisher.Close(ctx); err != nil {
+		slog.Warn("book event queue not drained in time; remaining events were spilled", "error", err)
+		return
+	}
+	slog.Info("book event queue drained")
+}
+
+// serve runs the HTTP server until ctx is cancelled and then shuts it down
+// gracefully.
+func serve(ctx context.Context, port string, handler http.Handler) error {
 	server := &http.Server{
 		Addr:         ":" + port,
-		Handler:      httpHandler,
+		Handler:      handler,
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
@@ -110,30 +280,107 @@ func envOrDefault(key, defaultValue string) string {
 	return v
 }
 
+// envInt returns the integer in key, or 0 when it is unset or invalid.
+func envInt(key string) int {
+	v := strings.TrimSpace(os.Getenv(key))
+	if v == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		slog.Warn("ignoring invalid integer environment variable", "envVar", key)
+		return 0
+	}
+	return n
+}
+
+func envBool(key string) bool {
+	v, err := strconv.ParseBool(envOrDefault(key, "false"))
+	if err != nil {
+		slog.Warn("ignoring invalid boolean environment variable", "envVar", key)
+		return false
+	}
+	return v
+}
+
 func applyMigrations(ctx context.Context, pool *pgxpool.Pool) error {
-	entries, err := migrations.Files.ReadDir(".")
+	migrator, err := migrations.NewMigrator(pool, migrations.Files, slog.Default())
 	if err != nil {
-		return fmt.Errorf("read migrations: %w", err)
+		return err
 	}
+	_, err = migrator.Up(ctx)
+	return err
+}
 
-	var files []string
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-		files = append(files, entry.Name())
+func printMigrationStatus(ctx context.Context, pool *pgxpool.Pool, out io.Writer) error {
+	migrator, err := migrations.NewMigrator(pool, migrations.Files, slog.Default())
+	if err != nil {
+		return err
+	}
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return fmt.Errorf("migration status: %w", err)
 	}
-	sort.Strings(files)
 
-	for _, name := range files {
-		sqlBytes, err := migrations.Files.ReadFile(name)
-		if err != nil {
-			return fmt.Errorf("read migration %s: %w", name, err)
-		}
-		if _, err := pool.Exec(ctx, string(sqlBytes)); err != nil {
-			return fmt.Errorf("exec migration %s: %w", name, err)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATE\tAPPLIED AT")
+	for _, status := range statuses {
+		appliedAt := "-"
+		if !status.AppliedAt.IsZero() {
+			appliedAt = status.AppliedAt.UTC().Format(time.RFC3339)
 		}
+		fmt.Fprintf(w, "%03d\t%s\t%s\t%s\n", status.Version, status.Name, status.State, appliedAt)
+	}
+	return w.Flush()
+}
+
+// runMigrationPlan prints the plan for -migrate-down or -migrate-to and only
+// executes it when confirm is set, so operators always see a dry run first.
+func runMigrationPlan(ctx context.Context, pool *pgxpool.Pool, out io.Writer, downSteps int, toVersion int64, confirm bool) error {
+	migrator, err := migrations.NewMigrator(pool, migrations.Files, slog.Default())
+	if err != nil {
+		return err
+	}
+
+	var plan migrations.Plan
+	if downSteps > 0 {
+		plan, err = migrator.PlanDown(ctx, downSteps)
+	} else {
+		plan, err = migrator.PlanTo(ctx, toVersion)
+	}
+	if err != nil {
+		return fmt.Errorf("plan migrations: %w", err)
+	}
+
+	if len(plan.Migrations) == 0 {
+		fmt.Fprintln(out, "Nothing to do; the schema is already at the requested version.")
+		return nil
+	}
+
+	fmt.Fprintf(out, "Plan (%s, %d migration(s)):\n", plan.Direction, len(plan.Migrations))
+	for _, migration := range plan.Migrations {
+		fmt.Fprintf(out, "  %s %03d_%s\n", plan.Direction, migration.Version, migration.Name)
+	}
+
+	if !confirm {
+		fmt.Fprintln(out, "Dry run only; re-run with -confirm to execute this plan.")
+		return nil
+	}
+
+	if _, err := migrator.Execute(ctx, plan); err != nil {
+		return fmt.Errorf("execute migration plan: %w", err)
+	}
+	fmt.Fprintln(out, "Plan executed.")
+	return nil
+}
+
+func purgeDeletedBooks(ctx context.Context, pool *pgxpool.Pool, days int) error {
+	bookService := service.NewBookService(repo.NewBookRepository(pool))
+	purged, err := bookService.PurgeDeletedBooks(ctx, time.Duration(days)*24*time.Hour)
+	if err != nil {
+		return fmt.Errorf("purge deleted books: %w", err)
 	}
+	slog.Info("purged deleted books", "count", purged, "olderThanDays", days, "tenant", domain.TenantFromContext(ctx))
 	return nil
 }
 
@@ -143,19 +390,30 @@ type healthOutput struct {
 	}
 }
 
-func buildHTTPHandler(pool *pgxpool.Pool) http.Handler {
-	bookRepo := repo.NewBookRepository(pool)
-	bookService := service.NewBookService(bookRepo, buildBookServiceOptions(context.Background())...)
-	bookHandler := handlers.NewBookHandler(bookService)
+func buildHTTPHandler(bookRepo service.BookRepository, opts ...service.BookServiceOption) http.Handler {
+	bookService := service.NewBookService(bookRepo, opts...)
+	bookHandler := handlers.NewBookHandler(bookService,
+		handlers.WithIfMatchRequired(envBool("REQUIRE_IF_MATCH")),
+	)
 
+	problem.Install()
 	router := mux.NewRouter()
 	config := huma.DefaultConfig("Book API", "1.0.0")
+	config.Transformers = append(config.Transformers, problem.Transform)
 	api := humamux.New(router, config)
 
 	registerHealthRoutes(api)
 	handlers.RegisterBookRoutes(api, bookHandler)
-
-	return middleware.CORS(middleware.Logger(router))
+	handlers.RegisterAuthorRoutes(api, bookHandler)
+	handlers.RegisterCategoryRoutes(api, bookHandler)
+	handlers.RegisterTagRoutes(api, bookHandler)
+	handlers.RegisterFXRateRoutes(api, bookHandler)
+
+	tenants := middleware.Tenant(middleware.TenantOptions{
+		TokenSecret: []byte(os.Getenv("TENANT_TOKEN_SECRET")),
+		Required:    envBool("REQUIRE_TENANT"),
+	})
+	return middleware.CORS(middleware.RequestID(middleware.Logger(tenants(middleware.Actor(router)))))
 }
 
 func registerHealthRoutes(api huma.API) {
@@ -172,56 +430,16 @@ func registerHealthRoutes(api huma.API) {
 	})
 }
 
-func buildBookServiceOptions(ctx context.Context) []service.BookServiceOption {
-	var opts []service.BookServiceOption
-
-	publisher, err := configureSNSBookPublisher(ctx)
+// configureBookEventPublisher returns the SNS publisher, or nil when book
+// events are not published.
+func configureBookEventPublisher(ctx context.Context) service.BookEventPublisher {
+	publisher, err := notifications.NewSNSBookEventPublisherFromEnv(ctx, slog.Default())
 	if err != nil {
 		slog.Error("failed to configure SNS publisher for book events", "error", err)
-	} else if publisher != nil {
-		opts = append(opts, service.WithBookEventPublisher(publisher))
-	}
-
-	return opts
-}
-
-func configureSNSBookPublisher(ctx context.Context) (service.BookEventPublisher, error) {
-	topicARN := strings.TrimSpace(os.Getenv("SNS_TOPIC_ARN"))
-	if topicARN == "" {
-		slog.Warn("SNS_TOPIC_ARN not set; book created events will not be published",
-			"envVar", "SNS_TOPIC_ARN",
-		)
-		return nil, nil
-	}
-
-	region, err := snsRegionFromARN(topicARN)
-	if err != nil {
-		slog.Error("unable to derive AWS region from SNS topic ARN; book created events disabled",
-			"topicArn", topicARN,
-			"error", err,
-		)
-		return nil, err
-	}
-
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
-	if err != nil {
-		return nil, fmt.Errorf("load AWS config: %w", err)
-	}
-
-	client := sns.NewFromConfig(cfg)
-	return notifications.NewSNSBookEventPublisher(client, topicARN, slog.Default()), nil
-}
-
-func snsRegionFromARN(topicARN string) (string, error) {
-	parts := strings.Split(topicARN, ":")
-	if len(parts) < 6 || parts[0] != "arn" {
-		return "", fmt.Errorf("invalid SNS topic ARN: %s", topicARN)
+		return nil
 	}
-
-	region := strings.TrimSpace(parts[3])
-	if region == "" {
-		return "", fmt.Errorf("missing region in SNS topic ARN: %s", topicARN)
+	if publisher == nil {
+		return nil
 	}
-
-	return region, nil
+	return publisher
 }
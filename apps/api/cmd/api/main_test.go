@@ -3,17 +3,23 @@ package main
 import (
 	"bytes"
 	"context"
+	"encoding/csv"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/stretchr/testify/require"
+
+	"github.com/example/bookapi/internal/domain"
+	"github.com/example/bookapi/internal/repo"
+	"github.com/example/bookapi/internal/service"
 )
 
 func TestBookCRUDIntegration(t *testing.T) {
@@ -23,7 +29,7 @@ func TestBookCRUDIntegration(t *testing.T) {
 	}
 
 	ctx := context.Background()
-	pool, err := pgxpool.New(ctx, dsn)
+	pool, err := repo.NewPool(ctx, dsn)
 	if err != nil {
 		t.Skipf("unable to create pool for TEST_DB_DSN: %v", err)
 	}
@@ -36,10 +42,158 @@ func TestBookCRUDIntegration(t *testing.T) {
 	require.NoError(t, applyMigrations(ctx, pool))
 
 	t.Cleanup(func() {
-		_, _ = pool.Exec(context.Background(), "TRUNCATE TABLE books")
+		_, _ = pool.Exec(context.Background(), "TRUNCATE TABLE books, outbox, book_revisions, stock_ledger, book_authors, authors, book_categories, book_tags, categories, tags, book_prices, fx_rates")
+	})
+
+	txManager := repo.NewTxManager(pool)
+	handler := buildHTTPHandler(repo.NewBookRepository(pool),
+		service.WithOutbox(txManager, repo.NewOutboxRepository(pool)),
+		service.WithRevisions(txManager, repo.NewRevisionRepository(pool)),
+		service.WithStockLedger(txManager, repo.NewStockLedgerRepository(pool)),
+		service.WithAuthors(repo.NewAuthorRepository(pool)),
+		service.WithCategories(repo.NewCategoryRepository(pool)),
+		service.WithTags(repo.NewTagRepository(pool)),
+		service.WithFXRates(repo.NewFXRateRepository(pool), ""),
+	)
+	testBookCRUD(t, handler)
+
+	var pending int
+	require.NoError(t, pool.QueryRow(ctx, "SELECT count(*) FROM outbox WHERE event_type = 'book.created'").Scan(&pending))
+	require.Equal(t, 4, pending)
+	testSearchEscapesMarkup(t, handler)
+
+	bypasses, err := repo.BypassesRowSecurity(ctx, pool)
+	require.NoError(t, err)
+	if bypasses {
+		t.Log("skipping tenant isolation: the TEST_DB_DSN role bypasses row-level security")
+		return
+	}
+	testTenantIsolation(t, handler)
+}
+
+// testTenantIsolation checks that the catalog of one tenant is invisible to
+// and independent of the others.
+func testTenantIsolation(t *testing.T, handler http.Handler) {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	do := func(method, path, tenant string, payload any) *http.Response {
+		var body io.Reader
+		if payload != nil {
+			data, err := json.Marshal(payload)
+			require.NoError(t, err)
+			body = bytes.NewReader(data)
+		}
+		req, err := http.NewRequest(method, server.URL+path, body)
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Tenant-ID", tenant)
+		resp, err := server.Client().Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			_ = resp.Body.Close()
+		})
+		return resp
+	}
+
+	book := map[string]any{
+		"title":    "Tenancy",
+		"author":   "Shop Keeper",
+		"isbn":     "9780306406157",
+		"tags":     []string{"shared"},
+		"price":    "5.00",
+		"currency": "EUR",
+		"stock":    1,
+	}
+	var created bookResponse
+	createResp := do(http.MethodPost, "/books", "shop-a", book)
+	require.Equal(t, http.StatusCreated, createResp.StatusCode)
+	require.NoError(t, json.NewDecoder(createResp.Body).Decode(&created))
+
+	require.Equal(t, http.StatusOK, do(http.MethodGet, "/books/"+created.ID, "shop-a", nil).StatusCode)
+	require.Equal(t, http.StatusNotFound, do(http.MethodGet, "/books/"+created.ID, "shop-b", nil).StatusCode)
+	require.Equal(t, http.StatusNotFound, do(http.MethodGet, "/books/"+created.ID+"/history", "shop-b", nil).StatusCode)
+
+	var listed struct {
+		Books []bookResponse `json:"books"`
+	}
+	listResp := do(http.MethodGet, "/books", "shop-b", nil)
+	require.Equal(t, http.StatusOK, listResp.StatusCode)
+	require.NoError(t, json.NewDecoder(listResp.Body).Decode(&listed))
+	require.Empty(t, listed.Books)
+
+	// ISBNs, authors and tags are per tenant, so another tenant can reuse
+	// them without conflicts.
+	require.Equal(t, http.StatusCreated, do(http.MethodPost, "/books", "shop-b", book).StatusCode)
+}
+
+func TestBookCRUDInMemory(t *testing.T) {
+	books := repo.NewMemoryBookRepository()
+	handler := buildHTTPHandler(books,
+		service.WithRevisions(nil, repo.NewMemoryRevisionRepository()),
+		service.WithStockLedger(nil, repo.NewMemoryStockLedgerRepository()),
+		service.WithAuthors(books.Authors()),
+		service.WithCategories(books.Categories()),
+		service.WithTags(books.Tags()),
+		service.WithFXRates(repo.NewMemoryFXRateRepository(), ""),
+	)
+	testBookCRUD(t, handler)
+	testSearchEscapesMarkup(t, handler)
+	testTenantIsolation(t, handler)
+}
+
+// testSearchEscapesMarkup checks that markup stored in a book is escaped in
+// search snippets, whose only tags are the highlights.
+func testSearchEscapesMarkup(t *testing.T, handler http.Handler) {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	payload, err := json.Marshal(map[string]any{
+		"title":    `Quokkas <img src=x onerror="alert(1)"> & Friends`,
+		"author":   "Mallory",
+		"price":    "9.99",
+		"currency": "USD",
+		"stock":    1,
 	})
+	require.NoError(t, err)
+	createResp, err := server.Client().Post(server.URL+"/books", "application/json", bytes.NewReader(payload))
+	require.NoError(t, err)
+	defer func() {
+		_ = createResp.Body.Close()
+	}()
+	require.Equal(t, http.StatusCreated, createResp.StatusCode)
+
+	searchResp, err := server.Client().Get(server.URL + "/books/search?q=quokkas")
+	require.NoError(t, err)
+	defer func() {
+		_ = searchResp.Body.Close()
+	}()
+	require.Equal(t, http.StatusOK, searchResp.StatusCode)
+
+	var found struct {
+		Results []struct {
+			Snippet string `json:"snippet"`
+		} `json:"results"`
+	}
+	require.NoError(t, json.NewDecoder(searchResp.Body).Decode(&found))
+	require.Len(t, found.Results, 1)
+	snippet := found.Results[0].Snippet
+	require.Contains(t, snippet, "<mark>Quokkas</mark>")
+	require.Contains(t, snippet, "&lt;img")
+	require.Contains(t, snippet, "&amp;")
+	require.NotContains(t, snippet, "<img")
+}
+
+// testBookCRUD drives the full book lifecycle through the HTTP API so every
+// store implementation is held to the same behaviour.
+func testBookCRUD(t *testing.T, handler http.Handler) {
+	t.Helper()
 
-	server := httptest.NewServer(buildHTTPHandler(pool))
+	server := httptest.NewServer(handler)
 	defer server.Close()
 
 	client := server.Client()
@@ -47,7 +201,7 @@ func TestBookCRUDIntegration(t *testing.T) {
 	createPayload := map[string]any{
 		"title":    "Integration Testing with Go",
 		"author":   "John Doe",
-		"price":    24.99,
+		"price":    "24.99",
 		"currency": "usd",
 		"stock":    3,
 	}
@@ -68,6 +222,7 @@ func TestBookCRUDIntegration(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "Integration Testing with Go", created.Title)
 	require.Equal(t, "USD", created.Currency)
+	require.Equal(t, "24.99", created.Price)
 	require.True(t, created.CreatedAt.After(time.Time{}))
 
 	getResp, err := client.Get(server.URL + "/books/" + created.ID)
@@ -77,6 +232,7 @@ func TestBookCRUDIntegration(t *testing.T) {
 	}()
 
 	require.Equal(t, http.StatusOK, getResp.StatusCode)
+	require.Equal(t, `"1"`, getResp.Header.Get("ETag"))
 
 	var fetched bookResponse
 	require.NoError(t, json.NewDecoder(getResp.Body).Decode(&fetched))
@@ -98,7 +254,7 @@ func TestBookCRUDIntegration(t *testing.T) {
 	updatePayload := map[string]any{
 		"title":    "Integration Testing with Go - Second Edition",
 		"author":   "John Doe",
-		"price":    29.99,
+		"price":    "29.9",
 		"currency": "EUR",
 		"stock":    7,
 	}
@@ -108,6 +264,8 @@ func TestBookCRUDIntegration(t *testing.T) {
 	updateReq, err := http.NewRequest(http.MethodPut, server.URL+"/books/"+created.ID, bytes.NewReader(updateBody))
 	require.NoError(t, err)
 	updateReq.Header.Set("Content-Type", "application/json")
+	updateReq.Header.Set("If-Match", getResp.Header.Get("ETag"))
+	updateReq.Header.Set("X-Actor", "integration-test")
 
 	updateResp, err := client.Do(updateReq)
 	require.NoError(t, err)
@@ -115,11 +273,38 @@ func TestBookCRUDIntegration(t *testing.T) {
 		_ = updateResp.Body.Close()
 	}()
 	require.Equal(t, http.StatusOK, updateResp.StatusCode)
+	require.Equal(t, `"2"`, updateResp.Header.Get("ETag"))
+
+	staleReq, err := http.NewRequest(http.MethodPut, server.URL+"/books/"+created.ID, bytes.NewReader(updateBody))
+	require.NoError(t, err)
+	staleReq.Header.Set("Content-Type", "application/json")
+	staleReq.Header.Set("If-Match", getResp.Header.Get("ETag"))
+	staleResp, err := client.Do(staleReq)
+	require.NoError(t, err)
+	defer func() {
+		_ = staleResp.Body.Close()
+	}()
+	require.Equal(t, http.StatusPreconditionFailed, staleResp.StatusCode)
+
+	malformedReq, err := http.NewRequest(http.MethodPut, server.URL+"/books/"+created.ID, bytes.NewReader(updateBody))
+	require.NoError(t, err)
+	malformedReq.Header.Set("Content-Type", "application/json")
+	malformedReq.Header.Set("If-Match", `W/"2"`)
+	malformedResp, err := client.Do(malformedReq)
+	require.NoError(t, err)
+	defer func() {
+		_ = malformedResp.Body.Close()
+	}()
+	require.Equal(t, http.StatusBadRequest, malformedResp.StatusCode)
+	var malformed problemResponse
+	require.NoError(t, json.NewDecoder(malformedResp.Body).Decode(&malformed))
+	require.Equal(t, []violationResponse{{Field: "If-Match", Code: "invalid_format", Message: "must be * or a single strong entity tag"}}, malformed.Violations)
 
 	var updated bookResponse
 	require.NoError(t, json.NewDecoder(updateResp.Body).Decode(&updated))
 	require.Equal(t, "Integration Testing with Go - Second Edition", updated.Title)
 	require.Equal(t, "EUR", updated.Currency)
+	require.Equal(t, "29.90", updated.Price)
 	require.Greater(t, updated.Stock, created.Stock)
 
 	deleteReq, err := http.NewRequest(http.MethodDelete, server.URL+"/books/"+created.ID, nil)
@@ -137,10 +322,457 @@ func TestBookCRUDIntegration(t *testing.T) {
 		_ = finalGetResp.Body.Close()
 	}()
 	require.Equal(t, http.StatusNotFound, finalGetResp.StatusCode)
+
+	restoreResp, err := client.Post(server.URL+"/books/"+created.ID+":restore", "application/json", nil)
+	require.NoError(t, err)
+	defer func() {
+		_ = restoreResp.Body.Close()
+	}()
+	require.Equal(t, http.StatusOK, restoreResp.StatusCode)
+	require.Equal(t, `"4"`, restoreResp.Header.Get("ETag"))
+
+	historyReq, err := http.NewRequest(http.MethodGet, server.URL+"/books/"+created.ID+"/history?limit=3", nil)
+	require.NoError(t, err)
+	historyResp, err := client.Do(historyReq)
+	require.NoError(t, err)
+	defer func() {
+		_ = historyResp.Body.Close()
+	}()
+	require.Equal(t, http.StatusOK, historyResp.StatusCode)
+
+	var history struct {
+		Revisions []struct {
+			Version int64  `json:"version"`
+			Action  string `json:"action"`
+			Actor   string `json:"actor"`
+			Changes []struct {
+				Field string `json:"field"`
+			} `json:"changes"`
+		} `json:"revisions"`
+		NextCursor string `json:"nextCursor"`
+	}
+	require.NoError(t, json.NewDecoder(historyResp.Body).Decode(&history))
+	require.Len(t, history.Revisions, 3)
+	require.Equal(t, "restore", history.Revisions[0].Action)
+	require.Equal(t, "update", history.Revisions[2].Action)
+	require.Equal(t, "integration-test", history.Revisions[2].Actor)
+	require.Equal(t, "anonymous", history.Revisions[1].Actor)
+	require.Len(t, history.Revisions[2].Changes, 4)
+	require.NotEmpty(t, history.NextCursor)
+
+	batchBody, err := json.Marshal(map[string]any{
+		"items": []map[string]any{
+			{"title": "Batch One", "author": "Jane Roe", "isbn": "0-306-40615-2", "price": "10.00", "currency": "USD", "stock": 1},
+			{"title": "Batch Two", "author": "Jane Roe", "price": "1.5", "currency": "JPY", "stock": 1},
+			{"title": "Batch Three", "author": "Jane Roe", "price": "1.250", "currency": "KWD", "stock": 0},
+		},
+	})
+	require.NoError(t, err)
+	batchResp, err := client.Post(server.URL+"/books:batchCreate", "application/json", bytes.NewReader(batchBody))
+	require.NoError(t, err)
+	defer func() {
+		_ = batchResp.Body.Close()
+	}()
+	require.Equal(t, http.StatusOK, batchResp.StatusCode)
+
+	type batchResponse struct {
+		Created int `json:"created"`
+		Failed  int `json:"failed"`
+		Results []struct {
+			Index  int                `json:"index"`
+			Book   *bookResponse      `json:"book"`
+			Errors []domain.Violation `json:"errors"`
+		} `json:"results"`
+	}
+	var batch batchResponse
+	require.NoError(t, json.NewDecoder(batchResp.Body).Decode(&batch))
+	require.Equal(t, 2, batch.Created)
+	require.Equal(t, 1, batch.Failed)
+	require.Len(t, batch.Results, 3)
+	require.Equal(t, []domain.Violation{
+		{Field: "price", Code: domain.ViolationInvalidPrecision, Message: "must have at most 0 decimal places for JPY"},
+	}, batch.Results[1].Errors)
+	require.Equal(t, "1.250", batch.Results[2].Book.Price)
+
+	batchGetResp, err := client.Get(server.URL + "/books/" + batch.Results[2].Book.ID)
+	require.NoError(t, err)
+	defer func() {
+		_ = batchGetResp.Body.Close()
+	}()
+	require.Equal(t, http.StatusOK, batchGetResp.StatusCode)
+
+	isbnResp, err := client.Get(server.URL + "/books/by-isbn/978-0-306-40615-7")
+	require.NoError(t, err)
+	defer func() {
+		_ = isbnResp.Body.Close()
+	}()
+	require.Equal(t, http.StatusOK, isbnResp.StatusCode)
+	var byISBN bookResponse
+	require.NoError(t, json.NewDecoder(isbnResp.Body).Decode(&byISBN))
+	require.Equal(t, batch.Results[0].Book.ID, byISBN.ID)
+	require.Equal(t, "9780306406157", byISBN.ISBN)
+
+	takenBody, err := json.Marshal(map[string]any{
+		"items": []map[string]any{
+			{"title": "Batch Four", "author": "John Doe", "isbn": "9780306406157", "price": "10.00", "currency": "USD", "stock": 1},
+			{"title": "Batch Five", "author": "John Doe", "price": "10.00", "currency": "USD", "stock": 1},
+		},
+	})
+	require.NoError(t, err)
+	takenResp, err := client.Post(server.URL+"/books:batchCreate", "application/json", bytes.NewReader(takenBody))
+	require.NoError(t, err)
+	defer func() {
+		_ = takenResp.Body.Close()
+	}()
+	require.Equal(t, http.StatusOK, takenResp.StatusCode)
+	var taken batchResponse
+	require.NoError(t, json.NewDecoder(takenResp.Body).Decode(&taken))
+	require.Equal(t, 1, taken.Created)
+	require.Equal(t, 1, taken.Failed)
+	require.Equal(t, []domain.Violation{
+		{Field: "isbn", Code: domain.ViolationDuplicate, Message: "another book has this ISBN"},
+	}, taken.Results[0].Errors)
+	require.NotNil(t, taken.Results[1].Book)
+
+	badISBNResp, err := client.Get(server.URL + "/books/by-isbn/9780306406158")
+	require.NoError(t, err)
+	defer func() {
+		_ = badISBNResp.Body.Close()
+	}()
+	require.Equal(t, http.StatusBadRequest, badISBNResp.StatusCode)
+
+	duplicateBody := strings.NewReader(`{"title": "Copy", "author": "Jane Roe", "isbn": "9780306406157", "price": "1.00", "currency": "USD", "stock": 0}`)
+	duplicateResp, err := client.Post(server.URL+"/books", "application/json", duplicateBody)
+	require.NoError(t, err)
+	defer func() {
+		_ = duplicateResp.Body.Close()
+	}()
+	require.Equal(t, http.StatusConflict, duplicateResp.StatusCode)
+
+	exportResp, err := client.Get(server.URL + "/books/export?author=jane&sort=title")
+	require.NoError(t, err)
+	defer func() {
+		_ = exportResp.Body.Close()
+	}()
+	require.Equal(t, http.StatusOK, exportResp.StatusCode)
+	require.Equal(t, "text/csv; charset=utf-8", exportResp.Header.Get("Content-Type"))
+	require.Regexp(t, `^attachment; filename="books-\d{8}T\d{6}Z\.csv"$`, exportResp.Header.Get("Content-Disposition"))
+	rows, err := csv.NewReader(exportResp.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	require.Equal(t, []string{"id", "title", "author", "isbn", "price", "currency", "stock", "createdAt", "updatedAt", "version", "deletedAt"}, rows[0])
+	require.Equal(t, "Batch One", rows[1][1])
+	require.Equal(t, "9780306406157", rows[1][3])
+	require.Equal(t, "1.250", rows[2][4])
+
+	ndjsonResp, err := client.Get(server.URL + "/books/export?format=ndjson&includeDeleted=true")
+	require.NoError(t, err)
+	defer func() {
+		_ = ndjsonResp.Body.Close()
+	}()
+	require.Equal(t, http.StatusOK, ndjsonResp.StatusCode)
+	require.Equal(t, "application/x-ndjson", ndjsonResp.Header.Get("Content-Type"))
+	decoder := json.NewDecoder(ndjsonResp.Body)
+	exported := 0
+	for decoder.More() {
+		var book bookResponse
+		require.NoError(t, decoder.Decode(&book))
+		exported++
+	}
+	require.Equal(t, 4, exported)
+
+	badExportResp, err := client.Get(server.URL + "/books/export?minPrice=20&maxPrice=10")
+	require.NoError(t, err)
+	defer func() {
+		_ = badExportResp.Body.Close()
+	}()
+	require.Equal(t, http.StatusBadRequest, badExportResp.StatusCode)
+
+	adjustmentsURL := server.URL + "/books/" + batch.Results[0].Book.ID + "/stock/adjustments"
+	adjustStock := func(delta int, reason string) *http.Response {
+		body, err := json.Marshal(map[string]any{"delta": delta, "reason": reason, "reference": "order-42"})
+		require.NoError(t, err)
+		resp, err := client.Post(adjustmentsURL, "application/json", bytes.NewReader(body))
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			_ = resp.Body.Close()
+		})
+		return resp
+	}
+
+	saleResp := adjustStock(-1, "sale")
+	require.Equal(t, http.StatusCreated, saleResp.StatusCode)
+	require.Equal(t, `"2"`, saleResp.Header.Get("ETag"))
+	var adjustment struct {
+		Delta      int    `json:"delta"`
+		Reason     string `json:"reason"`
+		Reference  string `json:"reference"`
+		StockAfter int    `json:"stockAfter"`
+	}
+	require.NoError(t, json.NewDecoder(saleResp.Body).Decode(&adjustment))
+	require.Equal(t, -1, adjustment.Delta)
+	require.Equal(t, "order-42", adjustment.Reference)
+	require.Equal(t, 0, adjustment.StockAfter)
+
+	require.Equal(t, http.StatusConflict, adjustStock(-1, "sale").StatusCode)
+	require.Equal(t, http.StatusBadRequest, adjustStock(2, "sale").StatusCode)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			body := strings.NewReader(`{"delta": 2, "reason": "restock"}`)
+			resp, err := client.Post(adjustmentsURL, "application/json", body)
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+		}()
+	}
+	wg.Wait()
+
+	ledgerResp, err := client.Get(adjustmentsURL + "?limit=10")
+	require.NoError(t, err)
+	defer func() {
+		_ = ledgerResp.Body.Close()
+	}()
+	require.Equal(t, http.StatusOK, ledgerResp.StatusCode)
+	var ledger struct {
+		Adjustments []struct {
+			Reason     string `json:"reason"`
+			StockAfter int    `json:"stockAfter"`
+		} `json:"adjustments"`
+	}
+	require.NoError(t, json.NewDecoder(ledgerResp.Body).Decode(&ledger))
+	require.Len(t, ledger.Adjustments, 6)
+	require.Equal(t, "restock", ledger.Adjustments[0].Reason)
+	require.Equal(t, 10, ledger.Adjustments[0].StockAfter)
+	require.Equal(t, "sale", ledger.Adjustments[5].Reason)
+
+	postJSON := func(path string, payload any) *http.Response {
+		body, err := json.Marshal(payload)
+		require.NoError(t, err)
+		resp, err := client.Post(server.URL+path, "application/json", bytes.NewReader(body))
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			_ = resp.Body.Close()
+		})
+		return resp
+	}
+	var editor authorResponse
+	editorResp := postJSON("/authors", map[string]any{"name": "Ed Itor"})
+	require.Equal(t, http.StatusCreated, editorResp.StatusCode)
+	require.NoError(t, json.NewDecoder(editorResp.Body).Decode(&editor))
+
+	authorsResp, err := client.Get(server.URL + "/authors?name=doe")
+	require.NoError(t, err)
+	defer func() {
+		_ = authorsResp.Body.Close()
+	}()
+	require.Equal(t, http.StatusOK, authorsResp.StatusCode)
+	var authors struct {
+		Authors []authorResponse `json:"authors"`
+	}
+	require.NoError(t, json.NewDecoder(authorsResp.Body).Decode(&authors))
+	require.Len(t, authors.Authors, 1)
+	doe := authors.Authors[0]
+	require.Equal(t, "John Doe", doe.Name)
+
+	var anthology bookResponse
+	anthologyResp := postJSON("/books", map[string]any{
+		"title": "Collected Tests",
+		"authors": []map[string]any{
+			{"authorId": doe.ID},
+			{"authorId": editor.ID, "role": "editor"},
+		},
+		"price":    "5.00",
+		"currency": "USD",
+		"stock":    0,
+	})
+	require.Equal(t, http.StatusCreated, anthologyResp.StatusCode)
+	require.NoError(t, json.NewDecoder(anthologyResp.Body).Decode(&anthology))
+	require.Equal(t, "John Doe", anthology.Author)
+	require.Len(t, anthology.Authors, 2)
+	require.Equal(t, "editor", anthology.Authors[1].Role)
+
+	unknownResp := postJSON("/books", map[string]any{
+		"title":    "Ghost Written",
+		"authors":  []map[string]any{{"authorId": uuid.NewString()}},
+		"price":    "1.00",
+		"currency": "USD",
+		"stock":    0,
+	})
+	require.Equal(t, http.StatusBadRequest, unknownResp.StatusCode)
+
+	editorBooksResp, err := client.Get(server.URL + "/authors/" + editor.ID + "/books")
+	require.NoError(t, err)
+	defer func() {
+		_ = editorBooksResp.Body.Close()
+	}()
+	require.Equal(t, http.StatusOK, editorBooksResp.StatusCode)
+	var editorBooks struct {
+		Books []bookResponse `json:"books"`
+	}
+	require.NoError(t, json.NewDecoder(editorBooksResp.Body).Decode(&editorBooks))
+	require.Len(t, editorBooks.Books, 1)
+	require.Equal(t, anthology.ID, editorBooks.Books[0].ID)
+
+	deleteAuthorReq, err := http.NewRequest(http.MethodDelete, server.URL+"/authors/"+editor.ID, nil)
+	require.NoError(t, err)
+	deleteAuthorResp, err := client.Do(deleteAuthorReq)
+	require.NoError(t, err)
+	defer func() {
+		_ = deleteAuthorResp.Body.Close()
+	}()
+	require.Equal(t, http.StatusConflict, deleteAuthorResp.StatusCode)
+
+	missingAuthorResp, err := client.Get(server.URL + "/authors/" + uuid.NewString())
+	require.NoError(t, err)
+	defer func() {
+		_ = missingAuthorResp.Body.Close()
+	}()
+	require.Equal(t, http.StatusNotFound, missingAuthorResp.StatusCode)
+
+	var fiction, fantasy categoryResponse
+	fictionResp := postJSON("/categories", map[string]any{"name": "Fiction"})
+	require.Equal(t, http.StatusCreated, fictionResp.StatusCode)
+	require.NoError(t, json.NewDecoder(fictionResp.Body).Decode(&fiction))
+	fantasyResp := postJSON("/categories", map[string]any{"name": "Fantasy", "parentId": fiction.ID})
+	require.Equal(t, http.StatusCreated, fantasyResp.StatusCode)
+	require.NoError(t, json.NewDecoder(fantasyResp.Body).Decode(&fantasy))
+	require.Equal(t, fiction.ID, fantasy.ParentID)
+
+	var hobbit bookResponse
+	hobbitResp := postJSON("/books", map[string]any{
+		"title":       "The Hobbit",
+		"author":      "J.R.R. Tolkien",
+		"categoryIds": []string{fantasy.ID},
+		"tags":        []string{"Dragons", "classic"},
+		"price":       "10.00",
+		"currency":    "USD",
+		"stock":       1,
+	})
+	require.Equal(t, http.StatusCreated, hobbitResp.StatusCode)
+	require.NoError(t, json.NewDecoder(hobbitResp.Body).Decode(&hobbit))
+	require.Len(t, hobbit.Categories, 1)
+	require.Equal(t, "Fantasy", hobbit.Categories[0].Name)
+	require.Equal(t, []string{"classic", "dragons"}, hobbit.Tags)
+
+	for _, filter := range []string{"category=" + fiction.ID, "tag=DRAGONS"} {
+		filteredResp, err := client.Get(server.URL + "/books?" + filter)
+		require.NoError(t, err)
+		var filtered struct {
+			Books []bookResponse `json:"books"`
+		}
+		require.Equal(t, http.StatusOK, filteredResp.StatusCode)
+		require.NoError(t, json.NewDecoder(filteredResp.Body).Decode(&filtered))
+		_ = filteredResp.Body.Close()
+		require.Len(t, filtered.Books, 1, filter)
+		require.Equal(t, hobbit.ID, filtered.Books[0].ID)
+	}
+
+	deleteCategoryReq, err := http.NewRequest(http.MethodDelete, server.URL+"/categories/"+fiction.ID, nil)
+	require.NoError(t, err)
+	deleteCategoryResp, err := client.Do(deleteCategoryReq)
+	require.NoError(t, err)
+	defer func() {
+		_ = deleteCategoryResp.Body.Close()
+	}()
+	require.Equal(t, http.StatusConflict, deleteCategoryResp.StatusCode)
+
+	var dune bookResponse
+	duneResp := postJSON("/books", map[string]any{
+		"title":    "Dune",
+		"author":   "Frank Herbert",
+		"price":    "20.00",
+		"currency": "USD",
+		"prices":   []map[string]string{{"price": "8.50", "currency": "gbp"}},
+		"stock":    2,
+	})
+	require.Equal(t, http.StatusCreated, duneResp.StatusCode)
+	require.NoError(t, json.NewDecoder(duneResp.Body).Decode(&dune))
+	require.Len(t, dune.Prices, 1)
+	require.Equal(t, "GBP", dune.Prices[0].Currency)
+
+	missingRateResp, err := client.Get(server.URL + "/books/" + dune.ID + "?currency=EUR")
+	require.NoError(t, err)
+	defer func() {
+		_ = missingRateResp.Body.Close()
+	}()
+	require.Equal(t, http.StatusBadRequest, missingRateResp.StatusCode)
+
+	ratesResp := postJSON("/fx-rates:load", map[string]any{"base": "USD", "rates": map[string]string{"EUR": "0.9"}})
+	require.Equal(t, http.StatusOK, ratesResp.StatusCode)
+
+	for currency, want := range map[string]quotedPriceResponse{
+		"gbp": {Price: "8.50", Currency: "GBP", Kind: "explicit"},
+		"EUR": {Price: "18.00", Currency: "EUR", Kind: "converted", Rate: "0.9"},
+	} {
+		quotedResp, err := client.Get(server.URL + "/books/" + dune.ID + "?currency=" + currency)
+		require.NoError(t, err)
+		var quoted bookResponse
+		require.Equal(t, http.StatusOK, quotedResp.StatusCode)
+		require.NoError(t, json.NewDecoder(quotedResp.Body).Decode(&quoted))
+		_ = quotedResp.Body.Close()
+		require.Equal(t, "20.00", quoted.Price)
+		require.NotNil(t, quoted.QuotedPrice, currency)
+		require.Equal(t, want, *quoted.QuotedPrice)
+	}
+}
+
+func TestProblemResponses(t *testing.T) {
+	handler := buildHTTPHandler(repo.NewMemoryBookRepository())
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	send := func(method, path, body, requestID string) (*http.Response, problemResponse) {
+		req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		if requestID != "" {
+			req.Header.Set("X-Request-ID", requestID)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+		require.Equal(t, "application/problem+json", resp.Header.Get("Content-Type"))
+		var problem problemResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&problem))
+		require.Equal(t, resp.StatusCode, problem.Status)
+		require.Equal(t, resp.Header.Get("X-Request-ID"), problem.RequestID)
+		return resp, problem
+	}
+
+	resp, problem := send(http.MethodPost, "/books", `{"title":"Dune","author":"Frank Herbert","price":"abc","currency":"euro","stock":1}`, "trace-1")
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	require.Equal(t, "validation_failed", problem.Code)
+	require.Equal(t, "trace-1", problem.RequestID)
+	require.Equal(t, []violationResponse{
+		{Field: "currency", Code: "invalid_format", Message: "must be ISO 4217 code"},
+		{Field: "price", Code: "invalid_format", Message: "must be a decimal number"},
+	}, problem.Violations)
+
+	resp, problem = send(http.MethodPost, "/books", `{"author":"Frank Herbert","price":"9.99","currency":"USD","stock":"many"}`, "")
+	require.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	require.Equal(t, "validation_failed", problem.Code)
+	require.NotEmpty(t, problem.RequestID)
+	codes := make(map[string]string)
+	for _, violation := range problem.Violations {
+		codes[violation.Field] = violation.Code
+	}
+	require.Equal(t, map[string]string{"title": "required", "stock": "invalid_type"}, codes)
+
+	resp, problem = send(http.MethodGet, "/books/"+uuid.NewString(), "", "")
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	require.Equal(t, "not_found", problem.Code)
+	require.Equal(t, "https://bookapi.example.com/problems/not_found", problem.Type)
+	require.Empty(t, problem.Violations)
 }
 
 func TestHealthEndpoint(t *testing.T) {
-	handler := buildHTTPHandler(nil)
+	handler := buildHTTPHandler(repo.NewMemoryBookRepository())
 	server := httptest.NewServer(handler)
 	defer server.Close()
 
@@ -153,7 +785,7 @@ func TestHealthEndpoint(t *testing.T) {
 }
 
 func TestOpenAPIExposed(t *testing.T) {
-	handler := buildHTTPHandler(nil)
+	handler := buildHTTPHandler(repo.NewMemoryBookRepository())
 	server := httptest.NewServer(handler)
 	defer server.Close()
 
@@ -167,12 +799,61 @@ func TestOpenAPIExposed(t *testing.T) {
 }
 
 type bookResponse struct {
-	ID        string    `json:"id"`
-	Title     string    `json:"title"`
-	Author    string    `json:"author"`
-	Price     float64   `json:"price"`
-	Currency  string    `json:"currency"`
-	Stock     int       `json:"stock"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
+	ID      string `json:"id"`
+	Title   string `json:"title"`
+	Author  string `json:"author"`
+	Authors []struct {
+		AuthorID string `json:"authorId"`
+		Name     string `json:"name"`
+		Role     string `json:"role"`
+	} `json:"authors"`
+	Categories []struct {
+		CategoryID string `json:"categoryId"`
+		Name       string `json:"name"`
+	} `json:"categories"`
+	Tags     []string `json:"tags"`
+	ISBN     string   `json:"isbn"`
+	Price    string   `json:"price"`
+	Currency string   `json:"currency"`
+	Prices   []struct {
+		Price    string `json:"price"`
+		Currency string `json:"currency"`
+	} `json:"prices"`
+	QuotedPrice *quotedPriceResponse `json:"quotedPrice"`
+	Stock       int                  `json:"stock"`
+	CreatedAt   time.Time            `json:"createdAt"`
+	UpdatedAt   time.Time            `json:"updatedAt"`
+	Version     int64                `json:"version"`
+}
+
+type problemResponse struct {
+	Type       string              `json:"type"`
+	Status     int                 `json:"status"`
+	Code       string              `json:"code"`
+	RequestID  string              `json:"requestId"`
+	Violations []violationResponse `json:"violations"`
+}
+
+type violationResponse struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type authorResponse struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type categoryResponse struct {
+	ID       string `json:"id"`
+	ParentID string `json:"parentId"`
+	Name     string `json:"name"`
+}
+
+type quotedPriceResponse struct {
+	Price    string `json:"price"`
+	Currency string `json:"currency"`
+	Kind     string `json:"kind"`
+	Rate     string `json:"rate"`
 }
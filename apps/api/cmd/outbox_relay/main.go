@@ -0,0 +1,69 @@
+// Command outbox_relay drains the transactional outbox to SNS. Run it when
+// the API is started with OUTBOX_RELAY=external.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joho/godotenv"
+
+	"github.com/example/bookapi/internal/notifications"
+	"github.com/example/bookapi/internal/outbox"
+	"github.com/example/bookapi/internal/repo"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		slog.Error("failed to run outbox relay", "error", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	fs := flag.NewFlagSet("outbox_relay", flag.ExitOnError)
+	pollInterval := fs.Duration("poll-interval", time.Second, "how long to wait between polls when the outbox is empty")
+	batchSize := fs.Int("batch-size", 50, "number of events claimed per poll")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	_ = godotenv.Load()
+
+	dsn := os.Getenv("DB_DSN")
+	if strings.TrimSpace(dsn) == "" {
+		return errors.New("DB_DSN is required")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	publisher, err := notifications.NewSNSBookEventPublisherFromEnv(ctx, slog.Default())
+	if err != nil {
+		return fmt.Errorf("configure SNS publisher: %w", err)
+	}
+	if publisher == nil {
+		return errors.New("SNS_TOPIC_ARN is required")
+	}
+
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		return fmt.Errorf("connect to database: %w", err)
+	}
+	defer pool.Close()
+
+	relay := outbox.NewRelay(repo.NewOutboxRepository(pool), publisher, slog.Default(),
+		outbox.WithPollInterval(*pollInterval),
+		outbox.WithBatchSize(*batchSize),
+	)
+	return relay.Run(ctx)
+}
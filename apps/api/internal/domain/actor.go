@@ -0,0 +1,23 @@
+package domain
+
+import "context"
+
+// AnonymousActor is recorded for changes made without an identified caller.
+const AnonymousActor = "anonymous"
+
+type actorKey struct{}
+
+// ContextWithActor returns a copy of ctx that carries the caller making the
+// request.
+func ContextWithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the caller stored by ContextWithActor, or
+// AnonymousActor when there is none.
+func ActorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return AnonymousActor
+}
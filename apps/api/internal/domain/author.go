@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Author is a person credited on books.
+type Author struct {
+	ID        uuid.UUID
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// AuthorRole is the part an author played in a book.
+type AuthorRole string
+
+const (
+	RoleAuthor     AuthorRole = "author"
+	RoleEditor     AuthorRole = "editor"
+	RoleTranslator AuthorRole = "translator"
+)
+
+// AuthorRoles lists every role in a stable order.
+var AuthorRoles = []AuthorRole{RoleAuthor, RoleEditor, RoleTranslator}
+
+// Valid reports whether r is a known role.
+func (r AuthorRole) Valid() bool {
+	for _, role := range AuthorRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// BookAuthor credits an author on a book. A book's authors are kept in
+// credit order. Name is the author's current name and is not stored with
+// the link.
+type BookAuthor struct {
+	AuthorID uuid.UUID  `json:"authorId"`
+	Name     string     `json:"name"`
+	Role     AuthorRole `json:"role"`
+}
+
+// CreditLine renders authors as the single author string books have always
+// had: the names of those credited as author, or of everyone when nobody is,
+// joined with commas.
+func CreditLine(authors []BookAuthor) string {
+	var names []string
+	for _, author := range authors {
+		if author.Role == RoleAuthor {
+			names = append(names, author.Name)
+		}
+	}
+	if len(names) == 0 {
+		for _, author := range authors {
+			names = append(names, author.Name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// AuthorQuery selects a page of authors ordered by name.
+type AuthorQuery struct {
+	// Name, when set, only returns authors whose name contains it, ignoring
+	// case.
+	Name  string
+	Limit int
+	// After, when set, only returns authors ordered after this position.
+	After *Cursor
+}
+
+// AuthorPage is a page of authors with a cursor to the following page.
+type AuthorPage struct {
+	Authors    []Author
+	NextCursor string
+}
+
+// AuthorCursorSort is the sort recorded in author cursors.
+const AuthorCursorSort = "name"
+
+// NewAuthorCursor returns a cursor positioned after author.
+func NewAuthorCursor(author Author) Cursor {
+	return Cursor{Sort: AuthorCursorSort, Values: []string{author.Name}, ID: author.ID}
+}
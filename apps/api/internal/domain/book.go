@@ -1,6 +1,9 @@
 package domain
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -8,12 +11,58 @@ import (
 
 // Book represents a book record in the system.
 type Book struct {
-	ID        uuid.UUID `json:"id"`
-	Title     string    `json:"title"`
-	Author    string    `json:"author"`
-	Price     float64   `json:"price"`
-	Currency  string    `json:"currency"`
-	Stock     int       `json:"stock"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
+	ID     uuid.UUID `json:"id"`
+	Title  string    `json:"title"`
+	Author string    `json:"author"`
+	// Authors credits the book's authors, editors and translators in order.
+	// Author remains the credit line as printed.
+	Authors []BookAuthor `json:"authors,omitempty"`
+	// Categories files the book in the category tree, ordered by name.
+	Categories []BookCategory `json:"categories,omitempty"`
+	// Tags are normalized tag names in ascending order.
+	Tags []string `json:"tags,omitempty"`
+	// ISBN is the normalized ISBN-13, or empty when the book has none.
+	ISBN  string `json:"isbn,omitempty"`
+	Price Money  `json:"price"`
+	// Prices are explicit prices in currencies other than Price's, ordered
+	// by currency. Other currencies are converted from Price.
+	Prices    []Money    `json:"prices,omitempty"`
+	Stock     int        `json:"stock"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+	Version   int64      `json:"version"`
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+}
+
+// UnmarshalJSON also accepts the format written before prices became Money,
+// where price was a JSON number next to a top-level currency. Outbox rows and
+// revision snapshots stored by older releases use it.
+func (b *Book) UnmarshalJSON(data []byte) error {
+	type plainBook Book
+	var raw struct {
+		plainBook
+		Price    json.RawMessage `json:"price"`
+		Currency string          `json:"currency"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	book := Book(raw.plainBook)
+	price := bytes.TrimSpace(raw.Price)
+	switch {
+	case len(price) == 0 || bytes.Equal(price, []byte("null")):
+	case price[0] == '{':
+		if err := json.Unmarshal(price, &book.Price); err != nil {
+			return err
+		}
+	default:
+		legacy, err := ParseMoney(string(price), raw.Currency)
+		if err != nil {
+			return fmt.Errorf("book price %s: %w", price, err)
+		}
+		book.Price = legacy
+	}
+	*b = book
+	return nil
 }
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Category is a node in the category tree, such as Fantasy under Fiction.
+// Top-level categories have no parent.
+type Category struct {
+	ID        uuid.UUID
+	ParentID  *uuid.UUID
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// BookCategory files a book under a category. Name is the category's current
+// name and is not stored with the link.
+type BookCategory struct {
+	CategoryID uuid.UUID `json:"categoryId"`
+	Name       string    `json:"name"`
+}
+
+// IsDescendant reports whether id is ancestor or lies below it in the tree
+// described by parents, which maps every category to its parent. It stops on
+// cycles rather than looping.
+func IsDescendant(parents map[uuid.UUID]*uuid.UUID, id, ancestor uuid.UUID) bool {
+	seen := make(map[uuid.UUID]bool)
+	for !seen[id] {
+		if id == ancestor {
+			return true
+		}
+		seen[id] = true
+		parent := parents[id]
+		if parent == nil {
+			return false
+		}
+		id = *parent
+	}
+	return false
+}
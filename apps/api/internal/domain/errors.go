@@ -0,0 +1,87 @@
+package domain
+
+import "errors"
+
+// Error kinds classify failures by what the caller can do about them. An
+// error is of a kind when errors.Is matches it; build such errors with
+// NewError or wrap a cause in an Error.
+var (
+	// ErrNotFound: the resource does not exist, or not for this tenant.
+	ErrNotFound = errors.New("not found")
+	// ErrConflict: the request clashes with the current state, such as a
+	// taken name or a resource still in use.
+	ErrConflict = errors.New("conflict")
+	// ErrValidation: the input is invalid.
+	ErrValidation = errors.New("invalid input")
+	// ErrPreconditionFailed: the resource changed since the version the
+	// request was made against.
+	ErrPreconditionFailed = errors.New("precondition failed")
+	// ErrUnavailable: a dependency such as the database is down or
+	// overloaded; the request may succeed when retried.
+	ErrUnavailable = errors.New("unavailable")
+)
+
+// Errors of the catalog entities, of the kinds above and with messages fit
+// for API clients. The repositories return them and callers match on them.
+var (
+	// ErrBookNotFound is returned for a book that does not exist, or that is
+	// soft-deleted where only live books are looked up.
+	ErrBookNotFound    = NewError(ErrNotFound, "book not found")
+	ErrVersionConflict = NewError(ErrPreconditionFailed, "book has been modified; fetch the latest version and retry")
+	ErrBookNotDeleted  = NewError(ErrConflict, "book is not deleted")
+	// ErrAmbiguousBook is returned when looking a book up by title and
+	// author finds more than one live book.
+	ErrAmbiguousBook = NewError(ErrConflict, "several books share this title and author")
+	// ErrInsufficientStock is returned when a stock adjustment would take
+	// stock below zero.
+	ErrInsufficientStock = NewError(ErrConflict, "insufficient stock for this adjustment")
+	// ErrDuplicateISBN is returned when another book, live or soft-deleted,
+	// already has the ISBN.
+	ErrDuplicateISBN  = NewError(ErrConflict, "another book has this ISBN")
+	ErrAuthorNotFound = NewError(ErrNotFound, "author not found")
+	// ErrAuthorInUse is returned when deleting an author who is still
+	// credited on a book, live or soft-deleted.
+	ErrAuthorInUse      = NewError(ErrConflict, "author is credited on books; remove them from those books first")
+	ErrCategoryNotFound = NewError(ErrNotFound, "category not found")
+	// ErrCategoryInUse is returned when deleting a category that still has
+	// subcategories or books, live or soft-deleted.
+	ErrCategoryInUse = NewError(ErrConflict, "category has subcategories or books; move or delete them first")
+	// ErrDuplicateCategory is returned when a sibling category already has
+	// the name.
+	ErrDuplicateCategory = NewError(ErrConflict, "a category with this name already exists under the same parent")
+	ErrTagNotFound       = NewError(ErrNotFound, "tag not found")
+	// ErrTagInUse is returned when deleting a tag that is still on a book,
+	// live or soft-deleted.
+	ErrTagInUse     = NewError(ErrConflict, "tag is on books; remove it from those books first")
+	ErrDuplicateTag = NewError(ErrConflict, "a tag with this name already exists")
+)
+
+// Error is a failure of a kind with a message that is safe to show to
+// callers. Err, the cause, may hold SQL or driver details and is meant for
+// logs only.
+type Error struct {
+	Kind    error
+	Message string
+	Err     error
+}
+
+// NewError returns an error of kind without a cause.
+func NewError(kind error, message string) *Error {
+	return &Error{Kind: kind, Message: message}
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+	return e.Message + ": " + e.Err.Error()
+}
+
+// Unwrap makes errors.Is and errors.As see both the kind and the cause. The
+// kind of an error that wraps others is that of the outermost Error.
+func (e *Error) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Kind}
+	}
+	return []error{e.Kind, e.Err}
+}
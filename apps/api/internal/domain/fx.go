@@ -0,0 +1,205 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// MaxFXRateScale is the number of decimal places exchange rates are stored
+// with.
+const MaxFXRateScale = 12
+
+// ErrNoFXRate is returned when no exchange rate connects two currencies.
+var ErrNoFXRate = errors.New("no exchange rate between the currencies")
+
+// FXRate is the price of one unit of Base in Quote: 1 EUR = 1.0832 USD has
+// Base EUR, Quote USD and Rate 1.0832.
+type FXRate struct {
+	Base      string
+	Quote     string
+	Rate      Decimal
+	UpdatedAt time.Time
+}
+
+// RoundingMode chooses how a converted amount is rounded to the minor unit
+// of its currency.
+type RoundingMode string
+
+const (
+	// RoundHalfUp rounds halves away from zero.
+	RoundHalfUp RoundingMode = "half-up"
+	// RoundHalfEven rounds halves to the even minor unit (banker's rounding).
+	RoundHalfEven RoundingMode = "half-even"
+	// RoundDown truncates towards zero.
+	RoundDown RoundingMode = "down"
+	// RoundUp rounds away from zero.
+	RoundUp RoundingMode = "up"
+)
+
+// ParseRoundingMode parses a rounding mode, defaulting an empty value to
+// RoundHalfUp.
+func ParseRoundingMode(value string) (RoundingMode, error) {
+	switch mode := RoundingMode(strings.ToLower(strings.TrimSpace(value))); mode {
+	case "":
+		return RoundHalfUp, nil
+	case RoundHalfUp, RoundHalfEven, RoundDown, RoundUp:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown rounding mode %q (want half-up, half-even, down or up)", value)
+	}
+}
+
+// FXTable resolves exchange rates between currencies from a set of quoted
+// rates, using a quoted pair in either direction or, failing that, a cross
+// rate through a currency quoted against both.
+type FXTable struct {
+	rates map[[2]string]*big.Rat
+}
+
+func NewFXTable(rates []FXRate) FXTable {
+	table := FXTable{rates: make(map[[2]string]*big.Rat, len(rates))}
+	for _, rate := range rates {
+		if rate.Rate.Unscaled <= 0 {
+			continue
+		}
+		value := new(big.Rat).SetFrac(big.NewInt(rate.Rate.Unscaled), pow10(rate.Rate.Scale))
+		table.rates[[2]string{rate.Base, rate.Quote}] = value
+	}
+	return table
+}
+
+// Rate returns how many units of to one unit of from is worth.
+func (t FXTable) Rate(from, to string) (*big.Rat, bool) {
+	if from == to {
+		return big.NewRat(1, 1), true
+	}
+	if rate, ok := t.direct(from, to); ok {
+		return rate, true
+	}
+	pivots := make(map[string]bool)
+	for pair := range t.rates {
+		pivots[pair[0]] = true
+		pivots[pair[1]] = true
+	}
+	for pivot := range pivots {
+		first, ok := t.direct(from, pivot)
+		if !ok {
+			continue
+		}
+		if second, ok := t.direct(pivot, to); ok {
+			return new(big.Rat).Mul(first, second), true
+		}
+	}
+	return nil, false
+}
+
+func (t FXTable) direct(from, to string) (*big.Rat, bool) {
+	if rate, ok := t.rates[[2]string{from, to}]; ok {
+		return rate, true
+	}
+	if rate, ok := t.rates[[2]string{to, from}]; ok {
+		return new(big.Rat).Inv(rate), true
+	}
+	return nil, false
+}
+
+// FormatRate formats an exchange rate as a decimal with at most
+// MaxFXRateScale places and no trailing zeros.
+func FormatRate(rate *big.Rat) string {
+	text := rate.FloatString(MaxFXRateScale)
+	text = strings.TrimRight(text, "0")
+	return strings.TrimSuffix(text, ".")
+}
+
+// Convert converts m into currency at rate units of currency per unit of
+// m.Currency, rounding to the minor unit of currency with mode.
+func (m Money) Convert(rate *big.Rat, currency string, mode RoundingMode) (Money, error) {
+	value := new(big.Rat).SetFrac(big.NewInt(m.Amount), pow10(CurrencyExponent(m.Currency)))
+	value.Mul(value, rate)
+	value.Mul(value, new(big.Rat).SetInt(pow10(CurrencyExponent(currency))))
+
+	amount := roundRat(value, mode)
+	if !amount.IsInt64() {
+		return Money{}, fmt.Errorf("converted amount of %s %s does not fit %s", m, m.Currency, currency)
+	}
+	return Money{Amount: amount.Int64(), Currency: currency}, nil
+}
+
+// roundRat rounds value to an integer with mode.
+func roundRat(value *big.Rat, mode RoundingMode) *big.Int {
+	num := new(big.Int).Abs(value.Num())
+	quo, rem := new(big.Int).QuoRem(num, value.Denom(), new(big.Int))
+	if rem.Sign() != 0 {
+		// Compare the remainder with half the denominator.
+		half := new(big.Int).Mul(rem, big.NewInt(2)).Cmp(value.Denom())
+		switch mode {
+		case RoundUp:
+			quo.Add(quo, big.NewInt(1))
+		case RoundHalfUp:
+			if half >= 0 {
+				quo.Add(quo, big.NewInt(1))
+			}
+		case RoundHalfEven:
+			if half > 0 || (half == 0 && quo.Bit(0) == 1) {
+				quo.Add(quo, big.NewInt(1))
+			}
+		}
+	}
+	if value.Sign() < 0 {
+		quo.Neg(quo)
+	}
+	return quo
+}
+
+// PriceKind tells whether a price was set on the book or converted from its
+// base price.
+type PriceKind string
+
+const (
+	PriceExplicit  PriceKind = "explicit"
+	PriceConverted PriceKind = "converted"
+)
+
+// QuotedPrice is a book's price in a requested currency.
+type QuotedPrice struct {
+	Price Money
+	Kind  PriceKind
+	// Rate is the exchange rate applied to the base price; nil for explicit
+	// prices.
+	Rate *big.Rat
+}
+
+// QuotePrice returns the book's price in currency: its base price or an
+// explicit price in that currency when there is one, otherwise its base
+// price converted with the rates of table. It fails with ErrNoFXRate when
+// the table cannot convert the base currency.
+func (b Book) QuotePrice(currency string, table FXTable, mode RoundingMode) (QuotedPrice, error) {
+	if price, ok := b.PriceIn(currency); ok {
+		return QuotedPrice{Price: price, Kind: PriceExplicit}, nil
+	}
+	rate, ok := table.Rate(b.Price.Currency, currency)
+	if !ok {
+		return QuotedPrice{}, ErrNoFXRate
+	}
+	price, err := b.Price.Convert(rate, currency, mode)
+	if err != nil {
+		return QuotedPrice{}, err
+	}
+	return QuotedPrice{Price: price, Kind: PriceConverted, Rate: rate}, nil
+}
+
+// PriceIn returns the book's base price or explicit price in currency.
+func (b Book) PriceIn(currency string) (Money, bool) {
+	if b.Price.Currency == currency {
+		return b.Price, true
+	}
+	for _, price := range b.Prices {
+		if price.Currency == currency {
+			return price, true
+		}
+	}
+	return Money{}, false
+}
@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrInvalidISBN       = errors.New("must be an ISBN-10 or ISBN-13")
+	ErrISBNCheckDigit    = errors.New("has an invalid check digit")
+	ErrUnsupportedPrefix = errors.New("must start with 978 or 979")
+)
+
+// NormalizeISBN validates an ISBN-10 or ISBN-13 and returns it as the 13
+// digits of the ISBN-13, without hyphens. Spaces and hyphens in value are
+// ignored and a trailing ISBN-10 check digit may be x or X.
+func NormalizeISBN(value string) (string, error) {
+	digits := strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		if r == 'x' {
+			return 'X'
+		}
+		return r
+	}, strings.TrimSpace(value))
+
+	switch len(digits) {
+	case 10:
+		if !allDigits(digits[:9]) || !(isDigit(digits[9]) || digits[9] == 'X') {
+			return "", ErrInvalidISBN
+		}
+		if isbn10CheckDigit(digits[:9]) != digits[9] {
+			return "", ErrISBNCheckDigit
+		}
+		isbn := "978" + digits[:9]
+		return isbn + string(isbn13CheckDigit(isbn)), nil
+	case 13:
+		if !allDigits(digits) {
+			return "", ErrInvalidISBN
+		}
+		if !strings.HasPrefix(digits, "978") && !strings.HasPrefix(digits, "979") {
+			return "", ErrUnsupportedPrefix
+		}
+		if isbn13CheckDigit(digits[:12]) != digits[12] {
+			return "", ErrISBNCheckDigit
+		}
+		return digits, nil
+	default:
+		return "", ErrInvalidISBN
+	}
+}
+
+// isbn10CheckDigit computes the ISBN-10 check digit of the first nine
+// digits: weights 10 down to 2, modulo 11, with 10 written as X.
+func isbn10CheckDigit(digits string) byte {
+	sum := 0
+	for i := 0; i < 9; i++ {
+		sum += int(digits[i]-'0') * (10 - i)
+	}
+	check := (11 - sum%11) % 11
+	if check == 10 {
+		return 'X'
+	}
+	return byte('0' + check)
+}
+
+// isbn13CheckDigit computes the ISBN-13 check digit of the first twelve
+// digits: alternating weights 1 and 3, modulo 10.
+func isbn13CheckDigit(digits string) byte {
+	sum := 0
+	for i := 0; i < 12; i++ {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += int(digits[i]-'0') * weight
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+func allDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isDigit(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
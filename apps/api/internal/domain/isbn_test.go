@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeISBN(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+		err   error
+	}{
+		{"ISBN-10 with X check digit", "0-8044-2957-X", "9780804429573", nil},
+		{"ISBN-10 with lowercase x", "080442957x", "9780804429573", nil},
+		{"ISBN-10 with spaces and hyphens", " 0 306-40615 2 ", "9780306406157", nil},
+		{"ISBN-13 with hyphens", "978-0-306-40615-7", "9780306406157", nil},
+		{"ISBN-13 with 979 prefix", "979-10-90636-07-1", "9791090636071", nil},
+		{"empty", "", "", ErrInvalidISBN},
+		{"too short", "030640615", "", ErrInvalidISBN},
+		{"between the lengths", "97803064061", "", ErrInvalidISBN},
+		{"too long", "97803064061577", "", ErrInvalidISBN},
+		{"ISBN-10 with a letter", "03064O6152", "", ErrInvalidISBN},
+		{"ISBN-10 with X before the end", "0X06406152", "", ErrInvalidISBN},
+		{"ISBN-13 with X", "978030640615X", "", ErrInvalidISBN},
+		{"ISBN-13 with a dot", "978.030640615", "", ErrInvalidISBN},
+		{"bad ISBN-10 check digit", "0306406153", "", ErrISBNCheckDigit},
+		{"ISBN-10 with X where a digit belongs", "030640615X", "", ErrISBNCheckDigit},
+		{"bad ISBN-13 check digit", "9780306406158", "", ErrISBNCheckDigit},
+		{"bad 979 check digit", "9791090636072", "", ErrISBNCheckDigit},
+		{"unsupported prefix", "9770306406157", "", ErrUnsupportedPrefix},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeISBN(tt.value)
+			require.ErrorIs(t, err, tt.err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
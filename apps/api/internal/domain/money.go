@@ -0,0 +1,202 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// ErrInvalidDecimal is returned when a value is not a plain decimal number.
+var ErrInvalidDecimal = errors.New("must be a decimal number")
+
+const (
+	// DefaultCurrencyExponent is the number of minor-unit digits used by
+	// currencies not listed in currencyExponents.
+	DefaultCurrencyExponent = 2
+	// MaxCurrencyExponent is the largest exponent of any supported currency
+	// and the scale prices are stored with.
+	MaxCurrencyExponent = 3
+)
+
+// currencyExponents lists the ISO 4217 currencies whose minor unit is not
+// one hundredth of the major unit.
+var currencyExponents = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0,
+	"PYG": 0, "RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0,
+	"XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+}
+
+// CurrencyExponent returns the number of decimal places of currency's minor
+// unit, e.g. 2 for USD, 0 for JPY and 3 for KWD.
+func CurrencyExponent(currency string) int {
+	if exponent, ok := currencyExponents[strings.ToUpper(currency)]; ok {
+		return exponent
+	}
+	return DefaultCurrencyExponent
+}
+
+// PrecisionError reports an amount with more decimal places than its
+// currency allows.
+type PrecisionError struct {
+	Currency string
+	Exponent int
+}
+
+func (e PrecisionError) Error() string {
+	return fmt.Sprintf("must have at most %d decimal places for %s", e.Exponent, e.Currency)
+}
+
+// Decimal is an exact decimal number equal to Unscaled × 10^-Scale.
+type Decimal struct {
+	Unscaled int64
+	Scale    int
+}
+
+// ParseDecimal parses a plain decimal such as "24.99" or "-3" exactly.
+// Exponents, thousands separators and values that do not fit are rejected.
+func ParseDecimal(value string) (Decimal, error) {
+	text := strings.TrimSpace(value)
+	negative := strings.HasPrefix(text, "-")
+	text = strings.TrimPrefix(text, "-")
+
+	whole, fraction, hasPoint := strings.Cut(text, ".")
+	if whole == "" || (hasPoint && fraction == "") {
+		return Decimal{}, ErrInvalidDecimal
+	}
+
+	var unscaled int64
+	for _, r := range whole + fraction {
+		if r < '0' || r > '9' {
+			return Decimal{}, ErrInvalidDecimal
+		}
+		digit := int64(r - '0')
+		if unscaled > (math.MaxInt64-digit)/10 {
+			return Decimal{}, ErrInvalidDecimal
+		}
+		unscaled = unscaled*10 + digit
+	}
+	if negative {
+		unscaled = -unscaled
+	}
+	return Decimal{Unscaled: unscaled, Scale: len(fraction)}, nil
+}
+
+// String formats d with exactly Scale decimal places.
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(big.NewInt(d.Unscaled)).String()
+	if d.Scale > 0 {
+		if len(digits) <= d.Scale {
+			digits = strings.Repeat("0", d.Scale-len(digits)+1) + digits
+		}
+		digits = digits[:len(digits)-d.Scale] + "." + digits[len(digits)-d.Scale:]
+	}
+	if d.Unscaled < 0 {
+		return "-" + digits
+	}
+	return digits
+}
+
+// Cmp compares d and other by value, returning -1, 0 or +1.
+func (d Decimal) Cmp(other Decimal) int {
+	a, b := big.NewInt(d.Unscaled), big.NewInt(other.Unscaled)
+	if d.Scale < other.Scale {
+		a.Mul(a, pow10(other.Scale-d.Scale))
+	} else if other.Scale < d.Scale {
+		b.Mul(b, pow10(d.Scale-other.Scale))
+	}
+	return a.Cmp(b)
+}
+
+// Rescale returns d with the given scale. It fails when that would drop
+// non-zero digits or overflow.
+func (d Decimal) Rescale(scale int) (Decimal, bool) {
+	unscaled := big.NewInt(d.Unscaled)
+	if scale >= d.Scale {
+		unscaled.Mul(unscaled, pow10(scale-d.Scale))
+	} else {
+		var remainder big.Int
+		unscaled.QuoRem(unscaled, pow10(d.Scale-scale), &remainder)
+		if remainder.Sign() != 0 {
+			return Decimal{}, false
+		}
+	}
+	if !unscaled.IsInt64() {
+		return Decimal{}, false
+	}
+	return Decimal{Unscaled: unscaled.Int64(), Scale: scale}, true
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// Money is an exact amount in a currency, held as an integer number of the
+// currency's minor units (cents for USD, yen for JPY, fils for KWD).
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+// NewMoney converts amount to minor units of currency. It fails with a
+// PrecisionError when amount has more decimal places than the currency.
+func NewMoney(amount Decimal, currency string) (Money, error) {
+	exponent := CurrencyExponent(currency)
+	scaled, ok := amount.Rescale(exponent)
+	if !ok {
+		return Money{}, PrecisionError{Currency: currency, Exponent: exponent}
+	}
+	return Money{Amount: scaled.Unscaled, Currency: currency}, nil
+}
+
+// ParseMoney parses a decimal amount in major units, e.g. "24.99" USD.
+func ParseMoney(amount, currency string) (Money, error) {
+	value, err := ParseDecimal(amount)
+	if err != nil {
+		return Money{}, err
+	}
+	return NewMoney(value, currency)
+}
+
+// Decimal returns the amount in major units.
+func (m Money) Decimal() Decimal {
+	return Decimal{Unscaled: m.Amount, Scale: CurrencyExponent(m.Currency)}
+}
+
+// String formats the amount in major units with the currency's number of
+// decimal places, e.g. "24.90" for USD or "1500" for JPY.
+func (m Money) String() string {
+	return m.Decimal().String()
+}
+
+// IsNegative reports whether the amount is below zero.
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+type moneyJSON struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// MarshalJSON encodes m as {"amount": "24.99", "currency": "USD"} so the
+// amount survives JSON decoders that use binary floating point.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moneyJSON{Amount: m.String(), Currency: m.Currency})
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var raw moneyJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	parsed, err := ParseMoney(raw.Amount, raw.Currency)
+	if err != nil {
+		return fmt.Errorf("money amount %q: %w", raw.Amount, err)
+	}
+	*m = parsed
+	return nil
+}
@@ -0,0 +1,122 @@
+package domain
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDecimal(t *testing.T) {
+	for value, want := range map[string]Decimal{
+		"24.99":                {Unscaled: 2499, Scale: 2},
+		"-3":                   {Unscaled: -3, Scale: 0},
+		" 0.050 ":              {Unscaled: 50, Scale: 3},
+		"9223372036854775807":  {Unscaled: math.MaxInt64, Scale: 0},
+		"922337203685477580.7": {Unscaled: math.MaxInt64, Scale: 1},
+		"-9223372036854775807": {Unscaled: -math.MaxInt64, Scale: 0},
+	} {
+		got, err := ParseDecimal(value)
+		require.NoError(t, err, value)
+		require.Equal(t, want, got, value)
+	}
+
+	for _, value := range []string{
+		"",
+		"-",
+		"1.",
+		".5",
+		"+1",
+		"1e2",
+		"1,000",
+		"1.2.3",
+		"--1",
+		"9223372036854775808",
+		"922337203685477580.8",
+		"-9223372036854775808",
+	} {
+		_, err := ParseDecimal(value)
+		require.ErrorIs(t, err, ErrInvalidDecimal, value)
+	}
+}
+
+func TestDecimalString(t *testing.T) {
+	for want, d := range map[string]Decimal{
+		"24.99":  {Unscaled: 2499, Scale: 2},
+		"-0.05":  {Unscaled: -5, Scale: 2},
+		"0.005":  {Unscaled: 5, Scale: 3},
+		"-12.5":  {Unscaled: -125, Scale: 1},
+		"1500":   {Unscaled: 1500, Scale: 0},
+		"0.00":   {Unscaled: 0, Scale: 2},
+		"-1.000": {Unscaled: -1000, Scale: 3},
+	} {
+		require.Equal(t, want, d.String())
+	}
+}
+
+func TestDecimalRescale(t *testing.T) {
+	tests := []struct {
+		name  string
+		d     Decimal
+		scale int
+		want  Decimal
+		ok    bool
+	}{
+		{"adds places", Decimal{Unscaled: 5, Scale: 1}, 3, Decimal{Unscaled: 500, Scale: 3}, true},
+		{"drops zeros", Decimal{Unscaled: 1500, Scale: 3}, 1, Decimal{Unscaled: 15, Scale: 1}, true},
+		{"keeps negatives exact", Decimal{Unscaled: -250, Scale: 2}, 1, Decimal{Unscaled: -25, Scale: 1}, true},
+		{"rejects lost digits", Decimal{Unscaled: 2499, Scale: 2}, 1, Decimal{}, false},
+		{"rejects lost negative digits", Decimal{Unscaled: -1, Scale: 3}, 2, Decimal{}, false},
+		{"rejects overflow", Decimal{Unscaled: math.MaxInt64 / 10, Scale: 0}, 2, Decimal{}, false},
+		{"rejects negative overflow", Decimal{Unscaled: math.MinInt64 / 10, Scale: 0}, 2, Decimal{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.d.Rescale(tt.scale)
+			require.Equal(t, tt.ok, ok)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDecimalCmp(t *testing.T) {
+	tests := []struct {
+		a, b Decimal
+		want int
+	}{
+		{Decimal{Unscaled: 10, Scale: 1}, Decimal{Unscaled: 100, Scale: 2}, 0},
+		{Decimal{Unscaled: 1, Scale: 0}, Decimal{Unscaled: 999, Scale: 3}, 1},
+		{Decimal{Unscaled: 999, Scale: 3}, Decimal{Unscaled: 1, Scale: 0}, -1},
+		{Decimal{Unscaled: -5, Scale: 2}, Decimal{Unscaled: -1, Scale: 1}, 1},
+		{Decimal{Unscaled: math.MaxInt64, Scale: 0}, Decimal{Unscaled: math.MaxInt64, Scale: 1}, 1},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.want, tt.a.Cmp(tt.b), "%s vs %s", tt.a, tt.b)
+	}
+}
+
+func TestBookUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want Money
+	}{
+		{"money", `{"price":{"amount":"24.99","currency":"EUR"}}`, Money{Amount: 2499, Currency: "EUR"}},
+		{"legacy number", `{"price":24.99,"currency":"EUR"}`, Money{Amount: 2499, Currency: "EUR"}},
+		{"legacy integer without minor units", `{"price":100,"currency":"JPY"}`, Money{Amount: 100, Currency: "JPY"}},
+		{"legacy number with fewer places", `{"price":24.9,"currency":"USD"}`, Money{Amount: 2490, Currency: "USD"}},
+		{"no price", `{"title":"Dune"}`, Money{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var book Book
+			require.NoError(t, json.Unmarshal([]byte(tt.data), &book))
+			require.Equal(t, tt.want, book.Price)
+		})
+	}
+
+	var book Book
+	require.ErrorAs(t, json.Unmarshal([]byte(`{"price":1.5,"currency":"JPY"}`), &book), new(PrecisionError))
+	require.ErrorIs(t, json.Unmarshal([]byte(`{"price":1e2,"currency":"USD"}`), &book), ErrInvalidDecimal)
+}
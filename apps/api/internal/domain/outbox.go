@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Outbox event types.
+const (
+	// EventTypeBookCreated identifies events carrying a newly created book.
+	EventTypeBookCreated = "book.created"
+	// EventTypeBookUpdated identifies events carrying a BookUpdate.
+	EventTypeBookUpdated = "book.updated"
+	// EventTypeBookDeleted identifies events carrying a soft-deleted book.
+	EventTypeBookDeleted = "book.deleted"
+	// EventTypeStockChanged identifies events carrying a StockAdjustment.
+	EventTypeStockChanged = "book.stock_changed"
+)
+
+// OutboxEvent is a domain event stored alongside the change that produced it
+// and delivered to the event publisher afterwards.
+type OutboxEvent struct {
+	ID          uuid.UUID
+	Type        string
+	AggregateID uuid.UUID
+	Payload     json.RawMessage
+	CreatedAt   time.Time
+	// Actor is the caller whose change produced the event.
+	Actor string
+	// Tenant is the tenant whose catalog the event is about.
+	Tenant string
+	// Attempts counts failed deliveries so far.
+	Attempts int
+}
+
+// BookUpdate describes a change to a book: the book afterwards and the
+// fields that changed, with their values before and after.
+type BookUpdate struct {
+	Book    Book          `json:"book"`
+	Changes []FieldChange `json:"changes"`
+}
+
+// NewBookCreatedEvent returns the outbox event announcing book.
+func NewBookCreatedEvent(book Book) (OutboxEvent, error) {
+	return newOutboxEvent(EventTypeBookCreated, book.ID, book, book.CreatedAt)
+}
+
+// NewBookUpdatedEvent returns the outbox event announcing update.
+func NewBookUpdatedEvent(update BookUpdate) (OutboxEvent, error) {
+	return newOutboxEvent(EventTypeBookUpdated, update.Book.ID, update, update.Book.UpdatedAt)
+}
+
+// NewBookDeletedEvent returns the outbox event announcing that book, as
+// returned by the delete, was soft-deleted.
+func NewBookDeletedEvent(book Book) (OutboxEvent, error) {
+	createdAt := book.UpdatedAt
+	if book.DeletedAt != nil {
+		createdAt = *book.DeletedAt
+	}
+	return newOutboxEvent(EventTypeBookDeleted, book.ID, book, createdAt)
+}
+
+// NewStockChangedEvent returns the outbox event announcing adjustment.
+func NewStockChangedEvent(adjustment StockAdjustment) (OutboxEvent, error) {
+	return newOutboxEvent(EventTypeStockChanged, adjustment.BookID, adjustment, adjustment.CreatedAt)
+}
+
+func newOutboxEvent(eventType string, aggregateID uuid.UUID, payload any, createdAt time.Time) (OutboxEvent, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return OutboxEvent{}, err
+	}
+	return OutboxEvent{
+		ID:          uuid.New(),
+		Type:        eventType,
+		AggregateID: aggregateID,
+		Payload:     data,
+		CreatedAt:   createdAt,
+	}, nil
+}
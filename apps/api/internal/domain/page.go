@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultPageLimit is used when a list request does not specify a limit.
+	DefaultPageLimit = 20
+	// MaxPageLimit caps the number of books returned in a single page.
+	MaxPageLimit = 100
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor identifies a position in a sorted list of books: the sort key
+// values of the boundary row followed by its ID as a tie-breaker. Backward
+// cursors fetch the page that precedes the position.
+type Cursor struct {
+	Sort     string
+	Values   []string
+	ID       uuid.UUID
+	Backward bool
+}
+
+// NewCursor returns a cursor positioned at book for the given sort order.
+func NewCursor(book Book, sorts []BookSort, backward bool) Cursor {
+	values := make([]string, 0, len(sorts))
+	for _, sort := range sorts {
+		values = append(values, SortValue(book, sort.Field))
+	}
+	return Cursor{
+		Sort:     FormatBookSort(sorts),
+		Values:   values,
+		ID:       book.ID,
+		Backward: backward,
+	}
+}
+
+type cursorPayload struct {
+	Sort     string    `json:"s"`
+	Values   []string  `json:"v"`
+	ID       uuid.UUID `json:"id"`
+	Backward bool      `json:"b,omitempty"`
+}
+
+// Encode returns the opaque, URL-safe representation of the cursor.
+func (c Cursor) Encode() string {
+	payload, _ := json.Marshal(cursorPayload(c))
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+// DecodeCursor parses a cursor previously produced by Encode.
+func DecodeCursor(value string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	var payload cursorPayload
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	if payload.ID == uuid.Nil || payload.Sort == "" {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	return Cursor(payload), nil
+}
+
+// BookPage is a single page of books together with cursors to its neighbours.
+type BookPage struct {
+	Books      []Book
+	NextCursor string
+	PrevCursor string
+}
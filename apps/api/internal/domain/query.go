@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BookSortField names a book attribute that lists can be ordered by.
+type BookSortField string
+
+const (
+	SortByTitle     BookSortField = "title"
+	SortByPrice     BookSortField = "price"
+	SortByStock     BookSortField = "stock"
+	SortByCreatedAt BookSortField = "createdAt"
+	SortByUpdatedAt BookSortField = "updatedAt"
+)
+
+// MaxSortFields caps how many sort keys a single query may combine.
+const MaxSortFields = 3
+
+// BookSort orders a list by a single field.
+type BookSort struct {
+	Field BookSortField
+	Desc  bool
+}
+
+// DefaultBookSort orders books by creation time, oldest first.
+var DefaultBookSort = []BookSort{{Field: SortByCreatedAt}}
+
+// ParseBookSort parses a comma-separated sort spec such as "-price,title",
+// where a leading "-" selects descending order.
+func ParseBookSort(spec string) ([]BookSort, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return DefaultBookSort, nil
+	}
+
+	parts := strings.Split(spec, ",")
+	if len(parts) > MaxSortFields {
+		return nil, fmt.Errorf("at most %d sort fields allowed", MaxSortFields)
+	}
+
+	sorts := make([]BookSort, 0, len(parts))
+	seen := make(map[BookSortField]bool, len(parts))
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		sort := BookSort{}
+		if strings.HasPrefix(part, "-") {
+			sort.Desc = true
+			part = part[1:]
+		}
+		sort.Field = BookSortField(part)
+		switch sort.Field {
+		case SortByTitle, SortByPrice, SortByStock, SortByCreatedAt, SortByUpdatedAt:
+		default:
+			return nil, fmt.Errorf("unknown sort field %q", part)
+		}
+		if seen[sort.Field] {
+			return nil, fmt.Errorf("duplicate sort field %q", part)
+		}
+		seen[sort.Field] = true
+		sorts = append(sorts, sort)
+	}
+	return sorts, nil
+}
+
+// FormatBookSort returns the canonical spec for sorts, the inverse of
+// ParseBookSort.
+func FormatBookSort(sorts []BookSort) string {
+	parts := make([]string, 0, len(sorts))
+	for _, sort := range sorts {
+		if sort.Desc {
+			parts = append(parts, "-"+string(sort.Field))
+		} else {
+			parts = append(parts, string(sort.Field))
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// SortValue renders the value of field on book in the form stored in cursors.
+func SortValue(book Book, field BookSortField) string {
+	switch field {
+	case SortByTitle:
+		return book.Title
+	case SortByPrice:
+		return book.Price.String()
+	case SortByStock:
+		return strconv.Itoa(book.Stock)
+	case SortByCreatedAt:
+		return book.CreatedAt.UTC().Format(time.RFC3339Nano)
+	case SortByUpdatedAt:
+		return book.UpdatedAt.UTC().Format(time.RFC3339Nano)
+	default:
+		return ""
+	}
+}
+
+// BookQuery filters, orders and paginates a list of books. Zero-valued
+// filters are ignored. Time windows include their lower bound and exclude
+// their upper bound.
+type BookQuery struct {
+	Author string
+	// AuthorID, when set, only returns books crediting this author in any
+	// role.
+	AuthorID *uuid.UUID
+	// CategoryID, when set, only returns books filed under this category or
+	// any category below it.
+	CategoryID *uuid.UUID
+	// Tag, when set, only returns books with this normalized tag.
+	Tag           string
+	Currency      string
+	MinPrice      *Decimal
+	MaxPrice      *Decimal
+	InStock       bool
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	UpdatedAfter  *time.Time
+	UpdatedBefore *time.Time
+	// IncludeDeleted also returns soft-deleted books.
+	IncludeDeleted bool
+
+	Sort   []BookSort
+	Limit  int
+	Cursor *Cursor
+}
@@ -0,0 +1,18 @@
+package domain
+
+import "context"
+
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a copy of ctx that carries the ID of the
+// request being served.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the ID stored by ContextWithRequestID, or an
+// empty string when there is none.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"encoding/base64"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RevisionAction is the kind of change a book revision records.
+type RevisionAction string
+
+const (
+	RevisionCreated  RevisionAction = "create"
+	RevisionUpdated  RevisionAction = "update"
+	RevisionDeleted  RevisionAction = "delete"
+	RevisionRestored RevisionAction = "restore"
+)
+
+// FieldChange is the before and after value of a single book field. Before
+// is nil for fields set by a create.
+type FieldChange struct {
+	Field  string `json:"field"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+// BookRevision is an audit record of one change to a book: the book as it
+// was after the change and the fields that changed.
+type BookRevision struct {
+	ID        uuid.UUID
+	BookID    uuid.UUID
+	Version   int64
+	Action    RevisionAction
+	Actor     string
+	Snapshot  Book
+	Changes   []FieldChange
+	CreatedAt time.Time
+}
+
+// NewBookRevision records the change from before to after. before is nil
+// when the book was created.
+func NewBookRevision(action RevisionAction, actor string, before *Book, after Book, at time.Time) BookRevision {
+	return BookRevision{
+		ID:        uuid.New(),
+		BookID:    after.ID,
+		Version:   after.Version,
+		Action:    action,
+		Actor:     actor,
+		Snapshot:  after,
+		Changes:   DiffBooks(before, after),
+		CreatedAt: at,
+	}
+}
+
+// DiffBooks lists the user-visible fields that differ between before and
+// after, in a stable order. A nil before reports every field as set.
+func DiffBooks(before *Book, after Book) []FieldChange {
+	var changes []FieldChange
+	add := func(field string, from, to any, changed bool) {
+		if before == nil {
+			from = nil
+		} else if !changed {
+			return
+		}
+		changes = append(changes, FieldChange{Field: field, Before: from, After: to})
+	}
+
+	var prev Book
+	if before != nil {
+		prev = *before
+	}
+	add("title", prev.Title, after.Title, prev.Title != after.Title)
+	add("author", prev.Author, after.Author, prev.Author != after.Author)
+	add("authors", prev.Authors, after.Authors, !sameAuthors(prev.Authors, after.Authors))
+	add("categories", prev.Categories, after.Categories, !sameCategories(prev.Categories, after.Categories))
+	add("tags", prev.Tags, after.Tags, !slices.Equal(prev.Tags, after.Tags))
+	add("isbn", prev.ISBN, after.ISBN, prev.ISBN != after.ISBN)
+	add("price", prev.Price.String(), after.Price.String(), prev.Price.String() != after.Price.String())
+	add("currency", prev.Price.Currency, after.Price.Currency, prev.Price.Currency != after.Price.Currency)
+	add("prices", prev.Prices, after.Prices, !slices.Equal(prev.Prices, after.Prices))
+	add("stock", prev.Stock, after.Stock, prev.Stock != after.Stock)
+	if before != nil && !equalTimes(prev.DeletedAt, after.DeletedAt) {
+		changes = append(changes, FieldChange{Field: "deletedAt", Before: prev.DeletedAt, After: after.DeletedAt})
+	}
+	return changes
+}
+
+// sameAuthors compares credits by author and role; a renamed author is not a
+// change to the book.
+func sameAuthors(a, b []BookAuthor) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].AuthorID != b[i].AuthorID || a[i].Role != b[i].Role {
+			return false
+		}
+	}
+	return true
+}
+
+// sameCategories compares categories by ID; a renamed category is not a
+// change to the book.
+func sameCategories(a, b []BookCategory) bool {
+	return slices.EqualFunc(a, b, func(x, y BookCategory) bool { return x.CategoryID == y.CategoryID })
+}
+
+func equalTimes(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// BookRevisionQuery selects a page of a book's revisions, newest first.
+type BookRevisionQuery struct {
+	BookID uuid.UUID
+	Limit  int
+	// BeforeVersion, when set, only returns revisions older than this version.
+	BeforeVersion *int64
+}
+
+// BookRevisionPage is a page of revisions with a cursor to older ones.
+type BookRevisionPage struct {
+	Revisions  []BookRevision
+	NextCursor string
+}
+
+// EncodeRevisionCursor returns the opaque cursor for revisions older than
+// version.
+func EncodeRevisionCursor(version int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(version, 10)))
+}
+
+// DecodeRevisionCursor parses a cursor produced by EncodeRevisionCursor.
+func DecodeRevisionCursor(value string) (int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+	version, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil || version < 1 {
+		return 0, ErrInvalidCursor
+	}
+	return version, nil
+}
@@ -0,0 +1,17 @@
+package domain
+
+// BookSearchQuery describes a full-text search over book titles and authors.
+type BookSearchQuery struct {
+	Query  string
+	Limit  int
+	Offset int
+}
+
+// BookSearchResult is a book matched by a search, with its relevance rank
+// and a snippet of its HTML-escaped title and author where matching terms are
+// wrapped in <mark> tags.
+type BookSearchResult struct {
+	Book    Book
+	Rank    float32
+	Snippet string
+}
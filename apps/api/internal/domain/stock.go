@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StockReason explains why stock was adjusted.
+type StockReason string
+
+const (
+	StockSale      StockReason = "sale"
+	StockReturn    StockReason = "return"
+	StockRestock   StockReason = "restock"
+	StockShrinkage StockReason = "shrinkage"
+)
+
+// StockReasons lists every reason in a stable order.
+var StockReasons = []StockReason{StockSale, StockReturn, StockRestock, StockShrinkage}
+
+// Valid reports whether r is a known reason.
+func (r StockReason) Valid() bool {
+	for _, reason := range StockReasons {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// Decreases reports whether adjustments for r take stock out: sales and
+// shrinkage carry a negative delta, returns and restocks a positive one.
+func (r StockReason) Decreases() bool {
+	return r == StockSale || r == StockShrinkage
+}
+
+// StockAdjustment is one entry of the stock ledger: a signed change to a
+// book's stock and the stock level and book version it produced.
+type StockAdjustment struct {
+	ID          uuid.UUID   `json:"id"`
+	BookID      uuid.UUID   `json:"bookId"`
+	Delta       int         `json:"delta"`
+	Reason      StockReason `json:"reason"`
+	Reference   string      `json:"reference,omitempty"`
+	Actor       string      `json:"actor"`
+	StockAfter  int         `json:"stockAfter"`
+	BookVersion int64       `json:"bookVersion"`
+	CreatedAt   time.Time   `json:"createdAt"`
+}
+
+// StockLedgerQuery selects a page of a book's stock adjustments, newest
+// first.
+type StockLedgerQuery struct {
+	BookID uuid.UUID
+	Limit  int
+	// BeforeVersion, when set, only returns adjustments that produced an
+	// older book version. Ledger cursors encode it like revision cursors.
+	BeforeVersion *int64
+}
+
+// StockLedgerPage is a page of adjustments with a cursor to older ones.
+type StockLedgerPage struct {
+	Adjustments []StockAdjustment
+	NextCursor  string
+}
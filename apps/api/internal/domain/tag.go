@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tag is a free-form label on books. Names are normalized with NormalizeTag
+// and unique.
+type Tag struct {
+	ID        uuid.UUID
+	Name      string
+	CreatedAt time.Time
+}
+
+// NormalizeTag lowercases a tag name and collapses its whitespace, so
+// "Epic  Fantasy" and "epic fantasy" name the same tag.
+func NormalizeTag(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+}
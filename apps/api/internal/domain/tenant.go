@@ -0,0 +1,44 @@
+package domain
+
+import "context"
+
+// DefaultTenant owns the catalog of requests and jobs that name no tenant,
+// including every book stored before tenants were introduced.
+const DefaultTenant = "default"
+
+// MaxTenantIDLength bounds the length of a tenant ID.
+const MaxTenantIDLength = 64
+
+type tenantKey struct{}
+
+// ContextWithTenant returns a copy of ctx whose catalog reads and writes are
+// limited to tenant.
+func ContextWithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant stored by ContextWithTenant, or
+// DefaultTenant when there is none.
+func TenantFromContext(ctx context.Context) string {
+	if tenant, ok := ctx.Value(tenantKey{}).(string); ok && tenant != "" {
+		return tenant
+	}
+	return DefaultTenant
+}
+
+// ValidTenantID reports whether id can name a tenant: 1 to MaxTenantIDLength
+// ASCII letters, digits, '.', '_' or '-'.
+func ValidTenantID(id string) bool {
+	if id == "" || len(id) > MaxTenantIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '.', r == '_', r == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
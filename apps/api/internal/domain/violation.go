@@ -0,0 +1,40 @@
+package domain
+
+// Violation codes say what is wrong with a field of a rejected request.
+// Clients match on them, so a published code keeps its meaning.
+const (
+	// ViolationRequired: the field is missing or empty.
+	ViolationRequired = "required"
+	// ViolationInvalid: the value is wrong in a way no other code covers.
+	ViolationInvalid = "invalid"
+	// ViolationInvalidType: the value has the wrong JSON type.
+	ViolationInvalidType = "invalid_type"
+	// ViolationInvalidFormat: the value does not have the expected format,
+	// such as a UUID, a decimal or an ISO 4217 code.
+	ViolationInvalidFormat = "invalid_format"
+	// ViolationInvalidLength: the string or list is too short or too long.
+	ViolationInvalidLength = "invalid_length"
+	// ViolationOutOfRange: the number or time is outside the allowed range.
+	ViolationOutOfRange = "out_of_range"
+	// ViolationInvalidChoice: the value is not one of the allowed values.
+	ViolationInvalidChoice = "invalid_choice"
+	// ViolationInvalidPrecision: the decimal has too many decimal places.
+	ViolationInvalidPrecision = "invalid_precision"
+	// ViolationDuplicate: the value repeats another one of the request.
+	ViolationDuplicate = "duplicate"
+	// ViolationUnknownReference: the value refers to something that does not
+	// exist.
+	ViolationUnknownReference = "unknown_reference"
+	// ViolationUnknownField: the request has a field the API does not know.
+	ViolationUnknownField = "unknown_field"
+)
+
+// Violation is one invalid field of a rejected request.
+type Violation struct {
+	// Field is the path of the field, such as "title" or "authors[1].role".
+	Field string `json:"field"`
+	// Code is one of the Violation* codes.
+	Code string `json:"code"`
+	// Message describes the problem to a person.
+	Message string `json:"message"`
+}
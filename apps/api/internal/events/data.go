@@ -0,0 +1,72 @@
+package events
+
+import (
+	"net/mail"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BookCreatedV1 is the data of TypeBookCreatedV1 events.
+type BookCreatedV1 struct {
+	BookID uuid.UUID `json:"bookId"`
+	Title  string    `json:"title"`
+	Author string    `json:"author"`
+	ISBN   string    `json:"isbn,omitempty"`
+	// Price is an exact decimal amount in Currency, e.g. "24.99".
+	Price    string `json:"price"`
+	Currency string `json:"currency"`
+	Stock    int    `json:"stock"`
+	Version  int64  `json:"version"`
+	Actor    string `json:"actor"`
+	// UserEmail is the address of the creating user when Actor is one.
+	UserEmail string `json:"userEmail,omitempty"`
+}
+
+// BookUpdatedV1 is the data of TypeBookUpdatedV1 events.
+type BookUpdatedV1 struct {
+	BookID  uuid.UUID       `json:"bookId"`
+	Title   string          `json:"title"`
+	Version int64           `json:"version"`
+	Actor   string          `json:"actor"`
+	Changes []FieldChangeV1 `json:"changes"`
+}
+
+// FieldChangeV1 is a field of a book changed by an update, with its values
+// before and after the update.
+type FieldChangeV1 struct {
+	Field  string `json:"field"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+// BookDeletedV1 is the data of TypeBookDeletedV1 events.
+type BookDeletedV1 struct {
+	BookID    uuid.UUID `json:"bookId"`
+	Title     string    `json:"title"`
+	Version   int64     `json:"version"`
+	Actor     string    `json:"actor"`
+	DeletedAt time.Time `json:"deletedAt"`
+}
+
+// StockChangedV1 is the data of TypeStockChangedV1 events.
+type StockChangedV1 struct {
+	BookID uuid.UUID `json:"bookId"`
+	Delta  int       `json:"delta"`
+	// Stock is the stock level after the adjustment.
+	Stock     int    `json:"stock"`
+	Reason    string `json:"reason"`
+	Reference string `json:"reference,omitempty"`
+	Version   int64  `json:"version"`
+	Actor     string `json:"actor"`
+}
+
+// EmailOf returns actor when it is a bare email address, such as
+// "reader@example.com", and "" otherwise.
+func EmailOf(actor string) string {
+	address, err := mail.ParseAddress(actor)
+	if err != nil || address.Name != "" || address.Address != actor {
+		return ""
+	}
+	return address.Address
+}
@@ -0,0 +1,127 @@
+// Package events defines the book events the API publishes: CloudEvents 1.0
+// envelopes around typed, versioned data, each version described by an
+// embedded JSON Schema that publishers and consumers validate against.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SpecVersion is the CloudEvents version of every envelope.
+const SpecVersion = "1.0"
+
+// Source is the CloudEvents source of events published by the API.
+const Source = "/bookapi"
+
+// Event types. A breaking change to an event's data gets a new type with the
+// next version suffix and a new schema, published alongside the old one until
+// its consumers have moved on.
+const (
+	TypeBookCreatedV1  = "com.example.bookapi.book.created.v1"
+	TypeBookUpdatedV1  = "com.example.bookapi.book.updated.v1"
+	TypeBookDeletedV1  = "com.example.bookapi.book.deleted.v1"
+	TypeStockChangedV1 = "com.example.bookapi.book.stock_changed.v1"
+)
+
+const dataContentType = "application/json"
+
+// Envelope is a CloudEvent in the structured JSON format.
+type Envelope struct {
+	SpecVersion     string    `json:"specversion"`
+	ID              string    `json:"id"`
+	Source          string    `json:"source"`
+	Type            string    `json:"type"`
+	Subject         string    `json:"subject,omitempty"`
+	Time            time.Time `json:"time"`
+	DataContentType string    `json:"datacontenttype"`
+	// DataSchema is the URI of the JSON Schema Data conforms to, see
+	// SchemaURI.
+	DataSchema string `json:"dataschema"`
+	// TenantID is the tenantid extension attribute: the tenant whose
+	// catalog the event is about. Consumers serving one storefront must skip
+	// the events of the others.
+	TenantID string          `json:"tenantid,omitempty"`
+	Data     json.RawMessage `json:"data"`
+}
+
+// New wraps data in an envelope of eventType about subject, the ID of the
+// book it concerns, after validating data against the type's schema. An
+// empty id is replaced by a random one.
+func New(eventType, id, subject string, at time.Time, data any) (Envelope, error) {
+	schema, err := SchemaURI(eventType)
+	if err != nil {
+		return Envelope{}, err
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return Envelope{}, fmt.Errorf("marshal %s data: %w", eventType, err)
+	}
+	if err := Validate(eventType, raw); err != nil {
+		return Envelope{}, err
+	}
+	if id == "" {
+		id = uuid.NewString()
+	}
+	return Envelope{
+		SpecVersion:     SpecVersion,
+		ID:              id,
+		Source:          Source,
+		Type:            eventType,
+		Subject:         subject,
+		Time:            at.UTC(),
+		DataContentType: dataContentType,
+		DataSchema:      schema,
+		Data:            raw,
+	}, nil
+}
+
+// Parse decodes a structured CloudEvent and validates its data against the
+// schema of its type. Events of unknown types are rejected.
+func Parse(message []byte) (Envelope, error) {
+	var envelope Envelope
+	if err := json.Unmarshal(message, &envelope); err != nil {
+		return Envelope{}, fmt.Errorf("decode event: %w", err)
+	}
+	switch {
+	case envelope.SpecVersion != SpecVersion:
+		return Envelope{}, fmt.Errorf("unsupported CloudEvents specversion %q", envelope.SpecVersion)
+	case envelope.ID == "", envelope.Source == "", envelope.Type == "":
+		return Envelope{}, errors.New("event must have an id, source and type")
+	case envelope.DataContentType != "" && envelope.DataContentType != dataContentType:
+		return Envelope{}, fmt.Errorf("unsupported datacontenttype %q", envelope.DataContentType)
+	}
+	if err := Validate(envelope.Type, envelope.Data); err != nil {
+		return Envelope{}, err
+	}
+	return envelope, nil
+}
+
+// DecodeData unmarshals the data of e into v.
+func (e Envelope) DecodeData(v any) error {
+	if err := json.Unmarshal(e.Data, v); err != nil {
+		return fmt.Errorf("decode %s data: %w", e.Type, err)
+	}
+	return nil
+}
+
+type idKey struct{}
+
+// ContextWithID returns a copy of ctx under which events are published with
+// id, so that redelivering the same change yields an event with the same ID
+// consumers can deduplicate on.
+func ContextWithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, idKey{}, id)
+}
+
+// IDFromContext returns the ID stored by ContextWithID, or "" when there is
+// none.
+func IDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(idKey{}).(string)
+	return id
+}
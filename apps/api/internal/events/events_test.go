@@ -0,0 +1,126 @@
+package events
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAndParse(t *testing.T) {
+	at := time.Date(2025, 11, 10, 12, 0, 0, 0, time.UTC)
+	data := BookCreatedV1{
+		BookID:    uuid.New(),
+		Title:     "Dune",
+		Author:    "Frank Herbert",
+		Price:     "9.99",
+		Currency:  "GBP",
+		Stock:     3,
+		Version:   1,
+		Actor:     "reader@example.com",
+		UserEmail: "reader@example.com",
+	}
+
+	envelope, err := New(TypeBookCreatedV1, "event-1", data.BookID.String(), at, data)
+	require.NoError(t, err)
+	require.Equal(t, SpecVersion, envelope.SpecVersion)
+	require.Equal(t, "event-1", envelope.ID)
+	require.Equal(t, Source, envelope.Source)
+	require.Equal(t, SchemaBaseURI+"book.created.v1.json", envelope.DataSchema)
+
+	message, err := json.Marshal(envelope)
+	require.NoError(t, err)
+	parsed, err := Parse(message)
+	require.NoError(t, err)
+	require.Equal(t, TypeBookCreatedV1, parsed.Type)
+	require.True(t, at.Equal(parsed.Time))
+
+	var decoded BookCreatedV1
+	require.NoError(t, parsed.DecodeData(&decoded))
+	require.Equal(t, data, decoded)
+
+	generated, err := New(TypeBookCreatedV1, "", data.BookID.String(), at, data)
+	require.NoError(t, err)
+	require.NotEmpty(t, generated.ID)
+}
+
+func TestNewRejectsInvalidData(t *testing.T) {
+	_, err := New(TypeBookCreatedV1, "", "", time.Now(), BookCreatedV1{BookID: uuid.New(), Price: "9.99", Currency: "gbp"})
+	require.ErrorContains(t, err, "invalid "+TypeBookCreatedV1+" data")
+	require.ErrorContains(t, err, "title")
+	require.ErrorContains(t, err, "currency")
+
+	_, err = New(TypeStockChangedV1, "", "", time.Now(), StockChangedV1{
+		BookID: uuid.New(), Delta: 1, Stock: 1, Reason: "gift", Version: 2, Actor: "till-3",
+	})
+	require.ErrorContains(t, err, "reason")
+
+	_, err = New("com.example.bookapi.book.archived.v1", "", "", time.Now(), struct{}{})
+	require.ErrorContains(t, err, "unknown event type")
+}
+
+func TestParseRejectsInvalidEvents(t *testing.T) {
+	bookID := uuid.NewString()
+	testCases := []struct {
+		name    string
+		message string
+		wantErr string
+	}{
+		{
+			name:    "not json",
+			message: `BOOK_CREATED`,
+			wantErr: "decode event",
+		},
+		{
+			name:    "legacy message",
+			message: `{"type":"BOOK_CREATED","bookId":"` + bookID + `","title":"Dune","price":9.99}`,
+			wantErr: "specversion",
+		},
+		{
+			name:    "missing id",
+			message: `{"specversion":"1.0","source":"/bookapi","type":"` + TypeBookDeletedV1 + `","data":{}}`,
+			wantErr: "must have an id",
+		},
+		{
+			name:    "unknown type",
+			message: `{"specversion":"1.0","id":"1","source":"/bookapi","type":"com.example.other.v1","data":{}}`,
+			wantErr: "unknown event type",
+		},
+		{
+			name: "invalid data",
+			message: `{"specversion":"1.0","id":"1","source":"/bookapi","type":"` + TypeBookDeletedV1 + `",` +
+				`"data":{"bookId":"` + bookID + `","title":"Dune","version":2,"actor":"anonymous","deletedAt":"yesterday"}}`,
+			wantErr: "deletedAt",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse([]byte(tc.message))
+			require.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestSchemaIDs(t *testing.T) {
+	for eventType := range schemaFileNames {
+		raw, err := Schema(eventType)
+		require.NoError(t, err)
+		var schema struct {
+			ID string `json:"$id"`
+		}
+		require.NoError(t, json.Unmarshal(raw, &schema))
+		uri, err := SchemaURI(eventType)
+		require.NoError(t, err)
+		require.Equal(t, uri, schema.ID, eventType)
+	}
+}
+
+func TestEmailOf(t *testing.T) {
+	require.Equal(t, "reader@example.com", EmailOf("reader@example.com"))
+	require.Empty(t, EmailOf("Reader <reader@example.com>"))
+	require.Empty(t, EmailOf("till-3"))
+	require.Empty(t, EmailOf("anonymous"))
+}
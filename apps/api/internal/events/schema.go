@@ -0,0 +1,88 @@
+package events
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// SchemaBaseURI prefixes the URI of every schema, which is also its $id.
+const SchemaBaseURI = "https://bookapi.example.com/schemas/events/"
+
+//go:embed schemas/*.json
+var schemaFiles embed.FS
+
+// schemaFileNames maps each event type to its schema in schemaFiles.
+var schemaFileNames = map[string]string{
+	TypeBookCreatedV1:  "book.created.v1.json",
+	TypeBookUpdatedV1:  "book.updated.v1.json",
+	TypeBookDeletedV1:  "book.deleted.v1.json",
+	TypeStockChangedV1: "book.stock_changed.v1.json",
+}
+
+var (
+	schemas        = mustLoadSchemas()
+	schemaRegistry = huma.NewMapRegistry("#/components/schemas/", huma.DefaultSchemaNamer)
+)
+
+// Schema returns the JSON Schema of the data of eventType.
+func Schema(eventType string) ([]byte, error) {
+	name, ok := schemaFileNames[eventType]
+	if !ok {
+		return nil, fmt.Errorf("unknown event type %q", eventType)
+	}
+	return schemaFiles.ReadFile("schemas/" + name)
+}
+
+// SchemaURI returns the URI identifying the schema of eventType.
+func SchemaURI(eventType string) (string, error) {
+	name, ok := schemaFileNames[eventType]
+	if !ok {
+		return "", fmt.Errorf("unknown event type %q", eventType)
+	}
+	return SchemaBaseURI + name, nil
+}
+
+// Validate checks data, the JSON data of an event of eventType, against the
+// type's schema.
+func Validate(eventType string, data []byte) error {
+	schema, ok := schemas[eventType]
+	if !ok {
+		return fmt.Errorf("unknown event type %q", eventType)
+	}
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("decode %s data: %w", eventType, err)
+	}
+
+	result := &huma.ValidateResult{}
+	huma.Validate(schemaRegistry, schema, huma.NewPathBuffer(make([]byte, 0, 64), 0), huma.ModeReadFromServer, value, result)
+	if len(result.Errors) == 0 {
+		return nil
+	}
+	problems := make([]string, 0, len(result.Errors))
+	for _, err := range result.Errors {
+		problems = append(problems, err.Error())
+	}
+	return fmt.Errorf("invalid %s data: %s", eventType, strings.Join(problems, "; "))
+}
+
+func mustLoadSchemas() map[string]*huma.Schema {
+	loaded := make(map[string]*huma.Schema, len(schemaFileNames))
+	for eventType := range schemaFileNames {
+		raw, err := Schema(eventType)
+		if err != nil {
+			panic(err)
+		}
+		schema := &huma.Schema{}
+		if err := json.Unmarshal(raw, schema); err != nil {
+			panic(fmt.Sprintf("parse schema of %s: %v", eventType, err))
+		}
+		schema.PrecomputeMessages()
+		loaded[eventType] = schema
+	}
+	return loaded
+}
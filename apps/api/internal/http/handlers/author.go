@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"github.com/example/bookapi/internal/domain"
+	"github.com/example/bookapi/internal/service"
+	"github.com/example/bookapi/openapi"
+)
+
+type AuthorIDInput struct {
+	ID uuid.UUID `path:"id"`
+}
+
+type CreateAuthorInput struct {
+	Body openapi.AuthorCreate `body:""`
+}
+
+type UpdateAuthorInput struct {
+	ID   uuid.UUID            `path:"id"`
+	Body openapi.AuthorUpdate `body:""`
+}
+
+type AuthorOutput struct {
+	Body openapi.Author
+}
+
+type ListAuthorsInput struct {
+	Name   string `query:"name" maxLength:"200" doc:"Case-insensitive substring of the author name"`
+	Limit  int    `query:"limit" minimum:"1" maximum:"100" default:"20" doc:"Maximum number of authors to return"`
+	Cursor string `query:"cursor" doc:"Opaque cursor taken from nextCursor of a previous page"`
+}
+
+type ListAuthorsOutput struct {
+	Body struct {
+		Authors    []openapi.Author `json:"authors"`
+		NextCursor string           `json:"nextCursor,omitempty"`
+	}
+}
+
+type ListAuthorBooksInput struct {
+	ID       uuid.UUID `path:"id"`
+	Limit    int       `query:"limit" minimum:"1" maximum:"100" default:"20" doc:"Maximum number of books to return"`
+	Cursor   string    `query:"cursor" doc:"Opaque cursor taken from nextCursor or prevCursor of a previous page"`
+	Currency string    `query:"currency" pattern:"^[A-Za-z]{3}$" doc:"ISO 4217 currency to quote prices in, as quotedPrice"`
+}
+
+func RegisterAuthorRoutes(api huma.API, handler *BookHandler) {
+	huma.Register(api, huma.Operation{
+		OperationID:   "list-authors",
+		Method:        http.MethodGet,
+		Path:          "/authors",
+		Summary:       "List authors",
+		DefaultStatus: http.StatusOK,
+		Errors:        []int{http.StatusBadRequest},
+	}, handler.listAuthors)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "create-author",
+		Method:        http.MethodPost,
+		Path:          "/authors",
+		Summary:       "Create author",
+		DefaultStatus: http.StatusCreated,
+		Errors:        []int{http.StatusBadRequest},
+	}, handler.createAuthor)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "get-author",
+		Method:        http.MethodGet,
+		Path:          "/authors/{id}",
+		Summary:       "Get author by ID",
+		DefaultStatus: http.StatusOK,
+		Errors:        []int{http.StatusNotFound},
+	}, handler.getAuthor)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "update-author",
+		Method:        http.MethodPut,
+		Path:          "/authors/{id}",
+		Summary:       "Rename author",
+		DefaultStatus: http.StatusOK,
+		Errors:        []int{http.StatusBadRequest, http.StatusNotFound},
+	}, handler.updateAuthor)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "delete-author",
+		Method:        http.MethodDelete,
+		Path:          "/authors/{id}",
+		Summary:       "Delete author",
+		DefaultStatus: http.StatusNoContent,
+		Errors:        []int{http.StatusNotFound, http.StatusConflict},
+	}, handler.deleteAuthor)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "list-author-books",
+		Method:        http.MethodGet,
+		Path:          "/authors/{id}/books",
+		Summary:       "List the books of an author",
+		DefaultStatus: http.StatusOK,
+		Errors:        []int{http.StatusBadRequest, http.StatusNotFound},
+	}, handler.listAuthorBooks)
+}
+
+func (h *BookHandler) listAuthors(ctx context.Context, input *ListAuthorsInput) (*ListAuthorsOutput, error) {
+	page, err := h.service.ListAuthors(ctx, service.AuthorListInput{
+		Name:   input.Name,
+		Limit:  input.Limit,
+		Cursor: input.Cursor,
+	})
+	if err != nil {
+		return nil, apiError(ctx, err)
+	}
+
+	output := &ListAuthorsOutput{}
+	output.Body.Authors = make([]openapi.Author, 0, len(page.Authors))
+	for _, author := range page.Authors {
+		output.Body.Authors = append(output.Body.Authors, toOpenAPIAuthor(author))
+	}
+	output.Body.NextCursor = page.NextCursor
+	return output, nil
+}
+
+func (h *BookHandler) createAuthor(ctx context.Context, input *CreateAuthorInput) (*AuthorOutput, error) {
+	author, err := h.service.CreateAuthor(ctx, service.AuthorInput{Name: input.Body.Name})
+	if err != nil {
+		return nil, apiError(ctx, err)
+	}
+	return &AuthorOutput{Body: toOpenAPIAuthor(author)}, nil
+}
+
+func (h *BookHandler) getAuthor(ctx context.Context, input *AuthorIDInput) (*AuthorOutput, error) {
+	author, err := h.service.GetAuthor(ctx, input.ID)
+	if err != nil {
+		return nil, apiError(ctx, err)
+	}
+	return &AuthorOutput{Body: toOpenAPIAuthor(author)}, nil
+}
+
+func (h *BookHandler) updateAuthor(ctx context.Context, input *UpdateAuthorInput) (*AuthorOutput, error) {
+	author, err := h.service.UpdateAuthor(ctx, input.ID, service.AuthorInput{Name: input.Body.Name})
+	if err != nil {
+		return nil, apiError(ctx, err)
+	}
+	return &AuthorOutput{Body: toOpenAPIAuthor(author)}, nil
+}
+
+func (h *BookHandler) deleteAuthor(ctx context.Context, input *AuthorIDInput) (*struct{}, error) {
+	if err := h.service.DeleteAuthor(ctx, input.ID); err != nil {
+		return nil, apiError(ctx, err)
+	}
+	return nil, nil
+}
+
+func (h *BookHandler) listAuthorBooks(ctx context.Context, input *ListAuthorBooksInput) (*ListBooksOutput, error) {
+	page, err := h.service.ListAuthorBooks(ctx, input.ID, service.BookListInput{
+		Limit:  input.Limit,
+		Cursor: input.Cursor,
+	})
+	if err != nil {
+		return nil, apiError(ctx, err)
+	}
+
+	output := &ListBooksOutput{}
+	output.Body.Books = make([]openapi.Book, 0, len(page.Books))
+	for _, book := range page.Books {
+		output.Body.Books = append(output.Body.Books, toOpenAPIBook(book))
+	}
+	if err := h.quotePrices(ctx, input.Currency, page.Books, output.Body.Books); err != nil {
+		return nil, err
+	}
+	output.Body.NextCursor = page.NextCursor
+	output.Body.PrevCursor = page.PrevCursor
+	return output, nil
+}
+
+func toOpenAPIAuthor(author domain.Author) openapi.Author {
+	return openapi.Author{
+		Id:        openapi_types.UUID(author.ID),
+		Name:      author.Name,
+		CreatedAt: author.CreatedAt,
+		UpdatedAt: author.UpdatedAt,
+	}
+}
+
+func toOpenAPIBookAuthors(authors []domain.BookAuthor) *[]openapi.BookAuthor {
+	if len(authors) == 0 {
+		return nil
+	}
+	result := make([]openapi.BookAuthor, 0, len(authors))
+	for _, author := range authors {
+		result = append(result, openapi.BookAuthor{
+			AuthorId: openapi_types.UUID(author.AuthorID),
+			Name:     author.Name,
+			Role:     openapi.AuthorRole(author.Role),
+		})
+	}
+	return &result
+}
+
+func toServiceBookAuthors(authors []openapi.BookAuthorCreate) []service.BookAuthorInput {
+	result := make([]service.BookAuthorInput, 0, len(authors))
+	for _, author := range authors {
+		input := service.BookAuthorInput{AuthorID: uuid.UUID(author.AuthorId)}
+		if author.Role != nil {
+			input.Role = string(*author.Role)
+		}
+		result = append(result, input)
+	}
+	return result
+}
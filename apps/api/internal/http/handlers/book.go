@@ -2,55 +2,159 @@ package handlers
 
 import (
 	"context"
-	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/google/uuid"
 	openapi_types "github.com/oapi-codegen/runtime/types"
 
 	"github.com/example/bookapi/internal/domain"
-	"github.com/example/bookapi/internal/repo"
+	"github.com/example/bookapi/internal/http/problem"
 	"github.com/example/bookapi/internal/service"
 	"github.com/example/bookapi/openapi"
 )
 
+// batchCreateMaxBodyBytes leaves room for service.MaxBatchCreateItems books;
+// huma's 1 MiB default fits only a few thousand.
+const batchCreateMaxBodyBytes = 8 << 20
+
 type BookHandler struct {
-	service *service.BookService
+	service        *service.BookService
+	requireIfMatch bool
 }
 
-func NewBookHandler(service *service.BookService) *BookHandler {
-	return &BookHandler{service: service}
+func NewBookHandler(service *service.BookService, opts ...BookHandlerOption) *BookHandler {
+	handler := &BookHandler{service: service}
+	for _, opt := range opts {
+		opt(handler)
+	}
+	return handler
+}
+
+// BookHandlerOption configures BookHandler behavior.
+type BookHandlerOption func(*BookHandler)
+
+// WithIfMatchRequired makes PUT and DELETE reject requests without an
+// If-Match header with 428 Precondition Required.
+func WithIfMatchRequired(required bool) BookHandlerOption {
+	return func(handler *BookHandler) {
+		handler.requireIfMatch = required
+	}
 }
 
 type BookIDInput struct {
 	ID uuid.UUID `path:"id"`
 }
 
+type GetBookInput struct {
+	ID       uuid.UUID `path:"id"`
+	Currency string    `query:"currency" pattern:"^[A-Za-z]{3}$" doc:"ISO 4217 currency to quote prices in, as quotedPrice"`
+}
+
+type BookISBNInput struct {
+	ISBN     string `path:"isbn" doc:"ISBN-10 or ISBN-13, with or without hyphens"`
+	Currency string `query:"currency" pattern:"^[A-Za-z]{3}$" doc:"ISO 4217 currency to quote prices in, as quotedPrice"`
+}
+
+type DeleteBookInput struct {
+	ID      uuid.UUID `path:"id"`
+	IfMatch string    `header:"If-Match" doc:"ETag of the book version being deleted"`
+}
+
 type CreateBookInput struct {
 	Body openapi.BookCreate `body:""`
 }
 
 type CreateBookOutput struct {
+	ETag string `header:"ETag"`
 	Body openapi.Book
 }
 
+type BatchCreateBooksInput struct {
+	Body openapi.BatchCreateBooksRequest `body:""`
+}
+
+type BatchCreateBooksOutput struct {
+	Body openapi.BatchCreateBooksResponse
+}
+
 type GetBookOutput struct {
+	ETag string `header:"ETag"`
 	Body openapi.Book
 }
 
+// BookFilterParams are the filters and sort shared by listing and exporting
+// books.
+type BookFilterParams struct {
+	Author         string    `query:"author" maxLength:"200" doc:"Case-insensitive substring of the author name"`
+	Category       string    `query:"category" format:"uuid" doc:"Only return books filed under this category or one of its subcategories"`
+	Tag            string    `query:"tag" maxLength:"50" doc:"Only return books with this tag, ignoring case"`
+	Currency       string    `query:"currency" pattern:"^[A-Za-z]{3}$" doc:"Only return books whose base price is in this ISO 4217 currency; prices are not converted"`
+	MinPrice       string    `query:"minPrice" pattern:"^[0-9]+(\\.[0-9]+)?$" doc:"Minimum price, inclusive"`
+	MaxPrice       string    `query:"maxPrice" pattern:"^[0-9]+(\\.[0-9]+)?$" doc:"Maximum price, inclusive"`
+	InStock        bool      `query:"inStock" doc:"Only return books with stock greater than zero"`
+	CreatedAfter   time.Time `query:"createdAfter" doc:"Only return books created at or after this time"`
+	CreatedBefore  time.Time `query:"createdBefore" doc:"Only return books created before this time"`
+	UpdatedAfter   time.Time `query:"updatedAfter" doc:"Only return books updated at or after this time"`
+	UpdatedBefore  time.Time `query:"updatedBefore" doc:"Only return books updated before this time"`
+	IncludeDeleted bool      `query:"includeDeleted" doc:"Also return soft-deleted books"`
+	Sort           string    `query:"sort" doc:"Comma-separated sort fields (title, price, stock, createdAt, updatedAt); prefix with - for descending"`
+}
+
+type ListBooksInput struct {
+	BookFilterParams
+	Limit  int    `query:"limit" minimum:"1" maximum:"100" default:"20" doc:"Maximum number of books to return"`
+	Cursor string `query:"cursor" doc:"Opaque cursor taken from nextCursor or prevCursor of a previous page"`
+}
+
 type ListBooksOutput struct {
 	Body struct {
-		Books []openapi.Book `json:"books"`
+		Books      []openapi.Book `json:"books"`
+		NextCursor string         `json:"nextCursor,omitempty"`
+		PrevCursor string         `json:"prevCursor,omitempty"`
+	}
+}
+
+type SearchBooksInput struct {
+	Query    string `query:"q" required:"true" minLength:"1" maxLength:"200" doc:"Search terms; supports quoted phrases, OR and -exclusions"`
+	Limit    int    `query:"limit" minimum:"1" maximum:"100" default:"20" doc:"Maximum number of results to return"`
+	Offset   int    `query:"offset" minimum:"0" doc:"Number of results to skip"`
+	Currency string `query:"currency" pattern:"^[A-Za-z]{3}$" doc:"ISO 4217 currency to quote prices in, as quotedPrice"`
+}
+
+type SearchBooksOutput struct {
+	Body struct {
+		Results []openapi.BookSearchResult `json:"results"`
 	}
 }
 
 type UpdateBookInput struct {
-	ID   uuid.UUID          `path:"id"`
-	Body openapi.BookUpdate `body:""`
+	ID      uuid.UUID          `path:"id"`
+	IfMatch string             `header:"If-Match" doc:"ETag of the book version being updated"`
+	Body    openapi.BookUpdate `body:""`
 }
 
 type UpdateBookOutput struct {
+	ETag string `header:"ETag"`
+	Body openapi.Book
+}
+
+type ListBookHistoryInput struct {
+	ID     uuid.UUID `path:"id"`
+	Limit  int       `query:"limit" minimum:"1" maximum:"100" default:"20" doc:"Maximum number of revisions to return"`
+	Cursor string    `query:"cursor" doc:"Opaque cursor taken from nextCursor of a previous page"`
+}
+
+type ListBookHistoryOutput struct {
+	Body struct {
+		Revisions  []openapi.BookRevision `json:"revisions"`
+		NextCursor string                 `json:"nextCursor,omitempty"`
+	}
+}
+
+type RestoreBookOutput struct {
+	ETag string `header:"ETag"`
 	Body openapi.Book
 }
 
@@ -63,12 +167,52 @@ func RegisterBookRoutes(api huma.API, handler *BookHandler) {
 		DefaultStatus: http.StatusOK,
 	}, handler.listBooks)
 
+	// Search, export and the ISBN lookup are registered ahead of /books/{id}
+	// so the router does not treat "search", "export" or "by-isbn" as a book
+	// ID.
+	huma.Register(api, huma.Operation{
+		OperationID:   "search-books",
+		Method:        http.MethodGet,
+		Path:          "/books/search",
+		Summary:       "Search books by title and author",
+		DefaultStatus: http.StatusOK,
+	}, handler.searchBooks)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "export-books",
+		Method:        http.MethodGet,
+		Path:          "/books/export",
+		Summary:       "Export books as CSV, NDJSON or JSON",
+		DefaultStatus: http.StatusOK,
+		Responses: map[string]*huma.Response{
+			"200": {
+				Description: "Every matching book, streamed as an attachment",
+				Content: map[string]*huma.MediaType{
+					exportFormats[exportFormatCSV].contentType:    {},
+					exportFormats[exportFormatNDJSON].contentType: {},
+					exportFormats[exportFormatJSON].contentType:   {},
+				},
+			},
+		},
+		Errors: []int{http.StatusBadRequest},
+	}, handler.exportBooks)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "get-book-by-isbn",
+		Method:        http.MethodGet,
+		Path:          "/books/by-isbn/{isbn}",
+		Summary:       "Get book by ISBN",
+		DefaultStatus: http.StatusOK,
+		Errors:        []int{http.StatusBadRequest, http.StatusNotFound},
+	}, handler.getBookByISBN)
+
 	huma.Register(api, huma.Operation{
 		OperationID:   "get-book",
 		Method:        http.MethodGet,
 		Path:          "/books/{id}",
 		Summary:       "Get book by ID",
 		DefaultStatus: http.StatusOK,
+		Errors:        []int{http.StatusBadRequest, http.StatusNotFound},
 	}, handler.getBook)
 
 	huma.Register(api, huma.Operation{
@@ -77,14 +221,26 @@ func RegisterBookRoutes(api huma.API, handler *BookHandler) {
 		Path:          "/books",
 		Summary:       "Create book",
 		DefaultStatus: http.StatusCreated,
+		Errors:        []int{http.StatusBadRequest, http.StatusConflict},
 	}, handler.createBook)
 
+	huma.Register(api, huma.Operation{
+		OperationID:   "batch-create-books",
+		Method:        http.MethodPost,
+		Path:          "/books:batchCreate",
+		Summary:       "Create many books at once",
+		DefaultStatus: http.StatusOK,
+		MaxBodyBytes:  batchCreateMaxBodyBytes,
+		Errors:        []int{http.StatusBadRequest, http.StatusConflict},
+	}, handler.batchCreateBooks)
+
 	huma.Register(api, huma.Operation{
 		OperationID:   "update-book",
 		Method:        http.MethodPut,
 		Path:          "/books/{id}",
 		Summary:       "Update book",
 		DefaultStatus: http.StatusOK,
+		Errors:        []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusPreconditionFailed, http.StatusPreconditionRequired},
 	}, handler.updateBook)
 
 	huma.Register(api, huma.Operation{
@@ -93,100 +249,351 @@ func RegisterBookRoutes(api huma.API, handler *BookHandler) {
 		Path:          "/books/{id}",
 		Summary:       "Delete book",
 		DefaultStatus: http.StatusNoContent,
+		Errors:        []int{http.StatusBadRequest, http.StatusNotFound, http.StatusPreconditionFailed, http.StatusPreconditionRequired},
 	}, handler.deleteBook)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "list-book-history",
+		Method:        http.MethodGet,
+		Path:          "/books/{id}/history",
+		Summary:       "List the revision history of a book",
+		DefaultStatus: http.StatusOK,
+		Errors:        []int{http.StatusBadRequest, http.StatusNotFound},
+	}, handler.listBookHistory)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "adjust-stock",
+		Method:        http.MethodPost,
+		Path:          "/books/{id}/stock/adjustments",
+		Summary:       "Adjust the stock of a book",
+		DefaultStatus: http.StatusCreated,
+		Errors:        []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict},
+	}, handler.adjustStock)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "list-stock-adjustments",
+		Method:        http.MethodGet,
+		Path:          "/books/{id}/stock/adjustments",
+		Summary:       "List the stock ledger of a book",
+		DefaultStatus: http.StatusOK,
+		Errors:        []int{http.StatusBadRequest, http.StatusNotFound},
+	}, handler.listStockAdjustments)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "restore-book",
+		Method:        http.MethodPost,
+		Path:          "/books/{id}:restore",
+		Summary:       "Restore a deleted book",
+		DefaultStatus: http.StatusOK,
+		Errors:        []int{http.StatusNotFound, http.StatusConflict},
+	}, handler.restoreBook)
 }
 
-func (h *BookHandler) listBooks(ctx context.Context, _ *struct{}) (*ListBooksOutput, error) {
-	books, err := h.service.ListBooks(ctx)
+func (h *BookHandler) listBooks(ctx context.Context, input *ListBooksInput) (*ListBooksOutput, error) {
+	page, err := h.service.ListBooks(ctx, toServiceListInput(input))
 	if err != nil {
-		return nil, huma.NewError(http.StatusInternalServerError, err.Error())
+		return nil, apiError(ctx, err)
 	}
 
-	result := make([]openapi.Book, 0, len(books))
-	for _, b := range books {
+	result := make([]openapi.Book, 0, len(page.Books))
+	for _, b := range page.Books {
 		result = append(result, toOpenAPIBook(b))
 	}
 
 	output := &ListBooksOutput{}
 	output.Body.Books = result
+	output.Body.NextCursor = page.NextCursor
+	output.Body.PrevCursor = page.PrevCursor
+	return output, nil
+}
+
+func (h *BookHandler) searchBooks(ctx context.Context, input *SearchBooksInput) (*SearchBooksOutput, error) {
+	results, err := h.service.SearchBooks(ctx, service.BookSearchInput{
+		Query:  input.Query,
+		Limit:  input.Limit,
+		Offset: input.Offset,
+	})
+	if err != nil {
+		return nil, apiError(ctx, err)
+	}
+
+	books := make([]domain.Book, 0, len(results))
+	converted := make([]openapi.Book, 0, len(results))
+	for _, r := range results {
+		books = append(books, r.Book)
+		converted = append(converted, toOpenAPIBook(r.Book))
+	}
+	if err := h.quotePrices(ctx, input.Currency, books, converted); err != nil {
+		return nil, err
+	}
+
+	output := &SearchBooksOutput{}
+	output.Body.Results = make([]openapi.BookSearchResult, 0, len(results))
+	for i, r := range results {
+		output.Body.Results = append(output.Body.Results, openapi.BookSearchResult{
+			Book:    converted[i],
+			Rank:    r.Rank,
+			Snippet: r.Snippet,
+		})
+	}
 	return output, nil
 }
 
-func (h *BookHandler) getBook(ctx context.Context, input *BookIDInput) (*GetBookOutput, error) {
+func (h *BookHandler) getBook(ctx context.Context, input *GetBookInput) (*GetBookOutput, error) {
 	book, err := h.service.GetBook(ctx, input.ID)
 	if err != nil {
-		if err == repo.ErrNotFound {
-			return nil, huma.NewError(http.StatusNotFound, "book not found")
-		}
-		return nil, huma.NewError(http.StatusInternalServerError, err.Error())
+		return nil, apiError(ctx, err)
+	}
+
+	return h.getBookOutput(ctx, input.Currency, book)
+}
+
+func (h *BookHandler) getBookByISBN(ctx context.Context, input *BookISBNInput) (*GetBookOutput, error) {
+	book, err := h.service.GetBookByISBN(ctx, input.ISBN)
+	if err != nil {
+		return nil, apiError(ctx, err)
 	}
 
-	return &GetBookOutput{Body: toOpenAPIBook(book)}, nil
+	return h.getBookOutput(ctx, input.Currency, book)
+}
+
+// getBookOutput returns book with its price quoted in currency, if any. The
+// ETag stays that of the stored version whatever the currency.
+func (h *BookHandler) getBookOutput(ctx context.Context, currency string, book domain.Book) (*GetBookOutput, error) {
+	body := []openapi.Book{toOpenAPIBook(book)}
+	if err := h.quotePrices(ctx, currency, []domain.Book{book}, body); err != nil {
+		return nil, err
+	}
+	return &GetBookOutput{ETag: versionETag(book.Version), Body: body[0]}, nil
 }
 
 func (h *BookHandler) createBook(ctx context.Context, input *CreateBookInput) (*CreateBookOutput, error) {
 	book, err := h.service.CreateBook(ctx, toServiceCreateInput(input.Body))
 	if err != nil {
-		switch e := err.(type) {
-		case service.ValidationError:
-			return nil, huma.NewError(http.StatusBadRequest, "validation error", fmt.Errorf("fields: %v", e.Fields))
-		default:
-			return nil, huma.NewError(http.StatusInternalServerError, err.Error())
-		}
+		return nil, apiError(ctx, err)
 	}
 
-	output := &CreateBookOutput{Body: toOpenAPIBook(book)}
+	output := &CreateBookOutput{ETag: versionETag(book.Version), Body: toOpenAPIBook(book)}
 	return output, nil
 }
 
-func (h *BookHandler) updateBook(ctx context.Context, input *UpdateBookInput) (*UpdateBookOutput, error) {
-	book, err := h.service.UpdateBook(ctx, input.ID, toServiceUpdateInput(input.Body))
+func (h *BookHandler) batchCreateBooks(ctx context.Context, input *BatchCreateBooksInput) (*BatchCreateBooksOutput, error) {
+	inputs := make([]service.BookCreateInput, 0, len(input.Body.Items))
+	for _, item := range input.Body.Items {
+		inputs = append(inputs, toServiceCreateInput(item))
+	}
+
+	results, err := h.service.CreateBooks(ctx, inputs)
 	if err != nil {
-		switch e := err.(type) {
-		case service.ValidationError:
-			return nil, huma.NewError(http.StatusBadRequest, "validation error", fmt.Errorf("fields: %v", e.Fields))
-		default:
-			if err == repo.ErrNotFound {
-				return nil, huma.NewError(http.StatusNotFound, "book not found")
-			}
-			return nil, huma.NewError(http.StatusInternalServerError, err.Error())
+		return nil, apiError(ctx, err)
+	}
+
+	output := &BatchCreateBooksOutput{}
+	output.Body.Results = make([]openapi.BatchCreateBookResult, 0, len(results))
+	for _, result := range results {
+		item := openapi.BatchCreateBookResult{Index: result.Index}
+		if result.Book != nil {
+			book := toOpenAPIBook(*result.Book)
+			item.Book = &book
+			output.Body.Created++
+		} else {
+			violations := service.ValidationError{Fields: result.Errors}.Violations()
+			item.Errors = &violations
+			output.Body.Failed++
 		}
+		output.Body.Results = append(output.Body.Results, item)
 	}
-	return &UpdateBookOutput{Body: toOpenAPIBook(book)}, nil
+	return output, nil
 }
 
-func (h *BookHandler) deleteBook(ctx context.Context, input *BookIDInput) (*struct{}, error) {
-	err := h.service.DeleteBook(ctx, input.ID)
+func (h *BookHandler) updateBook(ctx context.Context, input *UpdateBookInput) (*UpdateBookOutput, error) {
+	expectedVersion, err := h.expectedVersion(input.IfMatch)
 	if err != nil {
-		if err == repo.ErrNotFound {
-			return nil, huma.NewError(http.StatusNotFound, "book not found")
-		}
-		return nil, huma.NewError(http.StatusInternalServerError, err.Error())
+		return nil, err
+	}
+
+	serviceInput := toServiceUpdateInput(input.Body)
+	serviceInput.ExpectedVersion = expectedVersion
+
+	book, err := h.service.UpdateBook(ctx, input.ID, serviceInput)
+	if err != nil {
+		return nil, apiError(ctx, err)
+	}
+	return &UpdateBookOutput{ETag: versionETag(book.Version), Body: toOpenAPIBook(book)}, nil
+}
+
+func (h *BookHandler) deleteBook(ctx context.Context, input *DeleteBookInput) (*struct{}, error) {
+	expectedVersion, err := h.expectedVersion(input.IfMatch)
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.service.DeleteBook(ctx, input.ID, expectedVersion)
+	if err != nil {
+		return nil, apiError(ctx, err)
 	}
 	return nil, nil
 }
 
+func (h *BookHandler) listBookHistory(ctx context.Context, input *ListBookHistoryInput) (*ListBookHistoryOutput, error) {
+	page, err := h.service.ListBookHistory(ctx, input.ID, service.BookHistoryInput{
+		Limit:  input.Limit,
+		Cursor: input.Cursor,
+	})
+	if err != nil {
+		return nil, apiError(ctx, err)
+	}
+
+	output := &ListBookHistoryOutput{}
+	output.Body.Revisions = make([]openapi.BookRevision, 0, len(page.Revisions))
+	for _, revision := range page.Revisions {
+		output.Body.Revisions = append(output.Body.Revisions, toOpenAPIRevision(revision))
+	}
+	output.Body.NextCursor = page.NextCursor
+	return output, nil
+}
+
+func (h *BookHandler) restoreBook(ctx context.Context, input *BookIDInput) (*RestoreBookOutput, error) {
+	book, err := h.service.RestoreBook(ctx, input.ID)
+	if err != nil {
+		return nil, apiError(ctx, err)
+	}
+	return &RestoreBookOutput{ETag: versionETag(book.Version), Body: toOpenAPIBook(book)}, nil
+}
+
+// expectedVersion turns an If-Match header into the version a write must
+// apply to, enforcing the header when the handler requires it. A header that
+// cannot be parsed is a bad request; 412 is left for versions that no longer
+// match.
+func (h *BookHandler) expectedVersion(ifMatch string) (*int64, error) {
+	if h.requireIfMatch && ifMatch == "" {
+		return nil, huma.NewError(http.StatusPreconditionRequired, "If-Match header is required")
+	}
+	version, err := parseIfMatch(ifMatch)
+	if err != nil {
+		return nil, problem.Validation(http.StatusBadRequest, []domain.Violation{{
+			Field:   "If-Match",
+			Code:    domain.ViolationInvalidFormat,
+			Message: ifMatchMessage,
+		}})
+	}
+	return version, nil
+}
+
 func toOpenAPIBook(book domain.Book) openapi.Book {
 	return openapi.Book{
-		Id:        openapi_types.UUID(book.ID),
-		Title:     book.Title,
-		Author:    book.Author,
-		Price:     float32(book.Price),
-		Currency:  book.Currency,
-		Stock:     book.Stock,
-		CreatedAt: book.CreatedAt,
-		UpdatedAt: book.UpdatedAt,
+		Id:         openapi_types.UUID(book.ID),
+		Title:      book.Title,
+		Author:     book.Author,
+		Authors:    toOpenAPIBookAuthors(book.Authors),
+		Categories: toOpenAPIBookCategories(book.Categories),
+		Tags:       optionalStrings(book.Tags),
+		Isbn:       optionalString(book.ISBN),
+		Price:      book.Price.String(),
+		Currency:   book.Price.Currency,
+		Prices:     toOpenAPIPrices(book.Prices),
+		Stock:      book.Stock,
+		CreatedAt:  book.CreatedAt,
+		UpdatedAt:  book.UpdatedAt,
+		Version:    book.Version,
+		DeletedAt:  book.DeletedAt,
+	}
+}
+
+func toOpenAPIRevision(revision domain.BookRevision) openapi.BookRevision {
+	changes := make([]openapi.FieldChange, 0, len(revision.Changes))
+	for _, change := range revision.Changes {
+		changes = append(changes, openapi.FieldChange{
+			Field:  change.Field,
+			Before: change.Before,
+			After:  change.After,
+		})
+	}
+	return openapi.BookRevision{
+		Version:   revision.Version,
+		Action:    openapi.BookRevisionAction(revision.Action),
+		Actor:     revision.Actor,
+		ChangedAt: revision.CreatedAt,
+		Changes:   changes,
+		Snapshot:  toOpenAPIBook(revision.Snapshot),
 	}
 }
 
 func toServiceCreateInput(body openapi.BookCreate) service.BookCreateInput {
-	return service.BookCreateInput{
+	result := service.BookCreateInput{
 		Title:    body.Title,
-		Author:   body.Author,
-		Price:    float64(body.Price),
+		Author:   derefString(body.Author),
+		ISBN:     derefString(body.Isbn),
+		Price:    body.Price,
 		Currency: body.Currency,
 		Stock:    body.Stock,
 	}
+	if body.Authors != nil {
+		result.Authors = toServiceBookAuthors(*body.Authors)
+	}
+	if body.CategoryIds != nil {
+		result.CategoryIDs = toUUIDs(*body.CategoryIds)
+	}
+	if body.Tags != nil {
+		result.Tags = *body.Tags
+	}
+	if body.Prices != nil {
+		result.Prices = toServicePrices(*body.Prices)
+	}
+	return result
+}
+
+func toServiceListInput(input *ListBooksInput) service.BookListInput {
+	result := toServiceFilterInput(input.BookFilterParams)
+	result.Limit = input.Limit
+	result.Cursor = input.Cursor
+	return result
+}
+
+func toServiceFilterInput(input BookFilterParams) service.BookListInput {
+	return service.BookListInput{
+		Author:         input.Author,
+		Category:       input.Category,
+		Tag:            input.Tag,
+		Currency:       input.Currency,
+		MinPrice:       input.MinPrice,
+		MaxPrice:       input.MaxPrice,
+		InStock:        input.InStock,
+		CreatedAfter:   optionalTime(input.CreatedAfter),
+		CreatedBefore:  optionalTime(input.CreatedBefore),
+		UpdatedAfter:   optionalTime(input.UpdatedAfter),
+		UpdatedBefore:  optionalTime(input.UpdatedBefore),
+		IncludeDeleted: input.IncludeDeleted,
+		Sort:           input.Sort,
+	}
+}
+
+func optionalTime(value time.Time) *time.Time {
+	if value.IsZero() {
+		return nil
+	}
+	return &value
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func optionalStrings(values []string) *[]string {
+	if len(values) == 0 {
+		return nil
+	}
+	return &values
+}
+
+func derefString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
 }
 
 func toServiceUpdateInput(body openapi.BookUpdate) service.BookUpdateInput {
@@ -199,14 +606,34 @@ func toServiceUpdateInput(body openapi.BookUpdate) service.BookUpdateInput {
 		value := *body.Author
 		result.Author = &value
 	}
+	if body.Authors != nil {
+		value := toServiceBookAuthors(*body.Authors)
+		result.Authors = &value
+	}
+	if body.CategoryIds != nil {
+		value := toUUIDs(*body.CategoryIds)
+		result.CategoryIDs = &value
+	}
+	if body.Tags != nil {
+		value := *body.Tags
+		result.Tags = &value
+	}
+	if body.Isbn != nil {
+		value := *body.Isbn
+		result.ISBN = &value
+	}
 	if body.Price != nil {
-		value := float64(*body.Price)
+		value := *body.Price
 		result.Price = &value
 	}
 	if body.Currency != nil {
 		value := *body.Currency
 		result.Currency = &value
 	}
+	if body.Prices != nil {
+		value := toServicePrices(*body.Prices)
+		result.Prices = &value
+	}
 	if body.Stock != nil {
 		value := *body.Stock
 		result.Stock = &value
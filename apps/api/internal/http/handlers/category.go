@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"github.com/example/bookapi/internal/domain"
+	"github.com/example/bookapi/internal/service"
+	"github.com/example/bookapi/openapi"
+)
+
+type CategoryIDInput struct {
+	ID uuid.UUID `path:"id"`
+}
+
+type CreateCategoryInput struct {
+	Body openapi.CategoryCreate `body:""`
+}
+
+type UpdateCategoryInput struct {
+	ID   uuid.UUID              `path:"id"`
+	Body openapi.CategoryUpdate `body:""`
+}
+
+type CategoryOutput struct {
+	Body openapi.Category
+}
+
+type ListCategoriesOutput struct {
+	Body struct {
+		Categories []openapi.Category `json:"categories"`
+	}
+}
+
+func RegisterCategoryRoutes(api huma.API, handler *BookHandler) {
+	huma.Register(api, huma.Operation{
+		OperationID:   "list-categories",
+		Method:        http.MethodGet,
+		Path:          "/categories",
+		Summary:       "List categories",
+		DefaultStatus: http.StatusOK,
+	}, handler.listCategories)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "create-category",
+		Method:        http.MethodPost,
+		Path:          "/categories",
+		Summary:       "Create category",
+		DefaultStatus: http.StatusCreated,
+		Errors:        []int{http.StatusBadRequest, http.StatusConflict},
+	}, handler.createCategory)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "get-category",
+		Method:        http.MethodGet,
+		Path:          "/categories/{id}",
+		Summary:       "Get category by ID",
+		DefaultStatus: http.StatusOK,
+		Errors:        []int{http.StatusNotFound},
+	}, handler.getCategory)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "update-category",
+		Method:        http.MethodPut,
+		Path:          "/categories/{id}",
+		Summary:       "Rename or move category",
+		DefaultStatus: http.StatusOK,
+		Errors:        []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict},
+	}, handler.updateCategory)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "delete-category",
+		Method:        http.MethodDelete,
+		Path:          "/categories/{id}",
+		Summary:       "Delete category",
+		DefaultStatus: http.StatusNoContent,
+		Errors:        []int{http.StatusNotFound, http.StatusConflict},
+	}, handler.deleteCategory)
+}
+
+func (h *BookHandler) listCategories(ctx context.Context, _ *struct{}) (*ListCategoriesOutput, error) {
+	categories, err := h.service.ListCategories(ctx)
+	if err != nil {
+		return nil, apiError(ctx, err)
+	}
+
+	output := &ListCategoriesOutput{}
+	output.Body.Categories = make([]openapi.Category, 0, len(categories))
+	for _, category := range categories {
+		output.Body.Categories = append(output.Body.Categories, toOpenAPICategory(category))
+	}
+	return output, nil
+}
+
+func (h *BookHandler) createCategory(ctx context.Context, input *CreateCategoryInput) (*CategoryOutput, error) {
+	category, err := h.service.CreateCategory(ctx, service.CategoryInput{
+		Name:     input.Body.Name,
+		ParentID: (*uuid.UUID)(input.Body.ParentId),
+	})
+	if err != nil {
+		return nil, apiError(ctx, err)
+	}
+	return &CategoryOutput{Body: toOpenAPICategory(category)}, nil
+}
+
+func (h *BookHandler) getCategory(ctx context.Context, input *CategoryIDInput) (*CategoryOutput, error) {
+	category, err := h.service.GetCategory(ctx, input.ID)
+	if err != nil {
+		return nil, apiError(ctx, err)
+	}
+	return &CategoryOutput{Body: toOpenAPICategory(category)}, nil
+}
+
+func (h *BookHandler) updateCategory(ctx context.Context, input *UpdateCategoryInput) (*CategoryOutput, error) {
+	category, err := h.service.UpdateCategory(ctx, input.ID, service.CategoryInput{
+		Name:     input.Body.Name,
+		ParentID: (*uuid.UUID)(input.Body.ParentId),
+	})
+	if err != nil {
+		return nil, apiError(ctx, err)
+	}
+	return &CategoryOutput{Body: toOpenAPICategory(category)}, nil
+}
+
+func (h *BookHandler) deleteCategory(ctx context.Context, input *CategoryIDInput) (*struct{}, error) {
+	if err := h.service.DeleteCategory(ctx, input.ID); err != nil {
+		return nil, apiError(ctx, err)
+	}
+	return nil, nil
+}
+
+func toOpenAPICategory(category domain.Category) openapi.Category {
+	return openapi.Category{
+		Id:        openapi_types.UUID(category.ID),
+		ParentId:  (*openapi_types.UUID)(category.ParentID),
+		Name:      category.Name,
+		CreatedAt: category.CreatedAt,
+		UpdatedAt: category.UpdatedAt,
+	}
+}
+
+func toOpenAPIBookCategories(categories []domain.BookCategory) *[]openapi.BookCategory {
+	if len(categories) == 0 {
+		return nil
+	}
+	result := make([]openapi.BookCategory, 0, len(categories))
+	for _, category := range categories {
+		result = append(result, openapi.BookCategory{
+			CategoryId: openapi_types.UUID(category.CategoryID),
+			Name:       category.Name,
+		})
+	}
+	return &result
+}
+
+func toUUIDs(ids []openapi_types.UUID) []uuid.UUID {
+	result := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, uuid.UUID(id))
+	}
+	return result
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/example/bookapi/internal/domain"
+	"github.com/example/bookapi/internal/http/problem"
+	"github.com/example/bookapi/internal/service"
+)
+
+// errorStatuses maps the domain error kinds to the statuses they are
+// answered with.
+var errorStatuses = []struct {
+	kind   error
+	status int
+}{
+	{domain.ErrValidation, http.StatusBadRequest},
+	{domain.ErrNotFound, http.StatusNotFound},
+	{domain.ErrConflict, http.StatusConflict},
+	{domain.ErrPreconditionFailed, http.StatusPreconditionFailed},
+	{domain.ErrUnavailable, http.StatusServiceUnavailable},
+}
+
+// internalErrorDetail answers errors of no domain kind, whose messages may
+// hold SQL or driver details.
+const internalErrorDetail = "an internal error occurred; quote the request ID when reporting it"
+
+// apiError converts an error of the service into the problem returned to
+// the client. Validation errors list their violations and other errors of a
+// domain kind show the message of their outermost domain.Error; the rest are
+// internal errors. Causes are logged, never returned.
+func apiError(ctx context.Context, err error) error {
+	var validationErr service.ValidationError
+	if errors.As(err, &validationErr) {
+		return problem.Validation(http.StatusBadRequest, validationErr.Violations())
+	}
+
+	status, detail := http.StatusInternalServerError, internalErrorDetail
+	var domainErr *domain.Error
+	if errors.As(err, &domainErr) {
+		status, detail = statusOf(domainErr.Kind), domainErr.Message
+	} else {
+		for _, s := range errorStatuses {
+			if errors.Is(err, s.kind) {
+				status, detail = s.status, s.kind.Error()
+				break
+			}
+		}
+	}
+	if status == http.StatusInternalServerError {
+		detail = internalErrorDetail
+	}
+
+	requestID := domain.RequestIDFromContext(ctx)
+	if status >= http.StatusInternalServerError {
+		slog.ErrorContext(ctx, "request failed", "error", err, "status", status, "requestId", requestID)
+	} else if domainErr != nil && domainErr.Err != nil {
+		slog.InfoContext(ctx, "request rejected", "error", err, "status", status, "requestId", requestID)
+	}
+	return problem.New(status, detail)
+}
+
+func statusOf(kind error) int {
+	for _, s := range errorStatuses {
+		if kind == s.kind {
+			return s.status
+		}
+	}
+	return http.StatusInternalServerError
+}
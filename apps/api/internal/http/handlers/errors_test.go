@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/bookapi/internal/domain"
+	"github.com/example/bookapi/internal/http/problem"
+	"github.com/example/bookapi/internal/service"
+)
+
+func TestAPIError(t *testing.T) {
+	t.Parallel()
+
+	driverErr := errors.New(`ERROR: new row for relation "books" violates check constraint "books_stock_check" (SQLSTATE 23514)`)
+
+	testCases := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantDetail string
+	}{
+		{
+			name:       "wrapped not found",
+			err:        fmt.Errorf("get book: %w", domain.ErrBookNotFound),
+			wantStatus: http.StatusNotFound,
+			wantDetail: "book not found",
+		},
+		{
+			name:       "conflict",
+			err:        domain.ErrTagInUse,
+			wantStatus: http.StatusConflict,
+			wantDetail: "tag is on books; remove it from those books first",
+		},
+		{
+			name:       "precondition failed",
+			err:        domain.ErrVersionConflict,
+			wantStatus: http.StatusPreconditionFailed,
+			wantDetail: "book has been modified; fetch the latest version and retry",
+		},
+		{
+			name:       "cause is not shown",
+			err:        &domain.Error{Kind: domain.ErrValidation, Message: "violates a constraint of the stored data", Err: driverErr},
+			wantStatus: http.StatusBadRequest,
+			wantDetail: "violates a constraint of the stored data",
+		},
+		{
+			name:       "outermost kind wins",
+			err:        &domain.Error{Kind: domain.ErrValidation, Message: "a credited author no longer exists", Err: domain.ErrAuthorNotFound},
+			wantStatus: http.StatusBadRequest,
+			wantDetail: "a credited author no longer exists",
+		},
+		{
+			name:       "bare kind",
+			err:        fmt.Errorf("load: %w", domain.ErrUnavailable),
+			wantStatus: http.StatusServiceUnavailable,
+			wantDetail: "unavailable",
+		},
+		{
+			name:       "unknown error",
+			err:        fmt.Errorf("scan book: %w", driverErr),
+			wantStatus: http.StatusInternalServerError,
+			wantDetail: internalErrorDetail,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var p *problem.Problem
+			require.ErrorAs(t, apiError(context.Background(), tc.err), &p)
+			require.Equal(t, tc.wantStatus, p.Status)
+			require.Equal(t, tc.wantDetail, p.Detail)
+			require.Empty(t, p.Violations)
+		})
+	}
+
+	t.Run("validation error", func(t *testing.T) {
+		t.Parallel()
+
+		err := fmt.Errorf("create book: %w", service.ValidationError{Fields: map[string]service.FieldError{
+			"title": {Code: domain.ViolationRequired, Message: "required"},
+		}})
+		var p *problem.Problem
+		require.ErrorAs(t, apiError(context.Background(), err), &p)
+		require.Equal(t, http.StatusBadRequest, p.Status)
+		require.Equal(t, problem.CodeValidationFailed, p.Code)
+		require.Equal(t, []domain.Violation{{Field: "title", Code: domain.ViolationRequired, Message: "required"}}, p.Violations)
+	})
+}
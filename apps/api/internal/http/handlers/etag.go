@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ifMatchMessage explains the If-Match headers a write accepts.
+const ifMatchMessage = "must be * or a single strong entity tag"
+
+var errInvalidIfMatch = errors.New("If-Match " + ifMatchMessage)
+
+// versionETag renders a book version as a strong entity tag.
+func versionETag(version int64) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// parseIfMatch converts an If-Match header into the version it requires.
+// Empty and "*" headers impose no version and return nil. Weak tags never
+// match under the strong comparison If-Match requires, so they are rejected
+// along with lists and malformed values.
+func parseIfMatch(header string) (*int64, error) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+	if len(header) < 2 || header[0] != '"' || header[len(header)-1] != '"' {
+		return nil, errInvalidIfMatch
+	}
+	version, err := strconv.ParseInt(header[1:len(header)-1], 10, 64)
+	if err != nil {
+		return nil, errInvalidIfMatch
+	}
+	return &version, nil
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIfMatch(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		header  string
+		want    *int64
+		wantErr bool
+	}{
+		{name: "empty imposes no version", header: ""},
+		{name: "wildcard imposes no version", header: "*"},
+		{name: "strong tag", header: `"7"`, want: ptr(int64(7))},
+		{name: "round trips version etag", header: versionETag(42), want: ptr(int64(42))},
+		{name: "weak tag rejected", header: `W/"7"`, wantErr: true},
+		{name: "list rejected", header: `"7", "8"`, wantErr: true},
+		{name: "unquoted rejected", header: "7", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseIfMatch(tc.header)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,266 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/example/bookapi/internal/domain"
+	"github.com/example/bookapi/internal/http/problem"
+	"github.com/example/bookapi/openapi"
+)
+
+const (
+	exportFormatCSV    = "csv"
+	exportFormatNDJSON = "ndjson"
+	exportFormatJSON   = "json"
+
+	// exportFlushEvery is the number of books written between flushes.
+	exportFlushEvery = 500
+	// exportWriteTimeout replaces the server's WriteTimeout for exports: every
+	// flush pushes the deadline out again, so a large export only fails when
+	// the client stops reading.
+	exportWriteTimeout = 30 * time.Second
+)
+
+type exportFormat struct {
+	contentType string
+	newEncoder  func(w io.Writer) bookEncoder
+}
+
+var exportFormats = map[string]exportFormat{
+	exportFormatCSV:    {contentType: "text/csv; charset=utf-8", newEncoder: newCSVBookEncoder},
+	exportFormatNDJSON: {contentType: "application/x-ndjson", newEncoder: newNDJSONBookEncoder},
+	exportFormatJSON:   {contentType: "application/json", newEncoder: newJSONBookEncoder},
+}
+
+type ExportBooksInput struct {
+	BookFilterParams
+	Format string `query:"format" enum:"csv,ndjson,json" default:"csv" doc:"File format of the export"`
+}
+
+func (h *BookHandler) exportBooks(ctx context.Context, input *ExportBooksInput) (*huma.StreamResponse, error) {
+	export, err := h.service.ExportBooks(toServiceFilterInput(input.BookFilterParams))
+	if err != nil {
+		return nil, apiError(ctx, err)
+	}
+
+	format := exportFormats[input.Format]
+	filename := fmt.Sprintf("books-%s.%s", time.Now().UTC().Format("20060102T150405Z"), input.Format)
+
+	return &huma.StreamResponse{Body: func(ctx huma.Context) {
+		w := &exportWriter{ctx: ctx, contentType: format.contentType, filename: filename}
+		if rw, ok := ctx.BodyWriter().(http.ResponseWriter); ok {
+			w.controller = http.NewResponseController(rw)
+		}
+		w.extendDeadline()
+
+		encoder := format.newEncoder(w)
+		written := 0
+		err := encoder.Begin()
+		if err == nil {
+			err = export.Each(ctx.Context(), func(book domain.Book) error {
+				if err := encoder.Encode(toOpenAPIBook(book)); err != nil {
+					return err
+				}
+				written++
+				if written%exportFlushEvery == 0 {
+					return w.flush(encoder)
+				}
+				return nil
+			})
+		}
+		if err == nil {
+			err = encoder.End()
+		}
+		if err == nil {
+			err = w.flush(encoder)
+		}
+		if err != nil {
+			w.fail(err, written)
+		}
+	}}, nil
+}
+
+// exportWriter sends the export headers with the first bytes of the body,
+// so an export that fails before anything was written can still answer
+// with an error status.
+type exportWriter struct {
+	ctx         huma.Context
+	controller  *http.ResponseController
+	contentType string
+	filename    string
+	started     bool
+}
+
+func (w *exportWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		w.ctx.SetHeader("Content-Type", w.contentType)
+		w.ctx.SetHeader("Content-Disposition", fmt.Sprintf("attachment; filename=%q", w.filename))
+		w.ctx.SetStatus(http.StatusOK)
+	}
+	return w.ctx.BodyWriter().Write(p)
+}
+
+// flush pushes buffered books to the client and extends the write deadline.
+func (w *exportWriter) flush(encoder bookEncoder) error {
+	if err := encoder.Flush(); err != nil {
+		return err
+	}
+	if w.controller != nil && w.started {
+		if err := w.controller.Flush(); err != nil {
+			return err
+		}
+	}
+	w.extendDeadline()
+	return nil
+}
+
+func (w *exportWriter) extendDeadline() {
+	if w.controller != nil {
+		_ = w.controller.SetWriteDeadline(time.Now().Add(exportWriteTimeout))
+	}
+}
+
+// fail reports an export error. Once part of the export has been sent the
+// status can no longer change, so the connection is aborted instead, which
+// keeps clients from mistaking a truncated file for a complete one.
+func (w *exportWriter) fail(err error, written int) {
+	slog.Error("book export failed", "error", err, "booksWritten", written)
+	if w.started {
+		panic(http.ErrAbortHandler)
+	}
+
+	statusErr := problem.New(http.StatusInternalServerError, "export failed")
+	statusErr.RequestID = domain.RequestIDFromContext(w.ctx.Context())
+	w.ctx.SetHeader("Content-Type", problem.ContentType)
+	w.ctx.SetStatus(statusErr.GetStatus())
+	_ = json.NewEncoder(w.ctx.BodyWriter()).Encode(statusErr)
+}
+
+// bookEncoder writes books in one export format. Output may be buffered
+// until Flush.
+type bookEncoder interface {
+	Begin() error
+	Encode(book openapi.Book) error
+	End() error
+	Flush() error
+}
+
+var csvHeader = []string{"id", "title", "author", "isbn", "price", "currency", "stock", "createdAt", "updatedAt", "version", "deletedAt"}
+
+type csvBookEncoder struct {
+	w *csv.Writer
+}
+
+func newCSVBookEncoder(w io.Writer) bookEncoder {
+	return &csvBookEncoder{w: csv.NewWriter(w)}
+}
+
+func (e *csvBookEncoder) Begin() error {
+	return e.w.Write(csvHeader)
+}
+
+func (e *csvBookEncoder) Encode(book openapi.Book) error {
+	deletedAt := ""
+	if book.DeletedAt != nil {
+		deletedAt = book.DeletedAt.Format(time.RFC3339Nano)
+	}
+	return e.w.Write([]string{
+		book.Id.String(),
+		book.Title,
+		book.Author,
+		derefString(book.Isbn),
+		book.Price,
+		book.Currency,
+		strconv.Itoa(book.Stock),
+		book.CreatedAt.Format(time.RFC3339Nano),
+		book.UpdatedAt.Format(time.RFC3339Nano),
+		strconv.FormatInt(book.Version, 10),
+		deletedAt,
+	})
+}
+
+func (e *csvBookEncoder) End() error {
+	return nil
+}
+
+func (e *csvBookEncoder) Flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type ndjsonBookEncoder struct {
+	buf *bufio.Writer
+	enc *json.Encoder
+}
+
+func newNDJSONBookEncoder(w io.Writer) bookEncoder {
+	buf := bufio.NewWriter(w)
+	return &ndjsonBookEncoder{buf: buf, enc: json.NewEncoder(buf)}
+}
+
+func (e *ndjsonBookEncoder) Begin() error {
+	return nil
+}
+
+func (e *ndjsonBookEncoder) Encode(book openapi.Book) error {
+	return e.enc.Encode(book)
+}
+
+func (e *ndjsonBookEncoder) End() error {
+	return nil
+}
+
+func (e *ndjsonBookEncoder) Flush() error {
+	return e.buf.Flush()
+}
+
+// jsonBookEncoder writes a single JSON array of books.
+type jsonBookEncoder struct {
+	buf   *bufio.Writer
+	count int
+}
+
+func newJSONBookEncoder(w io.Writer) bookEncoder {
+	return &jsonBookEncoder{buf: bufio.NewWriter(w)}
+}
+
+func (e *jsonBookEncoder) Begin() error {
+	_, err := e.buf.WriteString("[")
+	return err
+}
+
+func (e *jsonBookEncoder) Encode(book openapi.Book) error {
+	data, err := json.Marshal(book)
+	if err != nil {
+		return err
+	}
+	if e.count > 0 {
+		if err := e.buf.WriteByte(','); err != nil {
+			return err
+		}
+	}
+	e.count++
+	_, err = e.buf.Write(data)
+	return err
+}
+
+func (e *jsonBookEncoder) End() error {
+	_, err := e.buf.WriteString("]\n")
+	return err
+}
+
+func (e *jsonBookEncoder) Flush() error {
+	return e.buf.Flush()
+}
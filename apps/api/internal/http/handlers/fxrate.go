@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/example/bookapi/internal/domain"
+	"github.com/example/bookapi/internal/service"
+	"github.com/example/bookapi/openapi"
+)
+
+type LoadFXRatesInput struct {
+	Body openapi.FxRatesLoad `body:""`
+}
+
+type FXRatesOutput struct {
+	Body struct {
+		Rates []openapi.FxRate `json:"rates"`
+	}
+}
+
+func RegisterFXRateRoutes(api huma.API, handler *BookHandler) {
+	huma.Register(api, huma.Operation{
+		OperationID:   "list-fx-rates",
+		Method:        http.MethodGet,
+		Path:          "/fx-rates",
+		Summary:       "List exchange rates",
+		DefaultStatus: http.StatusOK,
+	}, handler.listFXRates)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "load-fx-rates",
+		Method:        http.MethodPost,
+		Path:          "/fx-rates:load",
+		Summary:       "Load exchange rates",
+		DefaultStatus: http.StatusOK,
+		Errors:        []int{http.StatusBadRequest},
+	}, handler.loadFXRates)
+}
+
+func (h *BookHandler) listFXRates(ctx context.Context, _ *struct{}) (*FXRatesOutput, error) {
+	rates, err := h.service.ListFXRates(ctx)
+	if err != nil {
+		return nil, apiError(ctx, err)
+	}
+	return toFXRatesOutput(rates), nil
+}
+
+func (h *BookHandler) loadFXRates(ctx context.Context, input *LoadFXRatesInput) (*FXRatesOutput, error) {
+	rates, err := h.service.LoadFXRates(ctx, service.FXRatesInput{
+		Base:  input.Body.Base,
+		Rates: input.Body.Rates,
+	})
+	if err != nil {
+		return nil, apiError(ctx, err)
+	}
+	return toFXRatesOutput(rates), nil
+}
+
+// quotePrices sets quotedPrice on each of result, the converted books, when
+// a currency was requested.
+func (h *BookHandler) quotePrices(ctx context.Context, currency string, books []domain.Book, result []openapi.Book) error {
+	if currency == "" || len(books) == 0 {
+		return nil
+	}
+	quotes, err := h.service.QuotePrices(ctx, currency, books)
+	if err != nil {
+		return apiError(ctx, err)
+	}
+	for i, quote := range quotes {
+		quoted := &openapi.QuotedPrice{
+			Price:    quote.Price.String(),
+			Currency: quote.Price.Currency,
+			Kind:     openapi.PriceKind(quote.Kind),
+		}
+		if quote.Rate != nil {
+			rate := domain.FormatRate(quote.Rate)
+			quoted.Rate = &rate
+		}
+		result[i].QuotedPrice = quoted
+	}
+	return nil
+}
+
+func toFXRatesOutput(rates []domain.FXRate) *FXRatesOutput {
+	output := &FXRatesOutput{}
+	output.Body.Rates = make([]openapi.FxRate, 0, len(rates))
+	for _, rate := range rates {
+		output.Body.Rates = append(output.Body.Rates, openapi.FxRate{
+			Base:      rate.Base,
+			Quote:     rate.Quote,
+			Rate:      rate.Rate.String(),
+			UpdatedAt: rate.UpdatedAt,
+		})
+	}
+	return output
+}
+
+func toOpenAPIPrices(prices []domain.Money) *[]openapi.Price {
+	if len(prices) == 0 {
+		return nil
+	}
+	result := make([]openapi.Price, 0, len(prices))
+	for _, price := range prices {
+		result = append(result, openapi.Price{Price: price.String(), Currency: price.Currency})
+	}
+	return &result
+}
+
+func toServicePrices(prices []openapi.Price) []service.PriceInput {
+	result := make([]service.PriceInput, 0, len(prices))
+	for _, price := range prices {
+		result = append(result, service.PriceInput{Price: price.Price, Currency: price.Currency})
+	}
+	return result
+}
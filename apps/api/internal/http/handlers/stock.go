@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"github.com/example/bookapi/internal/domain"
+	"github.com/example/bookapi/internal/service"
+	"github.com/example/bookapi/openapi"
+)
+
+type AdjustStockInput struct {
+	ID   uuid.UUID                     `path:"id"`
+	Body openapi.StockAdjustmentCreate `body:""`
+}
+
+type AdjustStockOutput struct {
+	ETag string `header:"ETag"`
+	Body openapi.StockAdjustment
+}
+
+type ListStockAdjustmentsInput struct {
+	ID     uuid.UUID `path:"id"`
+	Limit  int       `query:"limit" minimum:"1" maximum:"100" default:"20" doc:"Maximum number of adjustments to return"`
+	Cursor string    `query:"cursor" doc:"Opaque cursor taken from nextCursor of a previous page"`
+}
+
+type ListStockAdjustmentsOutput struct {
+	Body struct {
+		Adjustments []openapi.StockAdjustment `json:"adjustments"`
+		NextCursor  string                    `json:"nextCursor,omitempty"`
+	}
+}
+
+func (h *BookHandler) adjustStock(ctx context.Context, input *AdjustStockInput) (*AdjustStockOutput, error) {
+	serviceInput := service.StockAdjustmentInput{
+		Delta:  input.Body.Delta,
+		Reason: string(input.Body.Reason),
+	}
+	if input.Body.Reference != nil {
+		serviceInput.Reference = *input.Body.Reference
+	}
+
+	adjustment, book, err := h.service.AdjustStock(ctx, input.ID, serviceInput)
+	if err != nil {
+		return nil, apiError(ctx, err)
+	}
+	return &AdjustStockOutput{ETag: versionETag(book.Version), Body: toOpenAPIStockAdjustment(adjustment)}, nil
+}
+
+func (h *BookHandler) listStockAdjustments(ctx context.Context, input *ListStockAdjustmentsInput) (*ListStockAdjustmentsOutput, error) {
+	page, err := h.service.ListStockAdjustments(ctx, input.ID, service.StockLedgerInput{
+		Limit:  input.Limit,
+		Cursor: input.Cursor,
+	})
+	if err != nil {
+		return nil, apiError(ctx, err)
+	}
+
+	output := &ListStockAdjustmentsOutput{}
+	output.Body.Adjustments = make([]openapi.StockAdjustment, 0, len(page.Adjustments))
+	for _, adjustment := range page.Adjustments {
+		output.Body.Adjustments = append(output.Body.Adjustments, toOpenAPIStockAdjustment(adjustment))
+	}
+	output.Body.NextCursor = page.NextCursor
+	return output, nil
+}
+
+func toOpenAPIStockAdjustment(adjustment domain.StockAdjustment) openapi.StockAdjustment {
+	return openapi.StockAdjustment{
+		Id:          openapi_types.UUID(adjustment.ID),
+		BookId:      openapi_types.UUID(adjustment.BookID),
+		Delta:       adjustment.Delta,
+		Reason:      openapi.StockReason(adjustment.Reason),
+		Reference:   adjustment.Reference,
+		Actor:       adjustment.Actor,
+		StockAfter:  adjustment.StockAfter,
+		BookVersion: adjustment.BookVersion,
+		CreatedAt:   adjustment.CreatedAt,
+	}
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"github.com/example/bookapi/internal/domain"
+	"github.com/example/bookapi/internal/service"
+	"github.com/example/bookapi/openapi"
+)
+
+type TagIDInput struct {
+	ID uuid.UUID `path:"id"`
+}
+
+type CreateTagInput struct {
+	Body openapi.TagCreate `body:""`
+}
+
+type UpdateTagInput struct {
+	ID   uuid.UUID         `path:"id"`
+	Body openapi.TagUpdate `body:""`
+}
+
+type TagOutput struct {
+	Body openapi.Tag
+}
+
+type ListTagsInput struct {
+	Prefix string `query:"prefix" maxLength:"50" doc:"Only return tags starting with this prefix, ignoring case"`
+}
+
+type ListTagsOutput struct {
+	Body struct {
+		Tags []openapi.Tag `json:"tags"`
+	}
+}
+
+func RegisterTagRoutes(api huma.API, handler *BookHandler) {
+	huma.Register(api, huma.Operation{
+		OperationID:   "list-tags",
+		Method:        http.MethodGet,
+		Path:          "/tags",
+		Summary:       "List tags",
+		DefaultStatus: http.StatusOK,
+	}, handler.listTags)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "create-tag",
+		Method:        http.MethodPost,
+		Path:          "/tags",
+		Summary:       "Create tag",
+		DefaultStatus: http.StatusCreated,
+		Errors:        []int{http.StatusBadRequest, http.StatusConflict},
+	}, handler.createTag)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "update-tag",
+		Method:        http.MethodPut,
+		Path:          "/tags/{id}",
+		Summary:       "Rename tag",
+		DefaultStatus: http.StatusOK,
+		Errors:        []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict},
+	}, handler.updateTag)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "delete-tag",
+		Method:        http.MethodDelete,
+		Path:          "/tags/{id}",
+		Summary:       "Delete tag",
+		DefaultStatus: http.StatusNoContent,
+		Errors:        []int{http.StatusNotFound, http.StatusConflict},
+	}, handler.deleteTag)
+}
+
+func (h *BookHandler) listTags(ctx context.Context, input *ListTagsInput) (*ListTagsOutput, error) {
+	tags, err := h.service.ListTags(ctx, input.Prefix)
+	if err != nil {
+		return nil, apiError(ctx, err)
+	}
+
+	output := &ListTagsOutput{}
+	output.Body.Tags = make([]openapi.Tag, 0, len(tags))
+	for _, tag := range tags {
+		output.Body.Tags = append(output.Body.Tags, toOpenAPITag(tag))
+	}
+	return output, nil
+}
+
+func (h *BookHandler) createTag(ctx context.Context, input *CreateTagInput) (*TagOutput, error) {
+	tag, err := h.service.CreateTag(ctx, service.TagInput{Name: input.Body.Name})
+	if err != nil {
+		return nil, apiError(ctx, err)
+	}
+	return &TagOutput{Body: toOpenAPITag(tag)}, nil
+}
+
+func (h *BookHandler) updateTag(ctx context.Context, input *UpdateTagInput) (*TagOutput, error) {
+	tag, err := h.service.UpdateTag(ctx, input.ID, service.TagInput{Name: input.Body.Name})
+	if err != nil {
+		return nil, apiError(ctx, err)
+	}
+	return &TagOutput{Body: toOpenAPITag(tag)}, nil
+}
+
+func (h *BookHandler) deleteTag(ctx context.Context, input *TagIDInput) (*struct{}, error) {
+	if err := h.service.DeleteTag(ctx, input.ID); err != nil {
+		return nil, apiError(ctx, err)
+	}
+	return nil, nil
+}
+
+func toOpenAPITag(tag domain.Tag) openapi.Tag {
+	return openapi.Tag{
+		Id:        openapi_types.UUID(tag.ID),
+		Name:      tag.Name,
+		CreatedAt: tag.CreatedAt,
+	}
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/example/bookapi/internal/domain"
+)
+
+// ActorHeader names the caller on whose behalf a request is made. It is
+// recorded in the book revision history.
+const ActorHeader = "X-Actor"
+
+const maxActorLength = 200
+
+// Actor stores the caller from the X-Actor header in the request context.
+func Actor(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actor := strings.TrimSpace(r.Header.Get(ActorHeader))
+		if utf8.RuneCountInString(actor) > maxActorLength {
+			actor = string([]rune(actor)[:maxActorLength])
+		}
+		if actor != "" {
+			r = r.WithContext(domain.ContextWithActor(r.Context(), actor))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
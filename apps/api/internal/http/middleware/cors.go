@@ -9,7 +9,16 @@ var allowedHeaders = strings.Join([]string{
 	"Accept",
 	"Authorization",
 	"Content-Type",
+	"If-Match",
 	"Origin",
+	ActorHeader,
+	RequestIDHeader,
+	TenantHeader,
+}, ", ")
+
+var exposedHeaders = strings.Join([]string{
+	"ETag",
+	RequestIDHeader,
 }, ", ")
 
 // CORS adds permissive CORS headers suitable for local development.
@@ -18,6 +27,7 @@ func CORS(next http.Handler) http.Handler {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
 		w.Header().Set("Access-Control-Allow-Headers", allowedHeaders)
+		w.Header().Set("Access-Control-Expose-Headers", exposedHeaders)
 
 		if r.Method == http.MethodOptions {
 			w.WriteHeader(http.StatusNoContent)
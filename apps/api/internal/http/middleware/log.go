@@ -4,6 +4,8 @@ import (
 	"log/slog"
 	"net/http"
 	"time"
+
+	"github.com/example/bookapi/internal/domain"
 )
 
 type responseWriter struct {
@@ -16,6 +18,12 @@ func (rw *responseWriter) WriteHeader(status int) {
 	rw.ResponseWriter.WriteHeader(status)
 }
 
+// Unwrap lets http.ResponseController reach the underlying connection, which
+// streaming handlers use to flush and extend write deadlines.
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // Logger wraps handlers with basic request logging.
 func Logger(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -30,6 +38,7 @@ func Logger(next http.Handler) http.Handler {
 			"path", r.URL.Path,
 			"status", wrapped.status,
 			"duration", duration.String(),
+			"requestId", domain.RequestIDFromContext(r.Context()),
 		)
 	})
 }
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/example/bookapi/internal/domain"
+)
+
+// RequestIDHeader carries the ID of a request. A caller may set it to
+// correlate its logs with ours; the response always carries it.
+const RequestIDHeader = "X-Request-ID"
+
+const maxRequestIDLength = 128
+
+// RequestID gives every request an ID, the caller's from the X-Request-ID
+// header when valid or a new UUID, stores it in the request context and
+// returns it in the X-Request-ID response header.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimSpace(r.Header.Get(RequestIDHeader))
+		if !validRequestID(id) {
+			id = uuid.NewString()
+		}
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(domain.ContextWithRequestID(r.Context(), id)))
+	})
+}
+
+// validRequestID reports whether id is 1 to maxRequestIDLength printable
+// ASCII characters other than spaces, so it is safe to log and echo back.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/bookapi/internal/domain"
+)
+
+func TestRequestID(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{name: "generated when missing"},
+		{name: "caller's ID kept", header: "trace-42", want: "trace-42"},
+		{name: "spaces replaced", header: "trace 42"},
+		{name: "too long replaced", header: strings.Repeat("a", maxRequestIDLength+1)},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var seen string
+			handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seen = domain.RequestIDFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/books", nil)
+			if tc.header != "" {
+				req.Header.Set(RequestIDHeader, tc.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			require.Equal(t, seen, rec.Header().Get(RequestIDHeader))
+			if tc.want != "" {
+				require.Equal(t, tc.want, seen)
+				return
+			}
+			_, err := uuid.Parse(seen)
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/example/bookapi/internal/domain"
+	"github.com/example/bookapi/internal/http/problem"
+)
+
+// TenantHeader names the tenant a request is made for when tenants are not
+// taken from bearer tokens.
+const TenantHeader = "X-Tenant-ID"
+
+// TenantClaim is the bearer token claim naming the tenant.
+const TenantClaim = "tenant_id"
+
+// TenantOptions configures how Tenant resolves the tenant of a request.
+type TenantOptions struct {
+	// TokenSecret, when set, takes the tenant from the TenantClaim of an HS256
+	// bearer token signed with it, and ignores the X-Tenant-ID header.
+	TokenSecret []byte
+	// Required rejects requests that name no tenant instead of serving them
+	// from the catalog of domain.DefaultTenant.
+	Required bool
+}
+
+// Tenant stores the tenant of each request in its context, from the
+// X-Tenant-ID header or, with a TokenSecret, from the bearer token. Requests
+// with an invalid tenant or token are rejected before reaching next.
+func Tenant(options TenantOptions) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var tenant string
+			if len(options.TokenSecret) > 0 {
+				token, ok := bearerToken(r)
+				if !ok && options.Required {
+					writeError(w, r, http.StatusUnauthorized, "a bearer token naming the tenant is required")
+					return
+				}
+				if ok {
+					var err error
+					if tenant, err = tenantFromToken(token, options.TokenSecret, time.Now()); err != nil {
+						writeError(w, r, http.StatusUnauthorized, err.Error())
+						return
+					}
+				}
+			} else {
+				tenant = strings.TrimSpace(r.Header.Get(TenantHeader))
+				if tenant == "" && options.Required {
+					writeError(w, r, http.StatusBadRequest, "the "+TenantHeader+" header is required")
+					return
+				}
+			}
+
+			if tenant != "" {
+				if !domain.ValidTenantID(tenant) {
+					writeError(w, r, http.StatusBadRequest, "invalid tenant ID")
+					return
+				}
+				r = r.WithContext(domain.ContextWithTenant(r.Context(), tenant))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// tenantFromToken verifies an HS256 JWT signed with secret and returns its
+// TenantClaim. Tokens past their exp claim are rejected.
+func tenantFromToken(token string, secret []byte, now time.Time) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", errors.New("malformed bearer token")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil || header.Alg != "HS256" {
+		return "", errors.New("bearer token must be signed with HS256")
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", errors.New("malformed bearer token")
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return "", errors.New("invalid bearer token signature")
+	}
+
+	var claims struct {
+		Tenant    string   `json:"tenant_id"`
+		ExpiresAt *float64 `json:"exp"`
+	}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return "", errors.New("malformed bearer token")
+	}
+	if claims.ExpiresAt != nil && !now.Before(time.Unix(int64(*claims.ExpiresAt), 0)) {
+		return "", errors.New("bearer token has expired")
+	}
+	if claims.Tenant == "" {
+		return "", errors.New("bearer token has no " + TenantClaim + " claim")
+	}
+	return claims.Tenant, nil
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// writeError writes an error in the same shape as the API's own errors.
+func writeError(w http.ResponseWriter, r *http.Request, status int, message string) {
+	problem.Write(r.Context(), w, problem.New(status, message))
+}
@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/bookapi/internal/domain"
+)
+
+func TestTenant(t *testing.T) {
+	t.Parallel()
+
+	secret := []byte("s3cret")
+	now := time.Now()
+	later := now.Add(time.Hour).Unix()
+	earlier := now.Add(-time.Hour).Unix()
+
+	testCases := []struct {
+		name       string
+		options    TenantOptions
+		header     string
+		token      string
+		wantStatus int
+		wantTenant string
+	}{
+		{name: "no tenant falls back to default", wantStatus: http.StatusOK, wantTenant: domain.DefaultTenant},
+		{name: "header", header: "shop-1", wantStatus: http.StatusOK, wantTenant: "shop-1"},
+		{name: "invalid header", header: "shop 1", wantStatus: http.StatusBadRequest},
+		{name: "required header missing", options: TenantOptions{Required: true}, wantStatus: http.StatusBadRequest},
+		{
+			name:       "token claim",
+			options:    TenantOptions{TokenSecret: secret},
+			token:      signToken(t, secret, `{"alg":"HS256","typ":"JWT"}`, `{"tenant_id":"shop-2","exp":`+strconv.FormatInt(later, 10)+`}`),
+			wantStatus: http.StatusOK,
+			wantTenant: "shop-2",
+		},
+		{
+			name:       "token wins over header",
+			options:    TenantOptions{TokenSecret: secret},
+			header:     "shop-1",
+			token:      signToken(t, secret, `{"alg":"HS256"}`, `{"tenant_id":"shop-2"}`),
+			wantStatus: http.StatusOK,
+			wantTenant: "shop-2",
+		},
+		{
+			name:       "header ignored with token secret",
+			options:    TenantOptions{TokenSecret: secret},
+			header:     "shop-1",
+			wantStatus: http.StatusOK,
+			wantTenant: domain.DefaultTenant,
+		},
+		{
+			name:       "wrong signature",
+			options:    TenantOptions{TokenSecret: secret},
+			token:      signToken(t, []byte("other"), `{"alg":"HS256"}`, `{"tenant_id":"shop-2"}`),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "unsigned token",
+			options:    TenantOptions{TokenSecret: secret},
+			token:      encode(`{"alg":"none"}`) + "." + encode(`{"tenant_id":"shop-2"}`) + ".",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "expired token",
+			options:    TenantOptions{TokenSecret: secret},
+			token:      signToken(t, secret, `{"alg":"HS256"}`, `{"tenant_id":"shop-2","exp":`+strconv.FormatInt(earlier, 10)+`}`),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "token without claim",
+			options:    TenantOptions{TokenSecret: secret},
+			token:      signToken(t, secret, `{"alg":"HS256"}`, `{"sub":"someone"}`),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "required token missing",
+			options:    TenantOptions{TokenSecret: secret, Required: true},
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var tenant string
+			handler := Tenant(tc.options)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tenant = domain.TenantFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/books", nil)
+			if tc.header != "" {
+				req.Header.Set(TenantHeader, tc.header)
+			}
+			if tc.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.token)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.wantStatus, rec.Code)
+			require.Equal(t, tc.wantTenant, tenant)
+		})
+	}
+}
+
+func signToken(t *testing.T, secret []byte, header, claims string) string {
+	t.Helper()
+	signed := encode(header) + "." + encode(claims)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func encode(segment string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(segment))
+}
@@ -0,0 +1,148 @@
+// Package problem renders API errors as RFC 9457 problem details (the
+// successor of RFC 7807), served as application/problem+json. Install makes
+// every error huma produces, including its own request validation errors, a
+// Problem.
+package problem
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/example/bookapi/internal/domain"
+)
+
+// ContentType is the media type of problem details.
+const ContentType = "application/problem+json"
+
+// TypeBaseURI prefixes the code of a problem to form its type URI.
+const TypeBaseURI = "https://bookapi.example.com/problems/"
+
+// CodeValidationFailed is the code of problems listing field violations.
+// Other problems take the code of their status, see StatusCode.
+const CodeValidationFailed = "validation_failed"
+
+// Problem is an error response. Code is a stable, machine-readable name of
+// the kind of problem; Violations lists the invalid fields of a request
+// rejected with CodeValidationFailed.
+type Problem struct {
+	Type       string             `json:"type"`
+	Title      string             `json:"title"`
+	Status     int                `json:"status"`
+	Code       string             `json:"code"`
+	Detail     string             `json:"detail,omitempty"`
+	RequestID  string             `json:"requestId,omitempty"`
+	Violations []domain.Violation `json:"violations,omitempty"`
+}
+
+// New returns a problem with the code of status.
+func New(status int, detail string) *Problem {
+	return newProblem(status, StatusCode(status), detail)
+}
+
+// Validation returns a CodeValidationFailed problem listing violations.
+func Validation(status int, violations []domain.Violation) *Problem {
+	p := newProblem(status, CodeValidationFailed, "the request has invalid fields")
+	p.Violations = violations
+	return p
+}
+
+func newProblem(status int, code, detail string) *Problem {
+	return &Problem{
+		Type:   TypeBaseURI + code,
+		Title:  http.StatusText(status),
+		Status: status,
+		Code:   code,
+		Detail: detail,
+	}
+}
+
+// StatusCode returns the code of problems with status: its status text in
+// snake case, such as "not_found" or "precondition_failed".
+func StatusCode(status int) string {
+	text := http.StatusText(status)
+	if text == "" {
+		return "error"
+	}
+	text = strings.ReplaceAll(strings.ToLower(text), "-", " ")
+	return strings.Join(strings.Fields(text), "_")
+}
+
+func (p *Problem) Error() string {
+	if p.Detail == "" {
+		return p.Title
+	}
+	return p.Detail
+}
+
+// GetStatus satisfies huma.StatusError.
+func (p *Problem) GetStatus() int {
+	return p.Status
+}
+
+// ContentType satisfies huma.ContentTypeFilter, so problems are served as
+// application/problem+json.
+func (p *Problem) ContentType(string) string {
+	return ContentType
+}
+
+// Install replaces huma.NewError, which builds the errors handlers return and
+// huma writes itself, with NewError. Call it before registering operations,
+// which take the schema of their error responses from it.
+func Install() {
+	huma.NewError = NewError
+}
+
+// NewError builds a problem like huma.NewError does. Errors carrying a
+// huma.ErrorDetail, which huma's request validation reports, become
+// violations; the messages of other errors are appended to the detail of
+// client errors and left out of server errors.
+func NewError(status int, msg string, errs ...error) huma.StatusError {
+	var violations []domain.Violation
+	var causes []string
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		var detailer huma.ErrorDetailer
+		if errors.As(err, &detailer) {
+			violations = append(violations, violationOf(detailer.ErrorDetail()))
+			continue
+		}
+		causes = append(causes, err.Error())
+	}
+
+	if len(violations) > 0 {
+		p := Validation(status, violations)
+		p.Detail = msg
+		return p
+	}
+	if len(causes) > 0 && status < http.StatusInternalServerError {
+		msg = strings.Join(append([]string{msg}, causes...), ": ")
+	}
+	return New(status, msg)
+}
+
+// Transform is a huma.Transformer that stamps problems with the ID of the
+// request they answer.
+func Transform(ctx huma.Context, _ string, v any) (any, error) {
+	if p, ok := v.(*Problem); ok && p.RequestID == "" {
+		p.RequestID = domain.RequestIDFromContext(ctx.Context())
+	}
+	return v, nil
+}
+
+// Write writes p as the response to a request of ctx, for errors raised
+// outside of huma operations.
+func Write(ctx context.Context, w http.ResponseWriter, p *Problem) {
+	if p.RequestID == "" {
+		p.RequestID = domain.RequestIDFromContext(ctx)
+	}
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(p.Status)
+	_ = json.NewEncoder(w).Encode(p)
+}
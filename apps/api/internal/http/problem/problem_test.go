@@ -0,0 +1,113 @@
+package problem
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/bookapi/internal/domain"
+)
+
+func TestStatusCode(t *testing.T) {
+	require.Equal(t, "not_found", StatusCode(http.StatusNotFound))
+	require.Equal(t, "precondition_required", StatusCode(http.StatusPreconditionRequired))
+	require.Equal(t, "unprocessable_entity", StatusCode(http.StatusUnprocessableEntity))
+	require.Equal(t, "request_entity_too_large", StatusCode(http.StatusRequestEntityTooLarge))
+	require.Equal(t, "error", StatusCode(599))
+}
+
+func TestNewError(t *testing.T) {
+	t.Run("huma validation errors become violations", func(t *testing.T) {
+		err := NewError(http.StatusUnprocessableEntity, "validation failed",
+			&huma.ErrorDetail{Location: "body", Message: "expected required property title to be present"},
+			&huma.ErrorDetail{Location: "body.authors[0].role", Message: `expected value to be one of "author, editor"`},
+			&huma.ErrorDetail{Location: "query.limit", Message: "expected number <= 100", Value: 500},
+			&huma.ErrorDetail{Location: "body.stock", Message: "expected integer"},
+			&huma.ErrorDetail{Location: "body.extra", Message: "unexpected property"},
+		)
+
+		var p *Problem
+		require.ErrorAs(t, err, &p)
+		require.Equal(t, http.StatusUnprocessableEntity, p.GetStatus())
+		require.Equal(t, CodeValidationFailed, p.Code)
+		require.Equal(t, TypeBaseURI+CodeValidationFailed, p.Type)
+		require.Equal(t, "Unprocessable Entity", p.Title)
+		require.Equal(t, "validation failed", p.Detail)
+		require.Equal(t, []domain.Violation{
+			{Field: "title", Code: domain.ViolationRequired, Message: "expected required property title to be present"},
+			{Field: "authors[0].role", Code: domain.ViolationInvalidChoice, Message: `expected value to be one of "author, editor"`},
+			{Field: "limit", Code: domain.ViolationOutOfRange, Message: "expected number <= 100"},
+			{Field: "stock", Code: domain.ViolationInvalidType, Message: "expected integer"},
+			{Field: "extra", Code: domain.ViolationUnknownField, Message: "unexpected property"},
+		}, p.Violations)
+	})
+
+	t.Run("violation codes follow the schema keyword", func(t *testing.T) {
+		minLength, multipleOf := 2, 0.5
+		for code, schema := range map[string]*huma.Schema{
+			domain.ViolationInvalidFormat: {Type: huma.TypeString, Format: "date-time"},
+			domain.ViolationInvalidLength: {Type: huma.TypeString, MinLength: &minLength},
+			domain.ViolationOutOfRange:    {Type: huma.TypeNumber, MultipleOf: &multipleOf},
+			domain.ViolationInvalidChoice: {Type: huma.TypeString, Enum: []any{"a", "b"}},
+			domain.ViolationDuplicate:     {Type: huma.TypeArray, Items: &huma.Schema{Type: huma.TypeString}, UniqueItems: true},
+			domain.ViolationInvalidType:   {Type: huma.TypeBoolean},
+		} {
+			value := map[string]any{
+				huma.TypeString:  "x",
+				huma.TypeNumber:  0.3,
+				huma.TypeArray:   []any{"a", "a"},
+				huma.TypeBoolean: "yes",
+			}[schema.Type]
+			schema.PrecomputeMessages()
+			result := &huma.ValidateResult{}
+			huma.Validate(nil, schema, huma.NewPathBuffer([]byte("body.field"), 0), huma.ModeWriteToServer, value, result)
+			require.Len(t, result.Errors, 1, code)
+
+			var p *Problem
+			require.ErrorAs(t, NewError(http.StatusUnprocessableEntity, "validation failed", result.Errors...), &p)
+			require.Equal(t, code, p.Violations[0].Code, p.Violations[0].Message)
+		}
+
+		err := NewError(http.StatusUnprocessableEntity, "validation failed",
+			&huma.ErrorDetail{Location: "body.isbn", Message: "expected a valid ISBN"},
+		)
+		require.Equal(t, domain.ViolationInvalid, err.(*Problem).Violations[0].Code)
+	})
+
+	t.Run("client errors keep their causes", func(t *testing.T) {
+		err := NewError(http.StatusBadRequest, "unable to parse body", errors.New("unexpected EOF"))
+		require.Equal(t, "bad_request", err.(*Problem).Code)
+		require.Equal(t, "unable to parse body: unexpected EOF", err.Error())
+	})
+
+	t.Run("server errors hide their causes", func(t *testing.T) {
+		err := NewError(http.StatusInternalServerError, "unexpected error occurred", errors.New("dial tcp: connection refused"))
+		require.Equal(t, "internal_server_error", err.(*Problem).Code)
+		require.Equal(t, "unexpected error occurred", err.Error())
+	})
+}
+
+func TestWrite(t *testing.T) {
+	ctx := domain.ContextWithRequestID(context.Background(), "req-1")
+	rec := httptest.NewRecorder()
+	Write(ctx, rec, New(http.StatusUnauthorized, "bearer token has expired"))
+
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+	require.Equal(t, ContentType, rec.Header().Get("Content-Type"))
+	var body map[string]any
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+	require.Equal(t, map[string]any{
+		"type":      TypeBaseURI + "unauthorized",
+		"title":     "Unauthorized",
+		"status":    float64(http.StatusUnauthorized),
+		"code":      "unauthorized",
+		"detail":    "bearer token has expired",
+		"requestId": "req-1",
+	}, body)
+}
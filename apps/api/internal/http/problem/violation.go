@@ -0,0 +1,139 @@
+package problem
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/validation"
+
+	"github.com/example/bookapi/internal/domain"
+)
+
+// locationPrefixes are the parts of the request huma names at the start of
+// the location of a validation error. Fields are reported without them.
+var locationPrefixes = []string{"body", "path", "query", "header", "cookie"}
+
+// keywordCodes gives the violation code of a value failing each JSON Schema
+// keyword huma validates.
+var keywordCodes = map[string]string{
+	"required":             domain.ViolationRequired,
+	"dependentRequired":    domain.ViolationRequired,
+	"additionalProperties": domain.ViolationUnknownField,
+	"type":                 domain.ViolationInvalidType,
+	"format":               domain.ViolationInvalidFormat,
+	"contentEncoding":      domain.ViolationInvalidFormat,
+	"pattern":              domain.ViolationInvalidFormat,
+	"enum":                 domain.ViolationInvalidChoice,
+	"minimum":              domain.ViolationOutOfRange,
+	"exclusiveMinimum":     domain.ViolationOutOfRange,
+	"maximum":              domain.ViolationOutOfRange,
+	"exclusiveMaximum":     domain.ViolationOutOfRange,
+	"multipleOf":           domain.ViolationOutOfRange,
+	"minLength":            domain.ViolationInvalidLength,
+	"maxLength":            domain.ViolationInvalidLength,
+	"minItems":             domain.ViolationInvalidLength,
+	"maxItems":             domain.ViolationInvalidLength,
+	"minProperties":        domain.ViolationInvalidLength,
+	"maxProperties":        domain.ViolationInvalidLength,
+	"uniqueItems":          domain.ViolationDuplicate,
+}
+
+// keywordMessages pairs the keywords with the message huma reports a
+// failure of them with, see huma/v2/validation. huma does not report the
+// keyword itself, so it is recovered by matching the whole message against
+// these; the first match wins, which puts the formats ahead of the pattern
+// message they would also match.
+var keywordMessages = []struct {
+	keyword string
+	message *regexp.Regexp
+}{
+	{"required", messagePattern(validation.MsgExpectedRequiredProperty)},
+	{"dependentRequired", messagePattern(validation.MsgExpectedDependentRequiredProperty)},
+	{"additionalProperties", messagePattern(validation.MsgUnexpectedProperty)},
+	{"type", messagePattern(validation.MsgExpectedBoolean)},
+	{"type", messagePattern(validation.MsgExpectedNumber)},
+	{"type", messagePattern(validation.MsgExpectedInteger)},
+	{"type", messagePattern(validation.MsgExpectedString)},
+	{"type", messagePattern(validation.MsgExpectedArray)},
+	{"type", messagePattern(validation.MsgExpectedObject)},
+	{"format", messagePattern(validation.MsgExpectedRFC3339DateTime)},
+	{"format", messagePattern(validation.MsgExpectedRFC1123DateTime)},
+	{"format", messagePattern(validation.MsgExpectedRFC3339Date)},
+	{"format", messagePattern(validation.MsgExpectedRFC3339Time)},
+	{"format", messagePattern(validation.MsgExpectedRFC5322Email)},
+	{"format", messagePattern(validation.MsgExpectedRFC5890Hostname)},
+	{"format", messagePattern(validation.MsgExpectedRFC2673IPv4)},
+	{"format", messagePattern(validation.MsgExpectedRFC2373IPv6)},
+	{"format", messagePattern(validation.MsgExpectedRFC3986URI)},
+	{"format", messagePattern(validation.MsgExpectedRFC4122UUID)},
+	{"format", messagePattern(validation.MsgExpectedRFC6570URITemplate)},
+	{"format", messagePattern(validation.MsgExpectedRFC6901JSONPointer)},
+	{"format", messagePattern(validation.MsgExpectedRFC6901RelativeJSONPointer)},
+	{"format", messagePattern(validation.MsgExpectedRegexp)},
+	{"contentEncoding", messagePattern(validation.MsgExpectedBase64String)},
+	{"pattern", messagePattern(validation.MsgExpectedMatchPattern)},
+	{"pattern", messagePattern(validation.MsgExpectedBePattern)},
+	{"enum", messagePattern(validation.MsgExpectedOneOf)},
+	{"minimum", messagePattern(validation.MsgExpectedMinimumNumber)},
+	{"exclusiveMinimum", messagePattern(validation.MsgExpectedExclusiveMinimumNumber)},
+	{"maximum", messagePattern(validation.MsgExpectedMaximumNumber)},
+	{"exclusiveMaximum", messagePattern(validation.MsgExpectedExclusiveMaximumNumber)},
+	{"multipleOf", messagePattern(validation.MsgExpectedNumberBeMultipleOf)},
+	{"minLength", messagePattern(validation.MsgExpectedMinLength)},
+	{"maxLength", messagePattern(validation.MsgExpectedMaxLength)},
+	{"minItems", messagePattern(validation.MsgExpectedMinItems)},
+	{"maxItems", messagePattern(validation.MsgExpectedMaxItems)},
+	{"minProperties", messagePattern(validation.MsgExpectedMinProperties)},
+	{"maxProperties", messagePattern(validation.MsgExpectedMaxProperties)},
+	{"uniqueItems", messagePattern(validation.MsgExpectedArrayItemsUnique)},
+}
+
+// formatVerbs are the verbs of huma's messages, see messagePattern.
+var formatVerbs = regexp.MustCompile(`%[vds]`)
+
+// messagePattern matches the whole of the messages huma formats from
+// format, capturing the formatted arguments.
+func messagePattern(format string) *regexp.Regexp {
+	return regexp.MustCompile("^" + formatVerbs.ReplaceAllLiteralString(regexp.QuoteMeta(format), "(.*)") + "$")
+}
+
+// violationOf turns a validation error reported by huma into a violation,
+// naming the field by its path within the request part it was found in.
+func violationOf(detail *huma.ErrorDetail) domain.Violation {
+	field := detail.Location
+	keyword, args := schemaKeyword(detail.Message)
+	code, ok := keywordCodes[keyword]
+	if !ok {
+		code = domain.ViolationInvalid
+	}
+	if keyword == "required" {
+		field = joinField(field, args[0])
+	}
+
+	for _, prefix := range locationPrefixes {
+		if rest, ok := strings.CutPrefix(field, prefix+"."); ok {
+			field = rest
+			break
+		}
+	}
+	return domain.Violation{Field: field, Code: code, Message: detail.Message}
+}
+
+// schemaKeyword returns the keyword huma reported message for and the
+// arguments it formatted into it, or "" for messages of no known keyword.
+func schemaKeyword(message string) (string, []string) {
+	for _, k := range keywordMessages {
+		if match := k.message.FindStringSubmatch(message); match != nil {
+			return k.keyword, match[1:]
+		}
+	}
+	return "", nil
+}
+
+func joinField(parent, property string) string {
+	if parent == "" {
+		return property
+	}
+	return parent + "." + property
+}
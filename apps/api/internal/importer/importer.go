@@ -0,0 +1,195 @@
+// Package importer loads book catalogs from CSV or NDJSON files through the
+// book service, so imported books are validated like books created over HTTP.
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/example/bookapi/internal/domain"
+	"github.com/example/bookapi/internal/service"
+)
+
+const defaultCheckpointEvery = 100
+
+// BookImporter upserts a single book; service.BookService implements it.
+type BookImporter interface {
+	ImportBook(ctx context.Context, input service.BookCreateInput, dryRun bool) (domain.Book, service.ImportAction, error)
+}
+
+type Options struct {
+	// DryRun validates every record and reports what would change without
+	// writing anything.
+	DryRun bool
+	// Progress, when set, skips the records it has already imported and is
+	// saved as the import advances. It is not used on dry runs.
+	Progress *Progress
+	// CheckpointEvery is the number of records between progress saves.
+	CheckpointEvery int
+}
+
+// Failure is a record that was not imported.
+type Failure struct {
+	Record int
+	Line   int
+	Fields map[string]service.FieldError
+}
+
+// Report summarizes an import.
+type Report struct {
+	DryRun bool
+	// Resumed is the number of records skipped because an earlier run had
+	// already imported them.
+	Resumed   int
+	Created   int
+	Updated   int
+	Unchanged int
+	Failures  []Failure
+}
+
+// Run imports every record of reader. Invalid records are reported in
+// Failures and skipped. Any other error stops the import after saving
+// progress, so a re-run continues with the record that failed. Because
+// books are upserted, records imported after the last checkpoint are
+// harmlessly imported again on resume.
+func Run(ctx context.Context, reader Reader, importer BookImporter, opts Options) (Report, error) {
+	report := Report{DryRun: opts.DryRun}
+	progress := opts.Progress
+	if opts.DryRun {
+		progress = nil
+	}
+	checkpointEvery := opts.CheckpointEvery
+	if checkpointEvery <= 0 {
+		checkpointEvery = defaultCheckpointEvery
+	}
+
+	done := 0
+	if progress != nil {
+		done = progress.Done
+	}
+	saved := done
+	checkpoint := func() error {
+		if progress == nil || done == saved {
+			return nil
+		}
+		if err := progress.Save(done); err != nil {
+			return err
+		}
+		saved = done
+		return nil
+	}
+	stop := func(err error) (Report, error) {
+		return report, errors.Join(err, checkpoint())
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return stop(err)
+		}
+		record, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return stop(err)
+		}
+		if record.Number <= done {
+			report.Resumed++
+			continue
+		}
+
+		if err := importRecord(ctx, importer, record, &report, opts.DryRun); err != nil {
+			return stop(fmt.Errorf("record %d (line %d): %w", record.Number, record.Line, err))
+		}
+		done = record.Number
+		if done-saved >= checkpointEvery {
+			if err := checkpoint(); err != nil {
+				return report, err
+			}
+		}
+	}
+
+	if progress != nil {
+		if err := progress.Remove(); err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+// importRecord adds the outcome of one record to report. Only errors that
+// should stop the whole import are returned.
+func importRecord(ctx context.Context, importer BookImporter, record Record, report *Report, dryRun bool) error {
+	err := record.Err
+	var action service.ImportAction
+	if err == nil {
+		_, action, err = importer.ImportBook(ctx, record.Input, dryRun)
+	}
+
+	var validationErr service.ValidationError
+	switch {
+	case err == nil:
+	case errors.As(err, &validationErr):
+		report.Failures = append(report.Failures, Failure{Record: record.Number, Line: record.Line, Fields: validationErr.Fields})
+		return nil
+	case errors.Is(err, domain.ErrAmbiguousBook):
+		report.Failures = append(report.Failures, Failure{Record: record.Number, Line: record.Line, Fields: map[string]service.FieldError{
+			"title": {Code: domain.ViolationInvalid, Message: err.Error()},
+		}})
+		return nil
+	default:
+		return err
+	}
+
+	switch action {
+	case service.ImportCreated:
+		report.Created++
+	case service.ImportUpdated:
+		report.Updated++
+	case service.ImportUnchanged:
+		report.Unchanged++
+	}
+	return nil
+}
+
+// Write prints the summary and one line per failed record.
+func (r Report) Write(out io.Writer) error {
+	verb := "Imported"
+	if r.DryRun {
+		verb = "Dry run, nothing written; would import"
+	}
+	fmt.Fprintf(out, "%s: %d created, %d updated, %d unchanged, %d invalid", verb, r.Created, r.Updated, r.Unchanged, len(r.Failures))
+	if r.Resumed > 0 {
+		fmt.Fprintf(out, " (resumed after %d records)", r.Resumed)
+	}
+	fmt.Fprintln(out)
+
+	if len(r.Failures) == 0 {
+		return nil
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RECORD\tLINE\tERRORS")
+	for _, failure := range r.Failures {
+		fmt.Fprintf(w, "%d\t%d\t%s\n", failure.Record, failure.Line, formatFields(failure.Fields))
+	}
+	return w.Flush()
+}
+
+func formatFields(fields map[string]service.FieldError) string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, name+": "+fields[name].Message)
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,146 @@
+package importer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/bookapi/internal/domain"
+	"github.com/example/bookapi/internal/repo"
+	"github.com/example/bookapi/internal/service"
+)
+
+func TestReader_CSVMapping(t *testing.T) {
+	mapping, err := ParseMapping("title=Book Title, price=RRP ,stock=Qty,isbn=EAN")
+	require.NoError(t, err)
+
+	source := "Book Title,Author,RRP,Currency,Qty,EAN\n" +
+		"Dune,Frank Herbert,9.99,usd,3,978-0-441-17271-9\n" +
+		"\"Neuromancer, Special Edition\",William Gibson,1500,JPY,many,\n"
+	reader, err := NewReader(strings.NewReader(source), FormatCSV, mapping)
+	require.NoError(t, err)
+
+	record, err := reader.Next()
+	require.NoError(t, err)
+	require.NoError(t, record.Err)
+	require.Equal(t, 1, record.Number)
+	require.Equal(t, 2, record.Line)
+	require.Equal(t, service.BookCreateInput{Title: "Dune", Author: "Frank Herbert", ISBN: "978-0-441-17271-9", Price: "9.99", Currency: "usd", Stock: 3}, record.Input)
+
+	record, err = reader.Next()
+	require.NoError(t, err)
+	require.Equal(t, "Neuromancer, Special Edition", record.Input.Title)
+	var validationErr service.ValidationError
+	require.ErrorAs(t, record.Err, &validationErr)
+	require.Contains(t, validationErr.Fields, "stock")
+
+	_, err = NewReader(strings.NewReader("Title,Author\n"), FormatCSV, nil)
+	require.ErrorContains(t, err, `no column "price"`)
+
+	_, err = ParseMapping("publisher=Imprint")
+	require.Error(t, err)
+}
+
+func TestReader_NDJSON(t *testing.T) {
+	source := `{"name": "Dune", "author": "Frank Herbert", "price": 9.99, "stock": 3}
+
+{"name": "Neuromancer", "author": "William Gibson", "price": "12.50", "currency": "EUR"}
+not json
+`
+	reader, err := NewReader(strings.NewReader(source), FormatNDJSON, Mapping{FieldTitle: "name"})
+	require.NoError(t, err)
+
+	record, err := reader.Next()
+	require.NoError(t, err)
+	require.Equal(t, service.BookCreateInput{Title: "Dune", Author: "Frank Herbert", Price: "9.99", Stock: 3}, record.Input)
+
+	record, err = reader.Next()
+	require.NoError(t, err)
+	require.Equal(t, 2, record.Number)
+	require.Equal(t, 3, record.Line)
+	require.Equal(t, "EUR", record.Input.Currency)
+
+	record, err = reader.Next()
+	require.NoError(t, err)
+	require.Error(t, record.Err)
+
+	_, err = reader.Next()
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+	source := "title,author,price,currency,stock\n" +
+		"Dune,Frank Herbert,9.99,USD,3\n" +
+		"Dune Messiah,Frank Herbert,1.234,USD,1\n" +
+		"Neuromancer,William Gibson,1500,JPY,2\n"
+	newReader := func() Reader {
+		reader, err := NewReader(strings.NewReader(source), FormatCSV, nil)
+		require.NoError(t, err)
+		return reader
+	}
+	books := repo.NewMemoryBookRepository()
+	svc := service.NewBookService(books)
+
+	report, err := Run(ctx, newReader(), svc, Options{DryRun: true})
+	require.NoError(t, err)
+	require.Equal(t, 2, report.Created)
+	require.Len(t, report.Failures, 1)
+	require.Equal(t, 3, report.Failures[0].Line)
+	page, err := books.List(ctx, domain.BookQuery{Sort: domain.DefaultBookSort, Limit: 10})
+	require.NoError(t, err)
+	require.Empty(t, page.Books)
+
+	var out bytes.Buffer
+	require.NoError(t, report.Write(&out))
+	require.Contains(t, out.String(), "would import: 2 created, 0 updated, 0 unchanged, 1 invalid")
+	require.Contains(t, out.String(), "price: must have at most 2 decimal places for USD")
+
+	// The first run stops at the third record; the second resumes there.
+	progress, err := LoadProgress(filepath.Join(t.TempDir(), "books.csv.progress"), "books.csv")
+	require.NoError(t, err)
+	failing := &failingImporter{BookImporter: svc, failOn: "Neuromancer"}
+	report, err = Run(ctx, newReader(), failing, Options{Progress: progress, CheckpointEvery: 1})
+	require.ErrorContains(t, err, "record 3 (line 4)")
+	require.Equal(t, 1, report.Created)
+
+	progress, err = LoadProgress(progress.path, "books.csv")
+	require.NoError(t, err)
+	require.Equal(t, 2, progress.Done)
+	report, err = Run(ctx, newReader(), svc, Options{Progress: progress})
+	require.NoError(t, err)
+	require.Equal(t, 2, report.Resumed)
+	require.Equal(t, 1, report.Created)
+	_, err = os.Stat(progress.path)
+	require.True(t, os.IsNotExist(err))
+
+	report, err = Run(ctx, newReader(), svc, Options{})
+	require.NoError(t, err)
+	require.Equal(t, 2, report.Unchanged)
+	require.Len(t, report.Failures, 1)
+
+	_, err = LoadProgress(progress.path, "other.csv")
+	require.NoError(t, err)
+	require.NoError(t, progress.Save(1))
+	_, err = LoadProgress(progress.path, "other.csv")
+	require.ErrorContains(t, err, "belongs to")
+}
+
+type failingImporter struct {
+	BookImporter
+	failOn string
+}
+
+func (f *failingImporter) ImportBook(ctx context.Context, input service.BookCreateInput, dryRun bool) (domain.Book, service.ImportAction, error) {
+	if input.Title == f.failOn {
+		return domain.Book{}, "", errors.New("database unavailable")
+	}
+	return f.BookImporter.ImportBook(ctx, input, dryRun)
+}
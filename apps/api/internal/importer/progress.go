@@ -0,0 +1,81 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Progress remembers how many records of a source have been imported so an
+// interrupted import can resume. It is stored as JSON next to the source by
+// default.
+type Progress struct {
+	path string
+	// Source identifies the file being imported; progress saved for another
+	// source is not reused.
+	Source string `json:"source"`
+	// Done is the number of leading records that have been imported.
+	Done int `json:"done"`
+}
+
+// LoadProgress reads the progress stored at path for source. A missing file
+// starts from the beginning.
+func LoadProgress(path, source string) (*Progress, error) {
+	progress := &Progress{path: path, Source: source}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return progress, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read import progress: %w", err)
+	}
+
+	var stored Progress
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("read import progress %s: %w", path, err)
+	}
+	if stored.Source != source {
+		return nil, fmt.Errorf("import progress %s belongs to %q, not %q; remove it to start over", path, stored.Source, source)
+	}
+	progress.Done = stored.Done
+	return progress, nil
+}
+
+// Save records that the first done records have been imported. The file is
+// replaced atomically so a crash never leaves it half written.
+func (p *Progress) Save(done int) error {
+	p.Done = done
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(p.path), filepath.Base(p.path)+".*")
+	if err != nil {
+		return fmt.Errorf("save import progress: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("save import progress: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("save import progress: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), p.path); err != nil {
+		return fmt.Errorf("save import progress: %w", err)
+	}
+	return nil
+}
+
+// Remove deletes the progress file once the import has finished.
+func (p *Progress) Remove() error {
+	if err := os.Remove(p.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("remove import progress: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,255 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/example/bookapi/internal/domain"
+	"github.com/example/bookapi/internal/service"
+)
+
+// Supported input formats.
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+)
+
+// Book fields that can be mapped to source columns.
+const (
+	FieldTitle    = "title"
+	FieldAuthor   = "author"
+	FieldISBN     = "isbn"
+	FieldPrice    = "price"
+	FieldCurrency = "currency"
+	FieldStock    = "stock"
+)
+
+var fields = []string{FieldTitle, FieldAuthor, FieldISBN, FieldPrice, FieldCurrency, FieldStock}
+
+// requiredFields must be present as columns in a CSV header. Books without
+// an ISBN column have none, currency falls back to USD and stock to zero when
+// their columns are missing.
+var requiredFields = []string{FieldTitle, FieldAuthor, FieldPrice}
+
+// maxLineBytes bounds a single NDJSON line.
+const maxLineBytes = 1 << 20
+
+// Mapping maps book fields to the column (CSV) or key (NDJSON) they are read
+// from. Fields without an entry are read from a column named like the field.
+type Mapping map[string]string
+
+// ParseMapping parses a comma-separated list of field=column pairs, e.g.
+// "title=Book Title,price=RRP".
+func ParseMapping(spec string) (Mapping, error) {
+	mapping := Mapping{}
+	if strings.TrimSpace(spec) == "" {
+		return mapping, nil
+	}
+	for _, pair := range strings.Split(spec, ",") {
+		field, column, ok := strings.Cut(pair, "=")
+		field = strings.ToLower(strings.TrimSpace(field))
+		column = strings.TrimSpace(column)
+		if !ok || column == "" {
+			return nil, fmt.Errorf("mapping %q: want field=column", pair)
+		}
+		if !isField(field) {
+			return nil, fmt.Errorf("mapping %q: unknown field %q (want one of %s)", pair, field, strings.Join(fields, ", "))
+		}
+		if _, dup := mapping[field]; dup {
+			return nil, fmt.Errorf("mapping %q: field %q is mapped twice", pair, field)
+		}
+		mapping[field] = column
+	}
+	return mapping, nil
+}
+
+func (m Mapping) column(field string) string {
+	if column, ok := m[field]; ok {
+		return column
+	}
+	return field
+}
+
+func isField(name string) bool {
+	for _, field := range fields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Record is one book read from the source. Number counts records from 1 and
+// is what import progress is tracked by; Line is where the record starts in
+// the file. Err holds a service.ValidationError when a value could not be
+// converted, in which case Input is incomplete.
+type Record struct {
+	Number int
+	Line   int
+	Input  service.BookCreateInput
+	Err    error
+}
+
+// Reader yields records until it returns io.EOF.
+type Reader interface {
+	Next() (Record, error)
+}
+
+// NewReader reads records in format from r, taking values from the columns
+// named by mapping.
+func NewReader(r io.Reader, format string, mapping Mapping) (Reader, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVReader(r, mapping)
+	case FormatNDJSON:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxLineBytes)
+		return &ndjsonReader{scanner: scanner, mapping: mapping}, nil
+	default:
+		return nil, fmt.Errorf("unknown format %q (want %s or %s)", format, FormatCSV, FormatNDJSON)
+	}
+}
+
+type csvReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+	number  int
+}
+
+func newCSVReader(r io.Reader, mapping Mapping) (*csvReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("csv: missing header row")
+		}
+		return nil, fmt.Errorf("csv: read header: %w", err)
+	}
+
+	positions := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, dup := positions[name]; !dup {
+			positions[name] = i
+		}
+	}
+
+	columns := make(map[string]int, len(fields))
+	for _, field := range fields {
+		if i, ok := positions[strings.ToLower(mapping.column(field))]; ok {
+			columns[field] = i
+		}
+	}
+	for _, field := range requiredFields {
+		if _, ok := columns[field]; !ok {
+			return nil, fmt.Errorf("csv: no column %q for %s", mapping.column(field), field)
+		}
+	}
+	return &csvReader{reader: reader, columns: columns}, nil
+}
+
+func (r *csvReader) Next() (Record, error) {
+	row, err := r.reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return Record{}, io.EOF
+		}
+		return Record{}, fmt.Errorf("csv: %w", err)
+	}
+	r.number++
+	line, _ := r.reader.FieldPos(0)
+
+	values := make(map[string]string, len(r.columns))
+	for field, i := range r.columns {
+		if i < len(row) {
+			values[field] = row[i]
+		}
+	}
+	input, err := toCreateInput(values)
+	return Record{Number: r.number, Line: line, Input: input, Err: err}, nil
+}
+
+type ndjsonReader struct {
+	scanner *bufio.Scanner
+	mapping Mapping
+	number  int
+	line    int
+}
+
+func (r *ndjsonReader) Next() (Record, error) {
+	for r.scanner.Scan() {
+		r.line++
+		data := bytes.TrimSpace(r.scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		r.number++
+		record := Record{Number: r.number, Line: r.line}
+
+		var object map[string]any
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		if err := decoder.Decode(&object); err != nil {
+			record.Err = service.ValidationError{Fields: map[string]service.FieldError{
+				"record": {Code: domain.ViolationInvalidType, Message: "must be a JSON object"},
+			}}
+			return record, nil
+		}
+
+		values := make(map[string]string, len(fields))
+		invalid := make(map[string]service.FieldError)
+		for _, field := range fields {
+			switch value := object[r.mapping.column(field)].(type) {
+			case nil:
+			case string:
+				values[field] = value
+			case json.Number:
+				values[field] = value.String()
+			default:
+				invalid[field] = service.FieldError{Code: domain.ViolationInvalidType, Message: "must be a string or number"}
+			}
+		}
+		if len(invalid) > 0 {
+			record.Err = service.ValidationError{Fields: invalid}
+			return record, nil
+		}
+		record.Input, record.Err = toCreateInput(values)
+		return record, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return Record{}, fmt.Errorf("ndjson: line %d: %w", r.line+1, err)
+	}
+	return Record{}, io.EOF
+}
+
+// toCreateInput converts raw field values. Price stays a string so the
+// service parses it exactly; only stock needs converting here.
+func toCreateInput(values map[string]string) (service.BookCreateInput, error) {
+	input := service.BookCreateInput{
+		Title:    values[FieldTitle],
+		Author:   values[FieldAuthor],
+		ISBN:     values[FieldISBN],
+		Price:    strings.TrimSpace(values[FieldPrice]),
+		Currency: values[FieldCurrency],
+	}
+	if stock := strings.TrimSpace(values[FieldStock]); stock != "" {
+		parsed, err := strconv.Atoi(stock)
+		if err != nil {
+			return input, service.ValidationError{Fields: map[string]service.FieldError{
+				FieldStock: {Code: domain.ViolationInvalidFormat, Message: "must be an integer"},
+			}}
+		}
+		input.Stock = parsed
+	}
+	return input, nil
+}
@@ -2,29 +2,29 @@ package bookemailer
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
 	"os"
-	"strings"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/ses"
 	"github.com/aws/aws-sdk-go-v2/service/ses/types"
-)
 
-const (
-	expectedType = "BOOK_CREATED"
-	emailBodyTpl = "A new book has been added:\nTitle: %s\nPrice: £%.2f\n\nBook ID: %s\n\nThis is an automated message."
+	"github.com/example/bookapi/internal/domain"
+	bookevents "github.com/example/bookapi/internal/events"
 )
 
-// Handler processes BOOK_CREATED SNS messages and emails end users.
+const emailBodyTpl = "A new book has been added:\nTitle: %s\nPrice: %s %s\n\nBook ID: %s\n\nThis is an automated message."
+
+// Handler processes book created events from SNS and emails end users.
+// With a tenant set, events about the catalogs of other tenants are skipped.
 type Handler struct {
 	sender        EmailSender
 	fallbackEmail string
+	tenant        string
 	logger        *slog.Logger
 }
 
@@ -53,6 +53,7 @@ func New(ctx context.Context) (*Handler, error) {
 			source: fromEmail,
 		},
 		fallbackEmail: os.Getenv("TO_EMAIL"),
+		tenant:        os.Getenv("TENANT_ID"),
 		logger:        logger,
 	}, nil
 }
@@ -72,12 +73,29 @@ func (h *Handler) Handle(ctx context.Context, event events.SNSEvent) error {
 }
 
 func (h *Handler) processRecord(ctx context.Context, record events.SNSEventRecord) error {
-	var msg BookCreatedMessage
-	if err := json.Unmarshal([]byte(record.SNS.Message), &msg); err != nil {
+	event, err := bookevents.Parse([]byte(record.SNS.Message))
+	if err != nil {
 		return fmt.Errorf("parse sns message: %w", err)
 	}
-
-	if err := validateMessage(msg); err != nil {
+	if event.Type != bookevents.TypeBookCreatedV1 {
+		return fmt.Errorf("unexpected event type: %s", event.Type)
+	}
+	// Events published before tenants existed carry none and are about the
+	// default tenant's catalog.
+	tenant := event.TenantID
+	if tenant == "" {
+		tenant = domain.DefaultTenant
+	}
+	if h.tenant != "" && tenant != h.tenant {
+		h.logger.Info("skipping book created event of another tenant",
+			"tenant", tenant,
+			"eventId", event.ID,
+			"messageId", record.SNS.MessageID,
+		)
+		return nil
+	}
+	var msg bookevents.BookCreatedV1
+	if err := event.DecodeData(&msg); err != nil {
 		return err
 	}
 
@@ -91,15 +109,16 @@ func (h *Handler) processRecord(ctx context.Context, record events.SNSEventRecor
 	}
 
 	bookID := msg.BookID
-	price := *msg.Price
 
 	subject := fmt.Sprintf("New book added: %s", msg.Title)
-	body := fmt.Sprintf(emailBodyTpl, msg.Title, price, bookID)
+	body := fmt.Sprintf(emailBodyTpl, msg.Title, msg.Price, msg.Currency, bookID)
 
 	h.logger.Info("attempting to send book created email",
 		"bookId", bookID,
 		"title", msg.Title,
 		"recipient", recipient,
+		"tenant", tenant,
+		"eventId", event.ID,
 		"messageId", record.SNS.MessageID,
 	)
 
@@ -124,30 +143,6 @@ func (h *Handler) processRecord(ctx context.Context, record events.SNSEventRecor
 	return nil
 }
 
-// BookCreatedMessage mirrors the SNS message schema.
-type BookCreatedMessage struct {
-	Type      string   `json:"type"`
-	BookID    string   `json:"bookId"`
-	Title     string   `json:"title"`
-	Price     *float64 `json:"price"`
-	UserEmail string   `json:"userEmail"`
-}
-
-func validateMessage(msg BookCreatedMessage) error {
-	switch {
-	case msg.Type != expectedType:
-		return fmt.Errorf("unexpected message type: %s", msg.Type)
-	case strings.TrimSpace(msg.BookID) == "":
-		return errors.New("bookId must be provided")
-	case msg.Title == "":
-		return errors.New("title must be provided")
-	case msg.Price == nil:
-		return errors.New("price must be provided")
-	default:
-		return nil
-	}
-}
-
 // EmailSender abstracts SES interactions for easier testing.
 type EmailSender interface {
 	Send(ctx context.Context, to, subject, body string) error
@@ -0,0 +1,378 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/example/bookapi/internal/domain"
+	"github.com/example/bookapi/internal/events"
+	"github.com/example/bookapi/internal/service"
+)
+
+const (
+	defaultAsyncWorkers   = 4
+	defaultAsyncQueueSize = 1000
+	defaultAsyncAttempts  = 5
+	defaultInitialBackoff = 200 * time.Millisecond
+	defaultMaxBackoff     = 30 * time.Second
+	// DefaultSpillFile is where AsyncBookEventPublisher appends the events it
+	// could not publish, unless WithSpillFile says otherwise.
+	DefaultSpillFile = "failed-book-events.jsonl"
+	// attemptTimeout bounds a single call to the wrapped publisher.
+	attemptTimeout = 10 * time.Second
+)
+
+// AsyncBookEventPublisher publishes book events in the background so the
+// latency and failures of the wrapped publisher stay off the request path.
+// Events wait in a bounded queue for a pool of workers, which retry failures
+// with exponential backoff and jitter. Events that still fail after the last
+// attempt, that find the queue full or the publisher closed, or that are
+// still queued when Close gives up are appended to a spill file as JSON lines
+// for later redelivery.
+//
+// Every attempt for an event publishes it with the same ID, see
+// events.ContextWithID.
+type AsyncBookEventPublisher struct {
+	next   service.BookEventPublisher
+	logger *slog.Logger
+
+	workers        int
+	queueSize      int
+	attempts       int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	spillPath      string
+	now            func() time.Time
+
+	queue chan asyncEvent
+	// mu guards closed, so no event is sent on queue after Close closes it.
+	mu     sync.RWMutex
+	closed bool
+	// abort is cancelled when Close runs out of time; workers then stop the
+	// attempt in flight and spill what they hold instead of retrying it.
+	abort    context.Context
+	abortNow context.CancelFunc
+	wg       sync.WaitGroup
+	spillMu  sync.Mutex
+}
+
+type asyncEvent struct {
+	ctx     context.Context
+	event   domain.OutboxEvent
+	publish func(ctx context.Context) error
+}
+
+// spilledEvent is a line of the spill file. Type and Payload are those of
+// the outbox event, see domain.OutboxEvent.
+type spilledEvent struct {
+	ID          uuid.UUID       `json:"id"`
+	Type        string          `json:"type"`
+	AggregateID uuid.UUID       `json:"aggregateId"`
+	Actor       string          `json:"actor"`
+	Tenant      string          `json:"tenant"`
+	Payload     json.RawMessage `json:"payload"`
+	CreatedAt   time.Time       `json:"createdAt"`
+	Attempts    int             `json:"attempts"`
+	Error       string          `json:"error"`
+	SpilledAt   time.Time       `json:"spilledAt"`
+}
+
+// AsyncOption configures AsyncBookEventPublisher behavior.
+type AsyncOption func(*AsyncBookEventPublisher)
+
+// WithWorkers sets how many events are published concurrently.
+func WithWorkers(workers int) AsyncOption {
+	return func(p *AsyncBookEventPublisher) {
+		if workers > 0 {
+			p.workers = workers
+		}
+	}
+}
+
+// WithQueueSize sets how many events may wait for a worker.
+func WithQueueSize(size int) AsyncOption {
+	return func(p *AsyncBookEventPublisher) {
+		if size > 0 {
+			p.queueSize = size
+		}
+	}
+}
+
+// WithMaxAttempts sets how many times an event is attempted before it is
+// spilled.
+func WithMaxAttempts(attempts int) AsyncOption {
+	return func(p *AsyncBookEventPublisher) {
+		if attempts > 0 {
+			p.attempts = attempts
+		}
+	}
+}
+
+// WithBackoff sets the delay before the first retry and the cap the
+// doubling delay grows to, before jitter.
+func WithBackoff(initial, max time.Duration) AsyncOption {
+	return func(p *AsyncBookEventPublisher) {
+		if initial > 0 && max >= initial {
+			p.initialBackoff = initial
+			p.maxBackoff = max
+		}
+	}
+}
+
+// WithSpillFile sets the file events that cannot be published are appended
+// to.
+func WithSpillFile(path string) AsyncOption {
+	return func(p *AsyncBookEventPublisher) {
+		if path != "" {
+			p.spillPath = path
+		}
+	}
+}
+
+// NewAsyncBookEventPublisher starts the workers publishing through next.
+// Call Close to drain the queue and stop them.
+func NewAsyncBookEventPublisher(next service.BookEventPublisher, logger *slog.Logger, opts ...AsyncOption) *AsyncBookEventPublisher {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	p := &AsyncBookEventPublisher{
+		next:           next,
+		logger:         logger,
+		workers:        defaultAsyncWorkers,
+		queueSize:      defaultAsyncQueueSize,
+		attempts:       defaultAsyncAttempts,
+		initialBackoff: defaultInitialBackoff,
+		maxBackoff:     defaultMaxBackoff,
+		spillPath:      DefaultSpillFile,
+		now:            time.Now,
+	}
+	p.abort, p.abortNow = context.WithCancel(context.Background())
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	p.queue = make(chan asyncEvent, p.queueSize)
+	p.wg.Add(p.workers)
+	for range p.workers {
+		go p.work()
+	}
+	return p
+}
+
+// PublishBookCreated queues a book created event.
+func (p *AsyncBookEventPublisher) PublishBookCreated(ctx context.Context, book domain.Book) error {
+	event, err := domain.NewBookCreatedEvent(book)
+	return p.enqueue(ctx, event, err, func(ctx context.Context) error {
+		return p.next.PublishBookCreated(ctx, book)
+	})
+}
+
+// PublishBookUpdated queues a book updated event.
+func (p *AsyncBookEventPublisher) PublishBookUpdated(ctx context.Context, update domain.BookUpdate) error {
+	event, err := domain.NewBookUpdatedEvent(update)
+	return p.enqueue(ctx, event, err, func(ctx context.Context) error {
+		return p.next.PublishBookUpdated(ctx, update)
+	})
+}
+
+// PublishBookDeleted queues a book deleted event.
+func (p *AsyncBookEventPublisher) PublishBookDeleted(ctx context.Context, book domain.Book) error {
+	event, err := domain.NewBookDeletedEvent(book)
+	return p.enqueue(ctx, event, err, func(ctx context.Context) error {
+		return p.next.PublishBookDeleted(ctx, book)
+	})
+}
+
+// PublishStockChanged queues a stock changed event.
+func (p *AsyncBookEventPublisher) PublishStockChanged(ctx context.Context, adjustment domain.StockAdjustment) error {
+	event, err := domain.NewStockChangedEvent(adjustment)
+	return p.enqueue(ctx, event, err, func(ctx context.Context) error {
+		return p.next.PublishStockChanged(ctx, adjustment)
+	})
+}
+
+// Close stops accepting events and waits until the queued ones have been
+// published or spilled. When ctx ends first, the events still queued or
+// waiting for a retry are spilled without further attempts and ctx's error
+// is returned.
+func (p *AsyncBookEventPublisher) Close(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.queue)
+	}
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		p.abortNow()
+		return nil
+	case <-ctx.Done():
+		p.abortNow()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// enqueue queues event, built with buildErr, to be published with publish.
+// The request's cancellation does not reach the workers, its values do.
+func (p *AsyncBookEventPublisher) enqueue(ctx context.Context, event domain.OutboxEvent, buildErr error, publish func(ctx context.Context) error) error {
+	if buildErr != nil {
+		return fmt.Errorf("build book event: %w", buildErr)
+	}
+	event.Actor = domain.ActorFromContext(ctx)
+	event.Tenant = domain.TenantFromContext(ctx)
+	queued := asyncEvent{
+		ctx:     events.ContextWithID(context.WithoutCancel(ctx), event.ID.String()),
+		event:   event,
+		publish: publish,
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return p.spill(queued, 0, errors.New("publisher is closed"))
+	}
+	select {
+	case p.queue <- queued:
+		return nil
+	default:
+		return p.spill(queued, 0, errors.New("event queue is full"))
+	}
+}
+
+func (p *AsyncBookEventPublisher) work() {
+	defer p.wg.Done()
+	for queued := range p.queue {
+		p.deliver(queued)
+	}
+}
+
+// deliver publishes queued, retrying failures, and spills it when every
+// attempt failed or Close ran out of time.
+func (p *AsyncBookEventPublisher) deliver(queued asyncEvent) {
+	var err error
+	attempt := 0
+	for attempt < p.attempts {
+		select {
+		case <-p.abort.Done():
+			if err == nil {
+				err = errors.New("shut down before the event was published")
+			}
+			_ = p.spill(queued, attempt, err)
+			return
+		default:
+		}
+
+		attempt++
+		ctx, cancel := context.WithTimeout(queued.ctx, attemptTimeout)
+		stop := context.AfterFunc(p.abort, cancel)
+		err = queued.publish(ctx)
+		stop()
+		cancel()
+		if err == nil {
+			return
+		}
+		if attempt == p.attempts {
+			break
+		}
+
+		delay := p.backoff(attempt)
+		p.logger.Warn("book event publish failed; retrying",
+			"error", err,
+			"eventId", queued.event.ID,
+			"eventType", queued.event.Type,
+			"attempt", attempt,
+			"retryIn", delay,
+		)
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-p.abort.Done():
+			timer.Stop()
+		}
+	}
+	_ = p.spill(queued, attempt, err)
+}
+
+// backoff returns the delay after the given failed attempt: the initial
+// backoff doubled for every earlier failure and capped at the maximum, of
+// which a random half is taken off so that retries of events that failed
+// together spread out.
+func (p *AsyncBookEventPublisher) backoff(attempt int) time.Duration {
+	delay := p.initialBackoff
+	for i := 1; i < attempt && delay < p.maxBackoff; i++ {
+		delay *= 2
+	}
+	delay = min(delay, p.maxBackoff)
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// spill appends queued to the spill file.
+func (p *AsyncBookEventPublisher) spill(queued asyncEvent, attempts int, cause error) error {
+	line, err := json.Marshal(spilledEvent{
+		ID:          queued.event.ID,
+		Type:        queued.event.Type,
+		AggregateID: queued.event.AggregateID,
+		Actor:       queued.event.Actor,
+		Tenant:      queued.event.Tenant,
+		Payload:     queued.event.Payload,
+		CreatedAt:   queued.event.CreatedAt,
+		Attempts:    attempts,
+		Error:       cause.Error(),
+		SpilledAt:   p.now().UTC(),
+	})
+	if err == nil {
+		err = p.appendSpill(append(line, '\n'))
+	}
+	if err != nil {
+		p.logger.Error("failed to spill book event; it is lost",
+			"error", err,
+			"cause", cause,
+			"eventId", queued.event.ID,
+			"eventType", queued.event.Type,
+			"bookId", queued.event.AggregateID,
+		)
+		return fmt.Errorf("spill book event: %w", err)
+	}
+
+	p.logger.Error("book event could not be published; spilled for redelivery",
+		"error", cause,
+		"eventId", queued.event.ID,
+		"eventType", queued.event.Type,
+		"bookId", queued.event.AggregateID,
+		"attempts", attempts,
+		"spillFile", p.spillPath,
+	)
+	return nil
+}
+
+func (p *AsyncBookEventPublisher) appendSpill(line []byte) error {
+	p.spillMu.Lock()
+	defer p.spillMu.Unlock()
+
+	file, err := os.OpenFile(p.spillPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(line); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
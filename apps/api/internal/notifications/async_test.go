@@ -0,0 +1,189 @@
+package notifications
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/bookapi/internal/domain"
+	"github.com/example/bookapi/internal/events"
+)
+
+func TestAsyncBookEventPublisher(t *testing.T) {
+	book := domain.Book{ID: uuid.New(), Title: "Dune", CreatedAt: time.Now()}
+	fastRetries := []AsyncOption{WithMaxAttempts(3), WithBackoff(time.Millisecond, 2*time.Millisecond)}
+
+	t.Run("retries failures with the same event ID", func(t *testing.T) {
+		next := &flakyPublisher{failures: 2}
+		spillFile := filepath.Join(t.TempDir(), "spill.jsonl")
+		publisher := NewAsyncBookEventPublisher(next, nil, append(fastRetries, WithSpillFile(spillFile))...)
+
+		ctx := domain.ContextWithActor(context.Background(), "till-3")
+		require.NoError(t, publisher.PublishBookCreated(ctx, book))
+		require.NoError(t, publisher.Close(context.Background()))
+
+		require.Equal(t, 3, next.calls)
+		require.Len(t, next.eventIDs, 3)
+		require.NotEmpty(t, next.eventIDs[0])
+		require.Equal(t, next.eventIDs[0], next.eventIDs[2])
+		require.Equal(t, []string{"till-3", "till-3", "till-3"}, next.actors)
+		require.NoFileExists(t, spillFile)
+	})
+
+	t.Run("spills events failing every attempt", func(t *testing.T) {
+		next := &flakyPublisher{failures: 100}
+		spillFile := filepath.Join(t.TempDir(), "spill.jsonl")
+		publisher := NewAsyncBookEventPublisher(next, nil, append(fastRetries, WithSpillFile(spillFile))...)
+
+		require.NoError(t, publisher.PublishBookDeleted(context.Background(), book))
+		require.NoError(t, publisher.Close(context.Background()))
+
+		spilled := readSpill(t, spillFile)
+		require.Len(t, spilled, 1)
+		require.Equal(t, domain.EventTypeBookDeleted, spilled[0].Type)
+		require.Equal(t, book.ID, spilled[0].AggregateID)
+		require.Equal(t, 3, spilled[0].Attempts)
+		require.Equal(t, "sns unavailable", spilled[0].Error)
+
+		var payload domain.Book
+		require.NoError(t, json.Unmarshal(spilled[0].Payload, &payload))
+		require.Equal(t, "Dune", payload.Title)
+	})
+
+	t.Run("spills events that find the queue full", func(t *testing.T) {
+		next := &flakyPublisher{started: make(chan struct{}, 1), release: make(chan struct{})}
+		spillFile := filepath.Join(t.TempDir(), "spill.jsonl")
+		publisher := NewAsyncBookEventPublisher(next, nil, WithWorkers(1), WithQueueSize(1), WithSpillFile(spillFile))
+
+		require.NoError(t, publisher.PublishBookCreated(context.Background(), book))
+		<-next.started
+		require.NoError(t, publisher.PublishBookCreated(context.Background(), book))
+		require.NoError(t, publisher.PublishBookCreated(context.Background(), book))
+		close(next.release)
+		require.NoError(t, publisher.Close(context.Background()))
+
+		require.Equal(t, 2, next.calls)
+		spilled := readSpill(t, spillFile)
+		require.Len(t, spilled, 1)
+		require.Equal(t, "event queue is full", spilled[0].Error)
+	})
+
+	t.Run("close spills what it cannot drain in time", func(t *testing.T) {
+		next := &flakyPublisher{release: make(chan struct{})}
+		spillFile := filepath.Join(t.TempDir(), "spill.jsonl")
+		publisher := NewAsyncBookEventPublisher(next, nil, WithWorkers(1), WithSpillFile(spillFile))
+
+		for range 3 {
+			require.NoError(t, publisher.PublishStockChanged(context.Background(), domain.StockAdjustment{BookID: book.ID}))
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		require.ErrorIs(t, publisher.Close(ctx), context.DeadlineExceeded)
+
+		require.NoError(t, publisher.PublishStockChanged(context.Background(), domain.StockAdjustment{BookID: book.ID}))
+		spilled := readSpill(t, spillFile)
+		require.Len(t, spilled, 4)
+		require.Equal(t, "publisher is closed", spilled[3].Error)
+	})
+}
+
+func TestAsyncBookEventPublisherBackoff(t *testing.T) {
+	publisher := NewAsyncBookEventPublisher(&flakyPublisher{}, nil, WithBackoff(time.Second, 10*time.Second))
+	defer publisher.Close(context.Background())
+
+	for range 20 {
+		delay := publisher.backoff(1)
+		require.GreaterOrEqual(t, delay, 500*time.Millisecond)
+		require.LessOrEqual(t, delay, time.Second)
+
+		delay = publisher.backoff(3)
+		require.GreaterOrEqual(t, delay, 2*time.Second)
+		require.LessOrEqual(t, delay, 4*time.Second)
+
+		delay = publisher.backoff(100)
+		require.GreaterOrEqual(t, delay, 5*time.Second)
+		require.LessOrEqual(t, delay, 10*time.Second)
+	}
+}
+
+// flakyPublisher fails its first failures calls. With release set, calls
+// block until it is closed or their context ends, after reporting on
+// started.
+type flakyPublisher struct {
+	failures int
+	started  chan struct{}
+	release  chan struct{}
+
+	mu       sync.Mutex
+	calls    int
+	eventIDs []string
+	actors   []string
+}
+
+func (p *flakyPublisher) PublishBookCreated(ctx context.Context, _ domain.Book) error {
+	return p.publish(ctx)
+}
+
+func (p *flakyPublisher) PublishBookUpdated(ctx context.Context, _ domain.BookUpdate) error {
+	return p.publish(ctx)
+}
+
+func (p *flakyPublisher) PublishBookDeleted(ctx context.Context, _ domain.Book) error {
+	return p.publish(ctx)
+}
+
+func (p *flakyPublisher) PublishStockChanged(ctx context.Context, _ domain.StockAdjustment) error {
+	return p.publish(ctx)
+}
+
+func (p *flakyPublisher) publish(ctx context.Context) error {
+	if p.started != nil {
+		select {
+		case p.started <- struct{}{}:
+		default:
+		}
+	}
+	if p.release != nil {
+		select {
+		case <-p.release:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls++
+	p.eventIDs = append(p.eventIDs, events.IDFromContext(ctx))
+	p.actors = append(p.actors, domain.ActorFromContext(ctx))
+	if p.calls <= p.failures {
+		return errors.New("sns unavailable")
+	}
+	return nil
+}
+
+func readSpill(t *testing.T, path string) []spilledEvent {
+	t.Helper()
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var spilled []spilledEvent
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event spilledEvent
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		spilled = append(spilled, event)
+	}
+	require.NoError(t, scanner.Err())
+	return spilled
+}
@@ -0,0 +1,60 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+)
+
+// NewSNSBookEventPublisherFromEnv builds a publisher for the topic in
+// SNS_TOPIC_ARN, inferring the AWS region from the ARN. It returns nil
+// without error when the variable is unset.
+func NewSNSBookEventPublisherFromEnv(ctx context.Context, logger *slog.Logger) (*SNSBookEventPublisher, error) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	topicARN := strings.TrimSpace(os.Getenv("SNS_TOPIC_ARN"))
+	if topicARN == "" {
+		logger.Warn("SNS_TOPIC_ARN not set; book events will not be published",
+			"envVar", "SNS_TOPIC_ARN",
+		)
+		return nil, nil
+	}
+
+	region, err := snsRegionFromARN(topicARN)
+	if err != nil {
+		logger.Error("unable to derive AWS region from SNS topic ARN; book events disabled",
+			"topicArn", topicARN,
+			"error", err,
+		)
+		return nil, err
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		return nil, fmt.Errorf("load AWS config: %w", err)
+	}
+
+	client := sns.NewFromConfig(cfg)
+	return NewSNSBookEventPublisher(client, topicARN, logger), nil
+}
+
+func snsRegionFromARN(topicARN string) (string, error) {
+	parts := strings.Split(topicARN, ":")
+	if len(parts) < 6 || parts[0] != "arn" {
+		return "", fmt.Errorf("invalid SNS topic ARN: %s", topicARN)
+	}
+
+	region := strings.TrimSpace(parts[3])
+	if region == "" {
+		return "", fmt.Errorf("missing region in SNS topic ARN: %s", topicARN)
+	}
+
+	return region, nil
+}
@@ -5,15 +5,17 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/google/uuid"
 
 	"github.com/example/bookapi/internal/domain"
+	"github.com/example/bookapi/internal/events"
 )
 
-const bookCreatedEventType = "BOOK_CREATED"
-
 // SNSBookEventPublisher publishes book domain events to Amazon SNS.
 type SNSBookEventPublisher struct {
 	client   *sns.Client
@@ -33,44 +35,116 @@ func NewSNSBookEventPublisher(client *sns.Client, topicARN string, logger *slog.
 	}
 }
 
-// PublishBookCreated sends a BOOK_CREATED event for the provided book.
+// PublishBookCreated sends a book created event for the provided book.
 func (p *SNSBookEventPublisher) PublishBookCreated(ctx context.Context, book domain.Book) error {
+	actor := domain.ActorFromContext(ctx)
+	return p.publish(ctx, events.TypeBookCreatedV1, book.ID, book.CreatedAt, events.BookCreatedV1{
+		BookID:    book.ID,
+		Title:     book.Title,
+		Author:    book.Author,
+		ISBN:      book.ISBN,
+		Price:     book.Price.String(),
+		Currency:  book.Price.Currency,
+		Stock:     book.Stock,
+		Version:   book.Version,
+		Actor:     actor,
+		UserEmail: events.EmailOf(actor),
+	})
+}
+
+// PublishBookUpdated sends a book updated event listing the changed fields
+// with their values before and after the update.
+func (p *SNSBookEventPublisher) PublishBookUpdated(ctx context.Context, update domain.BookUpdate) error {
+	changes := make([]events.FieldChangeV1, 0, len(update.Changes))
+	for _, change := range update.Changes {
+		changes = append(changes, events.FieldChangeV1(change))
+	}
+	return p.publish(ctx, events.TypeBookUpdatedV1, update.Book.ID, update.Book.UpdatedAt, events.BookUpdatedV1{
+		BookID:  update.Book.ID,
+		Title:   update.Book.Title,
+		Version: update.Book.Version,
+		Actor:   domain.ActorFromContext(ctx),
+		Changes: changes,
+	})
+}
+
+// PublishBookDeleted sends a book deleted event for the provided book.
+func (p *SNSBookEventPublisher) PublishBookDeleted(ctx context.Context, book domain.Book) error {
+	deletedAt := book.UpdatedAt
+	if book.DeletedAt != nil {
+		deletedAt = *book.DeletedAt
+	}
+	return p.publish(ctx, events.TypeBookDeletedV1, book.ID, deletedAt, events.BookDeletedV1{
+		BookID:    book.ID,
+		Title:     book.Title,
+		Version:   book.Version,
+		Actor:     domain.ActorFromContext(ctx),
+		DeletedAt: deletedAt,
+	})
+}
+
+// PublishStockChanged sends a stock changed event for the provided
+// adjustment.
+func (p *SNSBookEventPublisher) PublishStockChanged(ctx context.Context, adjustment domain.StockAdjustment) error {
+	return p.publish(ctx, events.TypeStockChangedV1, adjustment.BookID, adjustment.CreatedAt, events.StockChangedV1{
+		BookID:    adjustment.BookID,
+		Delta:     adjustment.Delta,
+		Stock:     adjustment.StockAfter,
+		Reason:    string(adjustment.Reason),
+		Reference: adjustment.Reference,
+		Version:   adjustment.BookVersion,
+		Actor:     domain.ActorFromContext(ctx),
+	})
+}
+
+// publish sends data as a CloudEvent of eventType. The event takes its ID
+// from ctx, see events.ContextWithID, and its tenant from ctx too, see
+// domain.TenantFromContext. Its type and tenant are also sent as the type
+// and tenantid message attributes for subscription filter policies.
+func (p *SNSBookEventPublisher) publish(ctx context.Context, eventType string, bookID uuid.UUID, at time.Time, data any) error {
 	if p == nil || p.client == nil || p.topicARN == "" {
 		return fmt.Errorf("sns book event publisher is not fully configured")
 	}
 
-	payload := map[string]any{
-		"type":   bookCreatedEventType,
-		"bookId": book.ID.String(),
-		"title":  book.Title,
-		"price":  book.Price,
+	event, err := events.New(eventType, events.IDFromContext(ctx), bookID.String(), at, data)
+	if err != nil {
+		return err
 	}
-
-	jsonBytes, err := json.Marshal(payload)
+	event.TenantID = domain.TenantFromContext(ctx)
+	jsonBytes, err := json.Marshal(event)
 	if err != nil {
-		return fmt.Errorf("marshal book created payload: %w", err)
+		return fmt.Errorf("marshal %s event: %w", eventType, err)
 	}
 
-	p.logger.Info("attempting to publish book created message",
-		"bookId", book.ID,
+	p.logger.Info("attempting to publish book event message",
+		"eventId", event.ID,
+		"eventType", eventType,
+		"tenant", event.TenantID,
+		"bookId", bookID,
 		"topicArn", p.topicARN,
 	)
 
 	resp, err := p.client.Publish(ctx, &sns.PublishInput{
 		TopicArn: aws.String(p.topicARN),
 		Message:  aws.String(string(jsonBytes)),
+		MessageAttributes: map[string]types.MessageAttributeValue{
+			"type":     {DataType: aws.String("String"), StringValue: aws.String(eventType)},
+			"tenantid": {DataType: aws.String("String"), StringValue: aws.String(event.TenantID)},
+		},
 	})
 	if err != nil {
-		p.logger.Error("failed to publish book created message",
+		p.logger.Error("failed to publish book event message",
 			"error", err,
-			"bookId", book.ID,
+			"eventType", eventType,
+			"bookId", bookID,
 			"topicArn", p.topicARN,
 		)
 		return fmt.Errorf("publish SNS message: %w", err)
 	}
 
-	p.logger.Info("published book created message",
-		"bookId", book.ID,
+	p.logger.Info("published book event message",
+		"eventType", eventType,
+		"bookId", bookID,
 		"topicArn", p.topicARN,
 		"messageId", aws.ToString(resp.MessageId),
 	)
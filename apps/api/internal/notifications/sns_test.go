@@ -0,0 +1,87 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/bookapi/internal/domain"
+	"github.com/example/bookapi/internal/events"
+)
+
+func TestSNSBookEventPublisher(t *testing.T) {
+	var messages, attributes, tenants []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		messages = append(messages, r.PostForm.Get("Message"))
+		for i := 1; r.PostForm.Has(fmt.Sprintf("MessageAttributes.entry.%d.Name", i)); i++ {
+			value := r.PostForm.Get(fmt.Sprintf("MessageAttributes.entry.%d.Value.StringValue", i))
+			switch r.PostForm.Get(fmt.Sprintf("MessageAttributes.entry.%d.Name", i)) {
+			case "type":
+				attributes = append(attributes, value)
+			case "tenantid":
+				tenants = append(tenants, value)
+			}
+		}
+		w.Header().Set("Content-Type", "text/xml")
+		_, _ = w.Write([]byte(`<PublishResponse><PublishResult><MessageId>m-1</MessageId></PublishResult></PublishResponse>`))
+	}))
+	defer server.Close()
+
+	client := sns.New(sns.Options{
+		Region:       "eu-north-1",
+		BaseEndpoint: aws.String(server.URL),
+		Credentials:  aws.AnonymousCredentials{},
+	})
+	publisher := NewSNSBookEventPublisher(client, "arn:aws:sns:eu-north-1:123456789012:books", nil)
+
+	now := time.Date(2025, 11, 10, 12, 0, 0, 0, time.UTC)
+	price, err := domain.ParseMoney("9.99", "GBP")
+	require.NoError(t, err)
+	book := domain.Book{ID: uuid.New(), Title: "Dune", Author: "Frank Herbert", Price: price, Stock: 2, CreatedAt: now, UpdatedAt: now, Version: 1}
+	ctx := domain.ContextWithTenant(domain.ContextWithActor(context.Background(), "reader@example.com"), "shop-a")
+	ctx = events.ContextWithID(ctx, "event-1")
+
+	require.NoError(t, publisher.PublishBookCreated(ctx, book))
+	require.NoError(t, publisher.PublishBookUpdated(ctx, domain.BookUpdate{
+		Book:    book,
+		Changes: []domain.FieldChange{{Field: "stock", Before: 1, After: 2}},
+	}))
+	deleted := book
+	deleted.DeletedAt = &now
+	require.NoError(t, publisher.PublishBookDeleted(ctx, deleted))
+	require.NoError(t, publisher.PublishStockChanged(ctx, domain.StockAdjustment{
+		BookID: book.ID, Delta: -1, Reason: domain.StockSale, StockAfter: 1, BookVersion: 2, CreatedAt: now,
+	}))
+
+	require.Equal(t, []string{
+		events.TypeBookCreatedV1,
+		events.TypeBookUpdatedV1,
+		events.TypeBookDeletedV1,
+		events.TypeStockChangedV1,
+	}, attributes)
+	require.Equal(t, []string{"shop-a", "shop-a", "shop-a", "shop-a"}, tenants)
+	for i, message := range messages {
+		envelope, err := events.Parse([]byte(message))
+		require.NoError(t, err)
+		require.Equal(t, attributes[i], envelope.Type)
+		require.Equal(t, "event-1", envelope.ID)
+		require.Equal(t, book.ID.String(), envelope.Subject)
+		require.Equal(t, "shop-a", envelope.TenantID)
+	}
+
+	envelope, err := events.Parse([]byte(messages[0]))
+	require.NoError(t, err)
+	var created events.BookCreatedV1
+	require.NoError(t, envelope.DecodeData(&created))
+	require.Equal(t, "9.99", created.Price)
+	require.Equal(t, "reader@example.com", created.UserEmail)
+}
@@ -0,0 +1,221 @@
+// Package outbox delivers domain events stored in the transactional outbox
+// to the configured event publisher.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/example/bookapi/internal/domain"
+	"github.com/example/bookapi/internal/events"
+	"github.com/example/bookapi/internal/service"
+)
+
+const (
+	defaultBatchSize      = 50
+	defaultPollInterval   = time.Second
+	defaultLease          = 30 * time.Second
+	defaultInitialBackoff = time.Second
+	defaultMaxBackoff     = 5 * time.Minute
+)
+
+// Store is the persistence the relay drains.
+type Store interface {
+	Claim(ctx context.Context, limit int, now time.Time, lease time.Duration) ([]domain.OutboxEvent, error)
+	MarkDelivered(ctx context.Context, id uuid.UUID) error
+	MarkFailed(ctx context.Context, id uuid.UUID, nextAttemptAt time.Time, reason string) error
+}
+
+// Relay polls the outbox and hands events to the publisher. Delivery is
+// at-least-once: an event is only removed after the publisher accepted it, so
+// a crash between publishing and acknowledging delivers it again. Failed
+// deliveries are retried with exponential backoff.
+type Relay struct {
+	store          Store
+	publisher      service.BookEventPublisher
+	logger         *slog.Logger
+	now            func() time.Time
+	batchSize      int
+	pollInterval   time.Duration
+	lease          time.Duration
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+}
+
+func NewRelay(store Store, publisher service.BookEventPublisher, logger *slog.Logger, opts ...RelayOption) *Relay {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	relay := &Relay{
+		store:          store,
+		publisher:      publisher,
+		logger:         logger,
+		now:            time.Now,
+		batchSize:      defaultBatchSize,
+		pollInterval:   defaultPollInterval,
+		lease:          defaultLease,
+		initialBackoff: defaultInitialBackoff,
+		maxBackoff:     defaultMaxBackoff,
+	}
+	for _, opt := range opts {
+		opt(relay)
+	}
+	return relay
+}
+
+// RelayOption configures Relay behavior.
+type RelayOption func(*Relay)
+
+// WithPollInterval sets how long the relay waits when the outbox is empty.
+func WithPollInterval(interval time.Duration) RelayOption {
+	return func(relay *Relay) {
+		if interval > 0 {
+			relay.pollInterval = interval
+		}
+	}
+}
+
+// WithBatchSize sets how many events are claimed per poll.
+func WithBatchSize(size int) RelayOption {
+	return func(relay *Relay) {
+		if size > 0 {
+			relay.batchSize = size
+		}
+	}
+}
+
+// WithBackoff sets the delay before the first retry and the cap the
+// doubling delay grows to.
+func WithBackoff(initial, max time.Duration) RelayOption {
+	return func(relay *Relay) {
+		if initial > 0 && max >= initial {
+			relay.initialBackoff = initial
+			relay.maxBackoff = max
+		}
+	}
+}
+
+// Run relays events until ctx is cancelled.
+func (r *Relay) Run(ctx context.Context) error {
+	r.logger.Info("outbox relay started", "batchSize", r.batchSize, "pollInterval", r.pollInterval)
+	for {
+		claimed, err := r.RelayOnce(ctx)
+		if err != nil && ctx.Err() == nil {
+			r.logger.Error("outbox relay poll failed", "error", err)
+		}
+		if err == nil && claimed == r.batchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			r.logger.Info("outbox relay stopped")
+			return nil
+		case <-time.After(r.pollInterval):
+		}
+	}
+}
+
+// RelayOnce claims one batch of due events and attempts to deliver each of
+// them. It returns the number of events claimed.
+func (r *Relay) RelayOnce(ctx context.Context) (int, error) {
+	events, err := r.store.Claim(ctx, r.batchSize, r.now().UTC(), r.lease)
+	if err != nil {
+		return 0, fmt.Errorf("claim outbox events: %w", err)
+	}
+
+	for _, event := range events {
+		// Unprocessed events become due again when their lease expires.
+		if ctx.Err() != nil {
+			return len(events), ctx.Err()
+		}
+
+		if err := r.deliver(ctx, event); err != nil {
+			delay := r.backoff(event.Attempts + 1)
+			r.logger.Warn("failed to deliver outbox event; will retry",
+				"error", err,
+				"eventId", event.ID,
+				"eventType", event.Type,
+				"attempt", event.Attempts+1,
+				"retryIn", delay,
+			)
+			if err := r.store.MarkFailed(ctx, event.ID, r.now().UTC().Add(delay), err.Error()); err != nil {
+				r.logger.Error("failed to record outbox delivery failure", "error", err, "eventId", event.ID)
+			}
+			continue
+		}
+
+		if err := r.store.MarkDelivered(ctx, event.ID); err != nil {
+			r.logger.Error("failed to acknowledge outbox event; it will be delivered again",
+				"error", err,
+				"eventId", event.ID,
+			)
+		}
+	}
+	return len(events), nil
+}
+
+// deliver hands event to the publisher on behalf of the actor that caused
+// it, in the tenant whose catalog it is about. Every attempt publishes with
+// the outbox event's ID, so consumers can drop duplicate deliveries.
+func (r *Relay) deliver(ctx context.Context, event domain.OutboxEvent) error {
+	if event.Actor != "" {
+		ctx = domain.ContextWithActor(ctx, event.Actor)
+	}
+	ctx = domain.ContextWithTenant(ctx, event.Tenant)
+	ctx = events.ContextWithID(ctx, event.ID.String())
+
+	switch event.Type {
+	case domain.EventTypeBookCreated:
+		var book domain.Book
+		if err := decodePayload(event, &book); err != nil {
+			return err
+		}
+		return r.publisher.PublishBookCreated(ctx, book)
+	case domain.EventTypeBookUpdated:
+		var update domain.BookUpdate
+		if err := decodePayload(event, &update); err != nil {
+			return err
+		}
+		return r.publisher.PublishBookUpdated(ctx, update)
+	case domain.EventTypeBookDeleted:
+		var book domain.Book
+		if err := decodePayload(event, &book); err != nil {
+			return err
+		}
+		return r.publisher.PublishBookDeleted(ctx, book)
+	case domain.EventTypeStockChanged:
+		var adjustment domain.StockAdjustment
+		if err := decodePayload(event, &adjustment); err != nil {
+			return err
+		}
+		return r.publisher.PublishStockChanged(ctx, adjustment)
+	default:
+		return fmt.Errorf("unknown outbox event type %q", event.Type)
+	}
+}
+
+func decodePayload(event domain.OutboxEvent, v any) error {
+	if err := json.Unmarshal(event.Payload, v); err != nil {
+		return fmt.Errorf("decode %s payload: %w", event.Type, err)
+	}
+	return nil
+}
+
+// backoff returns the delay before the given delivery attempt: the initial
+// backoff doubled for every earlier failure, capped at the maximum.
+func (r *Relay) backoff(attempt int) time.Duration {
+	delay := r.initialBackoff
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= r.maxBackoff {
+			return r.maxBackoff
+		}
+	}
+	return delay
+}
@@ -0,0 +1,154 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/bookapi/internal/domain"
+	"github.com/example/bookapi/internal/events"
+)
+
+func TestRelayOnce(t *testing.T) {
+	now := time.Date(2025, 11, 10, 12, 0, 0, 0, time.UTC)
+	book := domain.Book{ID: uuid.New(), Title: "Dune", CreatedAt: now}
+	event, err := domain.NewBookCreatedEvent(book)
+	require.NoError(t, err)
+	event.Actor = "till-3"
+	event.Tenant = "shop-a"
+
+	t.Run("delivered events are acknowledged", func(t *testing.T) {
+		store := &fakeStore{events: []domain.OutboxEvent{event}}
+		publisher := &fakePublisher{}
+		relay := NewRelay(store, publisher, nil)
+		relay.now = func() time.Time { return now }
+
+		claimed, err := relay.RelayOnce(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 1, claimed)
+		require.Equal(t, []uuid.UUID{book.ID}, publisher.published)
+		require.Equal(t, []string{"till-3"}, publisher.actors)
+		require.Equal(t, []string{"shop-a"}, publisher.tenants)
+		require.Equal(t, []string{event.ID.String()}, publisher.eventIDs)
+		require.Equal(t, []uuid.UUID{event.ID}, store.delivered)
+		require.Empty(t, store.failed)
+	})
+
+	t.Run("every event type is delivered", func(t *testing.T) {
+		deleted := book
+		deleted.DeletedAt = &now
+		updated, err := domain.NewBookUpdatedEvent(domain.BookUpdate{
+			Book:    book,
+			Changes: []domain.FieldChange{{Field: "title", Before: "Dune Messiah", After: "Dune"}},
+		})
+		require.NoError(t, err)
+		deletedEvent, err := domain.NewBookDeletedEvent(deleted)
+		require.NoError(t, err)
+		stockChanged, err := domain.NewStockChangedEvent(domain.StockAdjustment{
+			ID: uuid.New(), BookID: book.ID, Delta: -1, Reason: domain.StockSale, CreatedAt: now,
+		})
+		require.NoError(t, err)
+
+		store := &fakeStore{events: []domain.OutboxEvent{updated, deletedEvent, stockChanged}}
+		publisher := &fakePublisher{}
+		relay := NewRelay(store, publisher, nil)
+		relay.now = func() time.Time { return now }
+
+		claimed, err := relay.RelayOnce(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 3, claimed)
+		require.Equal(t, []uuid.UUID{book.ID, book.ID, book.ID}, publisher.published)
+		require.Equal(t, []string{"updated", "deleted", "stock changed"}, publisher.kinds)
+		require.Len(t, store.delivered, 3)
+	})
+
+	t.Run("failed events are retried with backoff", func(t *testing.T) {
+		retried := event
+		retried.Attempts = 2
+		store := &fakeStore{events: []domain.OutboxEvent{retried}}
+		relay := NewRelay(store, &fakePublisher{err: errors.New("sns unavailable")}, nil,
+			WithBackoff(time.Second, time.Minute))
+		relay.now = func() time.Time { return now }
+
+		_, err := relay.RelayOnce(context.Background())
+		require.NoError(t, err)
+		require.Empty(t, store.delivered)
+		require.Equal(t, now.Add(4*time.Second), store.failed[event.ID])
+	})
+}
+
+func TestRelayBackoff(t *testing.T) {
+	relay := NewRelay(&fakeStore{}, &fakePublisher{}, nil, WithBackoff(time.Second, 10*time.Second))
+
+	require.Equal(t, time.Second, relay.backoff(1))
+	require.Equal(t, 2*time.Second, relay.backoff(2))
+	require.Equal(t, 8*time.Second, relay.backoff(4))
+	require.Equal(t, 10*time.Second, relay.backoff(5))
+	require.Equal(t, 10*time.Second, relay.backoff(100))
+}
+
+type fakeStore struct {
+	events    []domain.OutboxEvent
+	delivered []uuid.UUID
+	failed    map[uuid.UUID]time.Time
+}
+
+func (s *fakeStore) Claim(_ context.Context, limit int, _ time.Time, _ time.Duration) ([]domain.OutboxEvent, error) {
+	claimed := s.events[:min(limit, len(s.events))]
+	s.events = s.events[len(claimed):]
+	return claimed, nil
+}
+
+func (s *fakeStore) MarkDelivered(_ context.Context, id uuid.UUID) error {
+	s.delivered = append(s.delivered, id)
+	return nil
+}
+
+func (s *fakeStore) MarkFailed(_ context.Context, id uuid.UUID, nextAttemptAt time.Time, _ string) error {
+	if s.failed == nil {
+		s.failed = make(map[uuid.UUID]time.Time)
+	}
+	s.failed[id] = nextAttemptAt
+	return nil
+}
+
+type fakePublisher struct {
+	published []uuid.UUID
+	kinds     []string
+	actors    []string
+	tenants   []string
+	eventIDs  []string
+	err       error
+}
+
+func (p *fakePublisher) PublishBookCreated(ctx context.Context, book domain.Book) error {
+	return p.record(ctx, "created", book.ID)
+}
+
+func (p *fakePublisher) PublishBookUpdated(ctx context.Context, update domain.BookUpdate) error {
+	return p.record(ctx, "updated", update.Book.ID)
+}
+
+func (p *fakePublisher) PublishBookDeleted(ctx context.Context, book domain.Book) error {
+	return p.record(ctx, "deleted", book.ID)
+}
+
+func (p *fakePublisher) PublishStockChanged(ctx context.Context, adjustment domain.StockAdjustment) error {
+	return p.record(ctx, "stock changed", adjustment.BookID)
+}
+
+func (p *fakePublisher) record(ctx context.Context, kind string, bookID uuid.UUID) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.published = append(p.published, bookID)
+	p.kinds = append(p.kinds, kind)
+	p.actors = append(p.actors, domain.ActorFromContext(ctx))
+	p.tenants = append(p.tenants, domain.TenantFromContext(ctx))
+	p.eventIDs = append(p.eventIDs, events.IDFromContext(ctx))
+	return nil
+}
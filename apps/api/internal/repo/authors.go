@@ -0,0 +1,138 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/example/bookapi/internal/domain"
+)
+
+// AuthorRepository stores authors. Links between books and authors are
+// written by BookRepository together with the book.
+type AuthorRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewAuthorRepository(pool *pgxpool.Pool) *AuthorRepository {
+	return &AuthorRepository{pool: pool}
+}
+
+func (r *AuthorRepository) Create(ctx context.Context, author domain.Author) error {
+	const query = `
+		INSERT INTO authors (id, name, created_at, updated_at)
+		VALUES ($1, $2, $3, $4)
+	`
+	_, err := dbFrom(ctx, r.pool).Exec(ctx, query, author.ID, author.Name, author.CreatedAt, author.UpdatedAt)
+	return err
+}
+
+func (r *AuthorRepository) Get(ctx context.Context, id uuid.UUID) (domain.Author, error) {
+	const query = `SELECT id, name, created_at, updated_at FROM authors WHERE id = $1`
+	return scanAuthor(dbFrom(ctx, r.pool).QueryRow(ctx, query, id))
+}
+
+// FindByName returns the oldest author with this name, ignoring case.
+func (r *AuthorRepository) FindByName(ctx context.Context, name string) (domain.Author, error) {
+	const query = `
+		SELECT id, name, created_at, updated_at
+		FROM authors
+		WHERE lower(name) = lower($1)
+		ORDER BY created_at, id
+		LIMIT 1
+	`
+	return scanAuthor(dbFrom(ctx, r.pool).QueryRow(ctx, query, name))
+}
+
+// List returns a page of authors ordered by name, ignoring case, then ID.
+func (r *AuthorRepository) List(ctx context.Context, query domain.AuthorQuery) (domain.AuthorPage, error) {
+	b := &queryBuilder{}
+	if query.Name != "" {
+		b.where(`name ILIKE '%%' || %s || '%%'`, escapeLike(query.Name))
+	}
+	if query.After != nil {
+		b.where("(lower(name), id) > (lower(%s), %s)", query.After.Values[0], query.After.ID)
+	}
+	sql := fmt.Sprintf(`
+		SELECT id, name, created_at, updated_at
+		FROM authors
+		%s
+		ORDER BY lower(name), id
+		LIMIT %s
+	`, b.whereClause(), b.arg(query.Limit+1))
+
+	rows, err := dbFrom(ctx, r.pool).Query(ctx, sql, b.args...)
+	if err != nil {
+		return domain.AuthorPage{}, err
+	}
+	defer rows.Close()
+
+	var authors []domain.Author
+	for rows.Next() {
+		author, err := scanAuthor(rows)
+		if err != nil {
+			return domain.AuthorPage{}, err
+		}
+		authors = append(authors, author)
+	}
+	if err := rows.Err(); err != nil {
+		return domain.AuthorPage{}, err
+	}
+	return buildAuthorPage(authors, query.Limit), nil
+}
+
+// Update renames the author.
+func (r *AuthorRepository) Update(ctx context.Context, author domain.Author) error {
+	const query = `UPDATE authors SET name = $2, updated_at = $3 WHERE id = $1`
+	tag, err := dbFrom(ctx, r.pool).Exec(ctx, query, author.ID, author.Name, author.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrAuthorNotFound
+	}
+	return nil
+}
+
+// Delete removes an author who is not credited on any book.
+func (r *AuthorRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	tag, err := dbFrom(ctx, r.pool).Exec(ctx, `DELETE FROM authors WHERE id = $1`, id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return domain.ErrAuthorInUse
+		}
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrAuthorNotFound
+	}
+	return nil
+}
+
+func scanAuthor(row pgx.Row) (domain.Author, error) {
+	var author domain.Author
+	if err := row.Scan(&author.ID, &author.Name, &author.CreatedAt, &author.UpdatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Author{}, domain.ErrAuthorNotFound
+		}
+		return domain.Author{}, fmt.Errorf("scan author: %w", err)
+	}
+	return author, nil
+}
+
+// buildAuthorPage trims rows fetched with one extra row to the limit and
+// sets the cursor when more authors follow.
+func buildAuthorPage(authors []domain.Author, limit int) domain.AuthorPage {
+	page := domain.AuthorPage{Authors: authors}
+	if len(authors) > limit {
+		page.Authors = authors[:limit]
+		page.NextCursor = domain.NewAuthorCursor(page.Authors[limit-1]).Encode()
+	}
+	return page
+}
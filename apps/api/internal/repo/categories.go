@@ -0,0 +1,118 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/example/bookapi/internal/domain"
+)
+
+// CategoryRepository stores the category tree. Links between books and
+// categories are written by BookRepository together with the book.
+type CategoryRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewCategoryRepository(pool *pgxpool.Pool) *CategoryRepository {
+	return &CategoryRepository{pool: pool}
+}
+
+func (r *CategoryRepository) Create(ctx context.Context, category domain.Category) error {
+	const query = `
+		INSERT INTO categories (id, parent_id, name, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err := dbFrom(ctx, r.pool).Exec(ctx, query,
+		category.ID, category.ParentID, category.Name, category.CreatedAt, category.UpdatedAt)
+	return translateCategoryError(err)
+}
+
+func (r *CategoryRepository) Get(ctx context.Context, id uuid.UUID) (domain.Category, error) {
+	const query = `SELECT id, parent_id, name, created_at, updated_at FROM categories WHERE id = $1`
+	return scanCategory(dbFrom(ctx, r.pool).QueryRow(ctx, query, id))
+}
+
+// List returns every category ordered by name, ignoring case, then ID.
+func (r *CategoryRepository) List(ctx context.Context) ([]domain.Category, error) {
+	const query = `
+		SELECT id, parent_id, name, created_at, updated_at
+		FROM categories
+		ORDER BY lower(name), id
+	`
+	rows, err := dbFrom(ctx, r.pool).Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := []domain.Category{}
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			return nil, err
+		}
+		categories = append(categories, category)
+	}
+	return categories, rows.Err()
+}
+
+// Update renames the category and moves it under its parent.
+func (r *CategoryRepository) Update(ctx context.Context, category domain.Category) error {
+	const query = `UPDATE categories SET parent_id = $2, name = $3, updated_at = $4 WHERE id = $1`
+	tag, err := dbFrom(ctx, r.pool).Exec(ctx, query, category.ID, category.ParentID, category.Name, category.UpdatedAt)
+	if err != nil {
+		return translateCategoryError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrCategoryNotFound
+	}
+	return nil
+}
+
+// Delete removes a category without subcategories or books.
+func (r *CategoryRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	tag, err := dbFrom(ctx, r.pool).Exec(ctx, `DELETE FROM categories WHERE id = $1`, id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return domain.ErrCategoryInUse
+		}
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrCategoryNotFound
+	}
+	return nil
+}
+
+func scanCategory(row pgx.Row) (domain.Category, error) {
+	var category domain.Category
+	err := row.Scan(&category.ID, &category.ParentID, &category.Name, &category.CreatedAt, &category.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Category{}, domain.ErrCategoryNotFound
+		}
+		return domain.Category{}, fmt.Errorf("scan category: %w", err)
+	}
+	return category, nil
+}
+
+func translateCategoryError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+	switch {
+	case pgErr.Code == "23505" && pgErr.ConstraintName == "categories_parent_name_key":
+		return domain.ErrDuplicateCategory
+	case pgErr.Code == "23503" && pgErr.ConstraintName == "categories_parent_id_fkey":
+		return domain.ErrCategoryNotFound
+	}
+	return err
+}
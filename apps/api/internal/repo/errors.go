@@ -0,0 +1,102 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/example/bookapi/internal/domain"
+)
+
+// translateError gives errors of Postgres and the driver a domain error kind,
+// keeping them as the cause. Errors that already have a kind, that are not
+// the database's, or that the domain has no kind for are returned as they
+// are. Repository methods that know the constraint behind a violation map it
+// to a more specific error on top, see translateWriteError.
+func translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var domainErr *domain.Error
+	if errors.As(err, &domainErr) {
+		return err
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		case pgErr.Code == "23505":
+			return &domain.Error{Kind: domain.ErrConflict, Message: "conflicts with an existing record", Err: err}
+		case pgErr.Code == "23503":
+			return &domain.Error{Kind: domain.ErrConflict, Message: "refers to or is referred to by another record", Err: err}
+		case pgErr.Code == "23514", pgErr.Code == "23502", strings.HasPrefix(pgErr.Code, "22"):
+			// check and not-null violations, and data exceptions such as values
+			// out of the range of their column.
+			return &domain.Error{Kind: domain.ErrValidation, Message: "violates a constraint of the stored data", Err: err}
+		case pgErr.Code == "40001", pgErr.Code == "40P01":
+			// serialization failures and deadlocks.
+			return &domain.Error{Kind: domain.ErrUnavailable, Message: "the database is busy; retry the request", Err: err}
+		case strings.HasPrefix(pgErr.Code, "08"), strings.HasPrefix(pgErr.Code, "53"), strings.HasPrefix(pgErr.Code, "57P"):
+			// connection failures, exhausted resources and shutdowns.
+			return &domain.Error{Kind: domain.ErrUnavailable, Message: "the database is unavailable", Err: err}
+		}
+		return err
+	}
+
+	var connectErr *pgconn.ConnectError
+	if errors.As(err, &connectErr) || pgconn.Timeout(err) {
+		return &domain.Error{Kind: domain.ErrUnavailable, Message: "the database is unavailable", Err: err}
+	}
+	return err
+}
+
+// translatingQuerier returns the errors of q with the kinds translateError
+// gives them, so every query of the repositories reports them the same way.
+type translatingQuerier struct {
+	q querier
+}
+
+func (t translatingQuerier) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	tag, err := t.q.Exec(ctx, sql, args...)
+	return tag, translateError(err)
+}
+
+func (t translatingQuerier) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	rows, err := t.q.Query(ctx, sql, args...)
+	if err != nil {
+		return rows, translateError(err)
+	}
+	return translatingRows{rows}, nil
+}
+
+func (t translatingQuerier) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return translatingRow{t.q.QueryRow(ctx, sql, args...)}
+}
+
+func (t translatingQuerier) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	n, err := t.q.CopyFrom(ctx, tableName, columnNames, rowSrc)
+	return n, translateError(err)
+}
+
+type translatingRows struct {
+	pgx.Rows
+}
+
+func (r translatingRows) Err() error {
+	return translateError(r.Rows.Err())
+}
+
+func (r translatingRows) Scan(dest ...any) error {
+	return translateError(r.Rows.Scan(dest...))
+}
+
+type translatingRow struct {
+	row pgx.Row
+}
+
+func (r translatingRow) Scan(dest ...any) error {
+	return translateError(r.row.Scan(dest...))
+}
@@ -0,0 +1,58 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/bookapi/internal/domain"
+)
+
+func TestTranslateError(t *testing.T) {
+	_, refused := pgconn.Connect(context.Background(), "postgres://books@127.0.0.1:1/books?connect_timeout=1")
+	require.Error(t, refused)
+
+	testCases := []struct {
+		name string
+		err  error
+		kind error
+	}{
+		{name: "unique violation", err: &pgconn.PgError{Code: "23505", ConstraintName: "authors_name_key"}, kind: domain.ErrConflict},
+		{name: "foreign key violation", err: &pgconn.PgError{Code: "23503"}, kind: domain.ErrConflict},
+		{name: "check violation", err: &pgconn.PgError{Code: "23514", ConstraintName: "books_stock_check"}, kind: domain.ErrValidation},
+		{name: "numeric out of range", err: &pgconn.PgError{Code: "22003"}, kind: domain.ErrValidation},
+		{name: "serialization failure", err: &pgconn.PgError{Code: "40001"}, kind: domain.ErrUnavailable},
+		{name: "too many connections", err: &pgconn.PgError{Code: "53300"}, kind: domain.ErrUnavailable},
+		{name: "wrapped admin shutdown", err: fmt.Errorf("insert book: %w", &pgconn.PgError{Code: "57P01"}), kind: domain.ErrUnavailable},
+		{name: "connection refused", err: fmt.Errorf("begin transaction: %w", refused), kind: domain.ErrUnavailable},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := translateError(tc.err)
+			require.ErrorIs(t, err, tc.kind)
+			require.ErrorIs(t, err, tc.err, "the cause is kept")
+
+			var domainErr *domain.Error
+			require.ErrorAs(t, err, &domainErr)
+			require.NotContains(t, domainErr.Message, "SQLSTATE")
+		})
+	}
+
+	t.Run("other errors are unchanged", func(t *testing.T) {
+		for _, err := range []error{nil, pgx.ErrNoRows, context.Canceled, &pgconn.PgError{Code: "42501"}, domain.ErrDuplicateISBN} {
+			require.Equal(t, err, translateError(err))
+		}
+	})
+
+	t.Run("constraint specific errors win", func(t *testing.T) {
+		err := translateWriteError(translateError(&pgconn.PgError{Code: "23505", ConstraintName: "books_isbn_key"}))
+		require.Equal(t, domain.ErrDuplicateISBN, err)
+		require.True(t, errors.Is(err, domain.ErrConflict))
+	})
+}
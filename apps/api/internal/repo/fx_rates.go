@@ -0,0 +1,85 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/example/bookapi/internal/domain"
+)
+
+// FXRateRepository stores exchange rates in fx_rates.
+type FXRateRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewFXRateRepository(pool *pgxpool.Pool) *FXRateRepository {
+	return &FXRateRepository{pool: pool}
+}
+
+// List returns every rate ordered by base and quote currency.
+func (r *FXRateRepository) List(ctx context.Context) ([]domain.FXRate, error) {
+	const query = `
+		SELECT base, quote, rate::text, updated_at
+		FROM fx_rates
+		ORDER BY base, quote
+	`
+	rows, err := dbFrom(ctx, r.pool).Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rates := []domain.FXRate{}
+	for rows.Next() {
+		var (
+			rate domain.FXRate
+			text string
+		)
+		if err := rows.Scan(&rate.Base, &rate.Quote, &text, &rate.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if rate.Rate, err = parseRate(text); err != nil {
+			return nil, fmt.Errorf("rate %s/%s: %w", rate.Base, rate.Quote, err)
+		}
+		rates = append(rates, rate)
+	}
+	return rates, rows.Err()
+}
+
+// Upsert stores rates, replacing the existing rate of each pair.
+func (r *FXRateRepository) Upsert(ctx context.Context, rates []domain.FXRate) error {
+	if len(rates) == 0 {
+		return nil
+	}
+	const query = `
+		INSERT INTO fx_rates (base, quote, rate, updated_at)
+		SELECT base, quote, rate::numeric, updated_at
+		FROM unnest($1::text[], $2::text[], $3::text[], $4::timestamptz[]) AS r(base, quote, rate, updated_at)
+		ON CONFLICT (base, quote) DO UPDATE
+		SET rate = excluded.rate, updated_at = excluded.updated_at
+	`
+	var (
+		bases, quotes, values []string
+		updatedAt             []any
+	)
+	for _, rate := range rates {
+		bases = append(bases, rate.Base)
+		quotes = append(quotes, rate.Quote)
+		values = append(values, rate.Rate.String())
+		updatedAt = append(updatedAt, rate.UpdatedAt)
+	}
+	_, err := dbFrom(ctx, r.pool).Exec(ctx, query, bases, quotes, values, updatedAt)
+	return err
+}
+
+// parseRate parses a NUMERIC rate, dropping the trailing zeros of its fixed
+// scale so large rates still fit a Decimal.
+func parseRate(text string) (domain.Decimal, error) {
+	if strings.Contains(text, ".") {
+		text = strings.TrimSuffix(strings.TrimRight(text, "0"), ".")
+	}
+	return domain.ParseDecimal(text)
+}
@@ -0,0 +1,1220 @@
+package repo
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+
+	"github.com/example/bookapi/internal/domain"
+)
+
+var errDuplicateID = errors.New("book with this id already exists")
+
+// MemoryBookRepository keeps books in process memory. It mirrors the
+// semantics of BookRepository, including soft deletes, version checks,
+// keyset ordering and the separation of tenants, so it can stand in for
+// Postgres in local development and tests. Data is lost when the process
+// exits.
+type MemoryBookRepository struct {
+	mu       sync.RWMutex
+	catalogs map[string]*memoryCatalog
+	// catalogsMu guards catalogs, whose entries are guarded by mu, so that
+	// readers holding only the read lock can add the catalog of a new
+	// tenant.
+	catalogsMu sync.Mutex
+}
+
+// memoryCatalog holds the books, authors, categories and tags of one tenant.
+// IDs, ISBNs and names only need to be unique within it, like the
+// tenant-scoped indexes in Postgres.
+type memoryCatalog struct {
+	books      map[uuid.UUID]domain.Book
+	authors    map[uuid.UUID]domain.Author
+	categories map[uuid.UUID]domain.Category
+	tags       map[uuid.UUID]domain.Tag
+}
+
+func NewMemoryBookRepository() *MemoryBookRepository {
+	return &MemoryBookRepository{catalogs: make(map[string]*memoryCatalog)}
+}
+
+// catalog returns the catalog of the tenant of ctx, see
+// domain.TenantFromContext, creating it on first use. Callers must hold the
+// lock.
+func (r *MemoryBookRepository) catalog(ctx context.Context) *memoryCatalog {
+	r.catalogsMu.Lock()
+	defer r.catalogsMu.Unlock()
+
+	tenant := domain.TenantFromContext(ctx)
+	c, ok := r.catalogs[tenant]
+	if !ok {
+		c = &memoryCatalog{
+			books:      make(map[uuid.UUID]domain.Book),
+			authors:    make(map[uuid.UUID]domain.Author),
+			categories: make(map[uuid.UUID]domain.Category),
+			tags:       make(map[uuid.UUID]domain.Tag),
+		}
+		r.catalogs[tenant] = c
+	}
+	return c
+}
+
+func (r *MemoryBookRepository) Create(ctx context.Context, book domain.Book) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c := r.catalog(ctx)
+
+	if _, exists := c.books[book.ID]; exists {
+		return errDuplicateID
+	}
+	if c.isbnTaken(book.ISBN, book.ID) {
+		return domain.ErrDuplicateISBN
+	}
+	if !c.authorsExist(book.Authors) {
+		return domain.ErrAuthorNotFound
+	}
+	if err := c.taxonomyExists(book); err != nil {
+		return err
+	}
+	c.books[book.ID] = cloneBook(book)
+	return nil
+}
+
+// CreateMany stores all books, or none of them when any ID or ISBN is
+// already taken.
+func (r *MemoryBookRepository) CreateMany(ctx context.Context, books []domain.Book) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c := r.catalog(ctx)
+
+	seen := make(map[uuid.UUID]bool, len(books))
+	seenISBNs := make(map[string]bool, len(books))
+	for _, book := range books {
+		if _, exists := c.books[book.ID]; exists || seen[book.ID] {
+			return errDuplicateID
+		}
+		if c.isbnTaken(book.ISBN, book.ID) || (book.ISBN != "" && seenISBNs[book.ISBN]) {
+			return domain.ErrDuplicateISBN
+		}
+		if !c.authorsExist(book.Authors) {
+			return domain.ErrAuthorNotFound
+		}
+		if err := c.taxonomyExists(book); err != nil {
+			return err
+		}
+		seen[book.ID] = true
+		seenISBNs[book.ISBN] = true
+	}
+	for _, book := range books {
+		c.books[book.ID] = cloneBook(book)
+	}
+	return nil
+}
+
+func (r *MemoryBookRepository) Get(ctx context.Context, id uuid.UUID) (domain.Book, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c := r.catalog(ctx)
+
+	book, ok := c.books[id]
+	if !ok || book.DeletedAt != nil {
+		return domain.Book{}, domain.ErrBookNotFound
+	}
+	return c.view(book), nil
+}
+
+func (r *MemoryBookRepository) List(ctx context.Context, query domain.BookQuery) (domain.BookPage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c := r.catalog(ctx)
+
+	backward := query.Cursor != nil && query.Cursor.Backward
+	var boundary sortKey
+	if query.Cursor != nil {
+		var err error
+		if boundary, err = cursorSortKey(*query.Cursor, query.Sort); err != nil {
+			return domain.BookPage{}, err
+		}
+	}
+
+	rows := make([]domain.Book, 0, len(c.books))
+	for _, book := range c.books {
+		if !c.matches(book, query) {
+			continue
+		}
+		if query.Cursor != nil && compareKeys(bookSortKey(book), boundary, query.Sort, backward) <= 0 {
+			continue
+		}
+		rows = append(rows, c.view(book))
+	}
+
+	slices.SortFunc(rows, func(a, b domain.Book) int {
+		return compareKeys(bookSortKey(a), bookSortKey(b), query.Sort, backward)
+	})
+	if len(rows) > query.Limit+1 {
+		rows = rows[:query.Limit+1]
+	}
+	return buildPage(rows, query), nil
+}
+
+// Export snapshots the matching books under the read lock and calls fn
+// after releasing it, so a slow consumer does not block writers.
+func (r *MemoryBookRepository) Export(ctx context.Context, query domain.BookQuery, fn func(domain.Book) error) error {
+	r.mu.RLock()
+	c := r.catalog(ctx)
+	rows := make([]domain.Book, 0, len(c.books))
+	for _, book := range c.books {
+		if c.matches(book, query) {
+			rows = append(rows, c.view(book))
+		}
+	}
+	r.mu.RUnlock()
+
+	slices.SortFunc(rows, func(a, b domain.Book) int {
+		return compareKeys(bookSortKey(a), bookSortKey(b), query.Sort, false)
+	})
+	for _, book := range rows {
+		if err := fn(book); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *MemoryBookRepository) GetByTitleAuthor(ctx context.Context, title, author string) (domain.Book, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c := r.catalog(ctx)
+
+	var found []domain.Book
+	for _, book := range c.books {
+		if book.DeletedAt == nil && strings.EqualFold(book.Title, title) && strings.EqualFold(book.Author, author) {
+			found = append(found, book)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return domain.Book{}, domain.ErrBookNotFound
+	case 1:
+		return c.view(found[0]), nil
+	default:
+		return domain.Book{}, domain.ErrAmbiguousBook
+	}
+}
+
+func (r *MemoryBookRepository) GetByISBN(ctx context.Context, isbn string) (domain.Book, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c := r.catalog(ctx)
+
+	for _, book := range c.books {
+		if book.DeletedAt == nil && book.ISBN == isbn {
+			return c.view(book), nil
+		}
+	}
+	return domain.Book{}, domain.ErrBookNotFound
+}
+
+// TakenISBNs returns those of isbns that a book, live or soft-deleted,
+// already has.
+func (r *MemoryBookRepository) TakenISBNs(ctx context.Context, isbns []string) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c := r.catalog(ctx)
+
+	var taken []string
+	for _, isbn := range isbns {
+		if c.isbnTaken(isbn, uuid.Nil) {
+			taken = append(taken, isbn)
+		}
+	}
+	return taken, nil
+}
+
+// Search approximates the Postgres full-text search: every term must prefix a
+// word of the title or author, terms prefixed with "-" exclude books, and
+// title matches rank above author matches. There is no stemming.
+func (r *MemoryBookRepository) Search(ctx context.Context, query domain.BookSearchQuery) ([]domain.BookSearchResult, error) {
+	include, exclude := searchTerms(query.Query)
+	if len(include) == 0 {
+		return []domain.BookSearchResult{}, nil
+	}
+
+	r.mu.RLock()
+	c := r.catalog(ctx)
+	var results []domain.BookSearchResult
+	for _, book := range c.books {
+		if book.DeletedAt != nil {
+			continue
+		}
+		rank, ok := searchRank(book, include, exclude)
+		if !ok {
+			continue
+		}
+		results = append(results, domain.BookSearchResult{
+			Book:    c.view(book),
+			Rank:    rank,
+			Snippet: highlight(book.Title+" by "+book.Author, include),
+		})
+	}
+	r.mu.RUnlock()
+
+	slices.SortFunc(results, func(a, b domain.BookSearchResult) int {
+		if c := cmp.Compare(b.Rank, a.Rank); c != 0 {
+			return c
+		}
+		if c := a.Book.CreatedAt.Compare(b.Book.CreatedAt); c != 0 {
+			return c
+		}
+		return bytes.Compare(a.Book.ID[:], b.Book.ID[:])
+	})
+
+	if query.Offset >= len(results) {
+		return []domain.BookSearchResult{}, nil
+	}
+	results = results[query.Offset:]
+	if len(results) > query.Limit {
+		results = results[:query.Limit]
+	}
+	return results, nil
+}
+
+func (r *MemoryBookRepository) Update(ctx context.Context, book domain.Book) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c := r.catalog(ctx)
+
+	stored, err := c.live(book.ID)
+	if err != nil {
+		return err
+	}
+	if stored.Version != book.Version {
+		return domain.ErrVersionConflict
+	}
+	if c.isbnTaken(book.ISBN, book.ID) {
+		return domain.ErrDuplicateISBN
+	}
+	if !c.authorsExist(book.Authors) {
+		return domain.ErrAuthorNotFound
+	}
+	if err := c.taxonomyExists(book); err != nil {
+		return err
+	}
+
+	stored.Title = book.Title
+	stored.Author = book.Author
+	stored.Authors = slices.Clone(book.Authors)
+	stored.Categories = slices.Clone(book.Categories)
+	stored.Tags = slices.Clone(book.Tags)
+	stored.ISBN = book.ISBN
+	stored.Price = book.Price
+	stored.Prices = slices.Clone(book.Prices)
+	stored.Stock = book.Stock
+	stored.UpdatedAt = book.UpdatedAt
+	stored.Version++
+	c.books[book.ID] = stored
+	return nil
+}
+
+func (r *MemoryBookRepository) AdjustStock(ctx context.Context, id uuid.UUID, delta int, updatedAt time.Time) (domain.Book, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c := r.catalog(ctx)
+
+	stored, err := c.live(id)
+	if err != nil {
+		return domain.Book{}, err
+	}
+	if stored.Stock+delta < 0 {
+		return domain.Book{}, domain.ErrInsufficientStock
+	}
+
+	stored.Stock += delta
+	stored.UpdatedAt = updatedAt
+	stored.Version++
+	c.books[id] = stored
+	return c.view(stored), nil
+}
+
+func (r *MemoryBookRepository) Delete(ctx context.Context, id uuid.UUID, expectedVersion *int64, deletedAt time.Time) (domain.Book, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c := r.catalog(ctx)
+
+	stored, err := c.live(id)
+	if err != nil {
+		return domain.Book{}, err
+	}
+	if expectedVersion != nil && stored.Version != *expectedVersion {
+		return domain.Book{}, domain.ErrVersionConflict
+	}
+
+	stored.DeletedAt = &deletedAt
+	stored.UpdatedAt = deletedAt
+	stored.Version++
+	c.books[id] = stored
+	return c.view(stored), nil
+}
+
+func (r *MemoryBookRepository) Restore(ctx context.Context, id uuid.UUID, restoredAt time.Time) (domain.Book, time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c := r.catalog(ctx)
+
+	stored, ok := c.books[id]
+	if !ok {
+		return domain.Book{}, time.Time{}, domain.ErrBookNotFound
+	}
+	if stored.DeletedAt == nil {
+		return domain.Book{}, time.Time{}, domain.ErrBookNotDeleted
+	}
+
+	deletedAt := *stored.DeletedAt
+	stored.DeletedAt = nil
+	stored.UpdatedAt = restoredAt
+	stored.Version++
+	c.books[id] = stored
+	return c.view(stored), deletedAt, nil
+}
+
+func (r *MemoryBookRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c := r.catalog(ctx)
+
+	var purged int64
+	for id, book := range c.books {
+		if book.DeletedAt != nil && book.DeletedAt.Before(deletedBefore) {
+			delete(c.books, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// live returns the stored book unless it is missing or soft-deleted. Callers
+// must hold the lock.
+func (c *memoryCatalog) live(id uuid.UUID) (domain.Book, error) {
+	book, ok := c.books[id]
+	if !ok || book.DeletedAt != nil {
+		return domain.Book{}, domain.ErrBookNotFound
+	}
+	return book, nil
+}
+
+// isbnTaken reports whether a book other than id, live or soft-deleted, has
+// the ISBN, like the books_isbn_key index. Callers must hold the lock.
+func (c *memoryCatalog) isbnTaken(isbn string, id uuid.UUID) bool {
+	if isbn == "" {
+		return false
+	}
+	for _, book := range c.books {
+		if book.ISBN == isbn && book.ID != id {
+			return true
+		}
+	}
+	return false
+}
+
+// authorsExist reports whether every credited author is stored, like the
+// book_authors foreign key. Callers must hold the lock.
+func (c *memoryCatalog) authorsExist(authors []domain.BookAuthor) bool {
+	for _, author := range authors {
+		if _, ok := c.authors[author.AuthorID]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// taxonomyExists checks that every category and tag of book is stored, like
+// the book_categories foreign key and the tag lookup in insertTaxonomy.
+// Callers must hold the lock.
+func (c *memoryCatalog) taxonomyExists(book domain.Book) error {
+	for _, category := range book.Categories {
+		if _, ok := c.categories[category.CategoryID]; !ok {
+			return domain.ErrCategoryNotFound
+		}
+	}
+	for _, name := range book.Tags {
+		if _, ok := c.tagNamed(name); !ok {
+			return domain.ErrTagNotFound
+		}
+	}
+	return nil
+}
+
+// tagNamed finds a tag by its normalized name. Callers must hold the lock.
+func (c *memoryCatalog) tagNamed(name string) (domain.Tag, bool) {
+	for _, tag := range c.tags {
+		if tag.Name == name {
+			return tag, true
+		}
+	}
+	return domain.Tag{}, false
+}
+
+// view returns a copy of a stored book with the current names of its
+// authors and categories, like the joins in bookColumns. Callers must hold
+// the lock.
+func (c *memoryCatalog) view(book domain.Book) domain.Book {
+	book = cloneBook(book)
+	for i, author := range book.Authors {
+		book.Authors[i].Name = c.authors[author.AuthorID].Name
+	}
+	for i, category := range book.Categories {
+		book.Categories[i].Name = c.categories[category.CategoryID].Name
+	}
+	slices.SortFunc(book.Categories, func(a, b domain.BookCategory) int {
+		return compareByName(a.Name, a.CategoryID, b.Name, b.CategoryID)
+	})
+	return book
+}
+
+// matches applies matchesQuery and the category filter, which needs the
+// category tree. Callers must hold the lock.
+func (c *memoryCatalog) matches(book domain.Book, query domain.BookQuery) bool {
+	if !matchesQuery(book, query) {
+		return false
+	}
+	if query.CategoryID == nil {
+		return true
+	}
+	parents := make(map[uuid.UUID]*uuid.UUID, len(c.categories))
+	for id, category := range c.categories {
+		parents[id] = category.ParentID
+	}
+	return slices.ContainsFunc(book.Categories, func(category domain.BookCategory) bool {
+		return domain.IsDescendant(parents, category.CategoryID, *query.CategoryID)
+	})
+}
+
+func cloneBook(book domain.Book) domain.Book {
+	if book.DeletedAt != nil {
+		deletedAt := *book.DeletedAt
+		book.DeletedAt = &deletedAt
+	}
+	book.Authors = slices.Clone(book.Authors)
+	book.Categories = slices.Clone(book.Categories)
+	book.Tags = slices.Clone(book.Tags)
+	book.Prices = slices.Clone(book.Prices)
+	return book
+}
+
+// matchesQuery applies the same filters as buildListQuery.
+func matchesQuery(book domain.Book, query domain.BookQuery) bool {
+	switch {
+	case !query.IncludeDeleted && book.DeletedAt != nil:
+		return false
+	case query.Author != "" && !strings.Contains(strings.ToLower(book.Author), strings.ToLower(query.Author)):
+		return false
+	case query.AuthorID != nil && !slices.ContainsFunc(book.Authors, func(author domain.BookAuthor) bool { return author.AuthorID == *query.AuthorID }):
+		return false
+	case query.Tag != "" && !slices.Contains(book.Tags, query.Tag):
+		return false
+	case query.Currency != "" && book.Price.Currency != query.Currency:
+		return false
+	case query.MinPrice != nil && book.Price.Decimal().Cmp(*query.MinPrice) < 0:
+		return false
+	case query.MaxPrice != nil && book.Price.Decimal().Cmp(*query.MaxPrice) > 0:
+		return false
+	case query.InStock && book.Stock <= 0:
+		return false
+	case query.CreatedAfter != nil && book.CreatedAt.Before(*query.CreatedAfter):
+		return false
+	case query.CreatedBefore != nil && !book.CreatedAt.Before(*query.CreatedBefore):
+		return false
+	case query.UpdatedAfter != nil && book.UpdatedAt.Before(*query.UpdatedAfter):
+		return false
+	case query.UpdatedBefore != nil && !book.UpdatedAt.Before(*query.UpdatedBefore):
+		return false
+	}
+	return true
+}
+
+// sortKey holds the values a book is ordered by.
+type sortKey struct {
+	title     string
+	price     domain.Decimal
+	stock     int
+	createdAt time.Time
+	updatedAt time.Time
+	id        uuid.UUID
+}
+
+func bookSortKey(book domain.Book) sortKey {
+	return sortKey{
+		title:     book.Title,
+		price:     book.Price.Decimal(),
+		stock:     book.Stock,
+		createdAt: book.CreatedAt,
+		updatedAt: book.UpdatedAt,
+		id:        book.ID,
+	}
+}
+
+// cursorSortKey rebuilds the sort key values stored in a cursor.
+func cursorSortKey(cursor domain.Cursor, sorts []domain.BookSort) (sortKey, error) {
+	if len(cursor.Values) != len(sorts) {
+		return sortKey{}, domain.ErrInvalidCursor
+	}
+
+	key := sortKey{id: cursor.ID}
+	for i, sort := range sorts {
+		value := cursor.Values[i]
+		var err error
+		switch sort.Field {
+		case domain.SortByTitle:
+			key.title = value
+		case domain.SortByPrice:
+			key.price, err = domain.ParseDecimal(value)
+		case domain.SortByStock:
+			key.stock, err = strconv.Atoi(value)
+		case domain.SortByCreatedAt:
+			key.createdAt, err = time.Parse(time.RFC3339Nano, value)
+		case domain.SortByUpdatedAt:
+			key.updatedAt, err = time.Parse(time.RFC3339Nano, value)
+		}
+		if err != nil {
+			return sortKey{}, domain.ErrInvalidCursor
+		}
+	}
+	return key, nil
+}
+
+// compareKeys orders keys by the sort fields and then by ID, reversing every
+// key when reading backward, exactly like the ORDER BY built by
+// buildListQuery.
+func compareKeys(a, b sortKey, sorts []domain.BookSort, backward bool) int {
+	for _, sort := range sorts {
+		c := compareField(a, b, sort.Field)
+		if sort.Desc != backward {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	c := bytes.Compare(a.id[:], b.id[:])
+	if backward {
+		c = -c
+	}
+	return c
+}
+
+func compareField(a, b sortKey, field domain.BookSortField) int {
+	switch field {
+	case domain.SortByTitle:
+		return strings.Compare(a.title, b.title)
+	case domain.SortByPrice:
+		return a.price.Cmp(b.price)
+	case domain.SortByStock:
+		return cmp.Compare(a.stock, b.stock)
+	case domain.SortByCreatedAt:
+		return a.createdAt.Compare(b.createdAt)
+	case domain.SortByUpdatedAt:
+		return a.updatedAt.Compare(b.updatedAt)
+	}
+	return 0
+}
+
+func searchTerms(query string) (include, exclude []string) {
+	for _, field := range strings.Fields(strings.ToLower(query)) {
+		negated := strings.HasPrefix(field, "-")
+		for _, word := range splitWords(field) {
+			if word == "or" {
+				continue
+			}
+			if negated {
+				exclude = append(exclude, word)
+			} else {
+				include = append(include, word)
+			}
+		}
+	}
+	return include, exclude
+}
+
+func splitWords(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func hasWordWithPrefix(words []string, term string) bool {
+	return slices.ContainsFunc(words, func(word string) bool {
+		return strings.HasPrefix(word, term)
+	})
+}
+
+// searchRank scores a book against the terms, weighting title matches like
+// the 'A' weight of search_vector and author matches like 'B'.
+func searchRank(book domain.Book, include, exclude []string) (float32, bool) {
+	titleWords := splitWords(strings.ToLower(book.Title))
+	authorWords := splitWords(strings.ToLower(book.Author))
+
+	for _, term := range exclude {
+		if hasWordWithPrefix(titleWords, term) || hasWordWithPrefix(authorWords, term) {
+			return 0, false
+		}
+	}
+
+	var rank float32
+	for _, term := range include {
+		switch {
+		case hasWordWithPrefix(titleWords, term):
+			rank += 1.0
+		case hasWordWithPrefix(authorWords, term):
+			rank += 0.4
+		default:
+			return 0, false
+		}
+	}
+	return rank / float32(len(include)), true
+}
+
+// highlight wraps every word that matches a term in <mark> tags and
+// HTML-escapes the rest, mirroring the Postgres search.
+func highlight(text string, terms []string) string {
+	var out strings.Builder
+	word := strings.Builder{}
+	flush := func() {
+		if word.Len() == 0 {
+			return
+		}
+		w := word.String()
+		lower := strings.ToLower(w)
+		if slices.ContainsFunc(terms, func(term string) bool { return strings.HasPrefix(lower, term) }) {
+			out.WriteString("<mark>" + w + "</mark>")
+		} else {
+			out.WriteString(w)
+		}
+		word.Reset()
+	}
+	for _, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			word.WriteRune(r)
+			continue
+		}
+		flush()
+		htmlEscaper.WriteString(&out, string(r))
+	}
+	flush()
+	return out.String()
+}
+
+// htmlEscaper escapes the characters the Postgres search escapes before
+// highlighting.
+var htmlEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// MemoryFXRateRepository is the in-memory counterpart of FXRateRepository.
+type MemoryFXRateRepository struct {
+	mu    sync.RWMutex
+	rates map[[2]string]domain.FXRate
+}
+
+func NewMemoryFXRateRepository() *MemoryFXRateRepository {
+	return &MemoryFXRateRepository{rates: make(map[[2]string]domain.FXRate)}
+}
+
+func (r *MemoryFXRateRepository) List(_ context.Context) ([]domain.FXRate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rates := make([]domain.FXRate, 0, len(r.rates))
+	for _, rate := range r.rates {
+		rates = append(rates, rate)
+	}
+	slices.SortFunc(rates, func(a, b domain.FXRate) int {
+		return cmp.Or(strings.Compare(a.Base, b.Base), strings.Compare(a.Quote, b.Quote))
+	})
+	return rates, nil
+}
+
+func (r *MemoryFXRateRepository) Upsert(_ context.Context, rates []domain.FXRate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, rate := range rates {
+		r.rates[[2]string{rate.Base, rate.Quote}] = rate
+	}
+	return nil
+}
+
+// tenantBook identifies a book within the catalog of a tenant, for the
+// in-memory histories, which like their Postgres tables belong to the tenant
+// that wrote them.
+type tenantBook struct {
+	tenant string
+	bookID uuid.UUID
+}
+
+func tenantBookOf(ctx context.Context, bookID uuid.UUID) tenantBook {
+	return tenantBook{tenant: domain.TenantFromContext(ctx), bookID: bookID}
+}
+
+// MemoryRevisionRepository is the in-memory counterpart of
+// RevisionRepository.
+type MemoryRevisionRepository struct {
+	mu        sync.RWMutex
+	revisions map[tenantBook][]domain.BookRevision
+}
+
+func NewMemoryRevisionRepository() *MemoryRevisionRepository {
+	return &MemoryRevisionRepository{revisions: make(map[tenantBook][]domain.BookRevision)}
+}
+
+func (r *MemoryRevisionRepository) Append(ctx context.Context, revision domain.BookRevision) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := tenantBookOf(ctx, revision.BookID)
+	for _, existing := range r.revisions[key] {
+		if existing.Version == revision.Version {
+			return fmt.Errorf("revision %d of book %s already exists", revision.Version, revision.BookID)
+		}
+	}
+	r.revisions[key] = append(r.revisions[key], revision)
+	return nil
+}
+
+func (r *MemoryRevisionRepository) List(ctx context.Context, query domain.BookRevisionQuery) (domain.BookRevisionPage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var revisions []domain.BookRevision
+	for _, revision := range r.revisions[tenantBookOf(ctx, query.BookID)] {
+		if query.BeforeVersion == nil || revision.Version < *query.BeforeVersion {
+			revisions = append(revisions, revision)
+		}
+	}
+	slices.SortFunc(revisions, func(a, b domain.BookRevision) int {
+		return cmp.Compare(b.Version, a.Version)
+	})
+	if len(revisions) > query.Limit+1 {
+		revisions = revisions[:query.Limit+1]
+	}
+	return buildRevisionPage(revisions, query.Limit), nil
+}
+
+// MemoryStockLedgerRepository is the in-memory counterpart of
+// StockLedgerRepository.
+type MemoryStockLedgerRepository struct {
+	mu          sync.RWMutex
+	adjustments map[tenantBook][]domain.StockAdjustment
+}
+
+func NewMemoryStockLedgerRepository() *MemoryStockLedgerRepository {
+	return &MemoryStockLedgerRepository{adjustments: make(map[tenantBook][]domain.StockAdjustment)}
+}
+
+func (r *MemoryStockLedgerRepository) Append(ctx context.Context, adjustment domain.StockAdjustment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := tenantBookOf(ctx, adjustment.BookID)
+	for _, existing := range r.adjustments[key] {
+		if existing.BookVersion == adjustment.BookVersion {
+			return fmt.Errorf("stock adjustment for version %d of book %s already exists", adjustment.BookVersion, adjustment.BookID)
+		}
+	}
+	r.adjustments[key] = append(r.adjustments[key], adjustment)
+	return nil
+}
+
+func (r *MemoryStockLedgerRepository) List(ctx context.Context, query domain.StockLedgerQuery) (domain.StockLedgerPage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var adjustments []domain.StockAdjustment
+	for _, adjustment := range r.adjustments[tenantBookOf(ctx, query.BookID)] {
+		if query.BeforeVersion == nil || adjustment.BookVersion < *query.BeforeVersion {
+			adjustments = append(adjustments, adjustment)
+		}
+	}
+	slices.SortFunc(adjustments, func(a, b domain.StockAdjustment) int {
+		return cmp.Compare(b.BookVersion, a.BookVersion)
+	})
+	if len(adjustments) > query.Limit+1 {
+		adjustments = adjustments[:query.Limit+1]
+	}
+	return buildStockLedgerPage(adjustments, query.Limit), nil
+}
+
+// MemoryAuthorRepository is the in-memory counterpart of AuthorRepository.
+// It shares the store of the MemoryBookRepository it was taken from, so that
+// books see renamed authors and credited authors cannot be deleted.
+type MemoryAuthorRepository struct {
+	store *MemoryBookRepository
+}
+
+// Authors returns the author repository backed by the same store as r.
+func (r *MemoryBookRepository) Authors() *MemoryAuthorRepository {
+	return &MemoryAuthorRepository{store: r}
+}
+
+func (r *MemoryAuthorRepository) Create(ctx context.Context, author domain.Author) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	c := r.store.catalog(ctx)
+
+	if _, exists := c.authors[author.ID]; exists {
+		return fmt.Errorf("author %s already exists", author.ID)
+	}
+	c.authors[author.ID] = author
+	return nil
+}
+
+func (r *MemoryAuthorRepository) Get(ctx context.Context, id uuid.UUID) (domain.Author, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	c := r.store.catalog(ctx)
+
+	author, ok := c.authors[id]
+	if !ok {
+		return domain.Author{}, domain.ErrAuthorNotFound
+	}
+	return author, nil
+}
+
+func (r *MemoryAuthorRepository) FindByName(ctx context.Context, name string) (domain.Author, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	c := r.store.catalog(ctx)
+
+	var found *domain.Author
+	for _, author := range c.authors {
+		if !strings.EqualFold(author.Name, name) {
+			continue
+		}
+		if found == nil || compareAuthorsByAge(author, *found) < 0 {
+			found = &author
+		}
+	}
+	if found == nil {
+		return domain.Author{}, domain.ErrAuthorNotFound
+	}
+	return *found, nil
+}
+
+func (r *MemoryAuthorRepository) List(ctx context.Context, query domain.AuthorQuery) (domain.AuthorPage, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	c := r.store.catalog(ctx)
+
+	var authors []domain.Author
+	for _, author := range c.authors {
+		if query.Name != "" && !strings.Contains(strings.ToLower(author.Name), strings.ToLower(query.Name)) {
+			continue
+		}
+		if query.After != nil && compareByName(author.Name, author.ID, query.After.Values[0], query.After.ID) <= 0 {
+			continue
+		}
+		authors = append(authors, author)
+	}
+	slices.SortFunc(authors, func(a, b domain.Author) int {
+		return compareByName(a.Name, a.ID, b.Name, b.ID)
+	})
+	if len(authors) > query.Limit+1 {
+		authors = authors[:query.Limit+1]
+	}
+	return buildAuthorPage(authors, query.Limit), nil
+}
+
+func (r *MemoryAuthorRepository) Update(ctx context.Context, author domain.Author) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	c := r.store.catalog(ctx)
+
+	stored, ok := c.authors[author.ID]
+	if !ok {
+		return domain.ErrAuthorNotFound
+	}
+	stored.Name = author.Name
+	stored.UpdatedAt = author.UpdatedAt
+	c.authors[author.ID] = stored
+	return nil
+}
+
+func (r *MemoryAuthorRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	c := r.store.catalog(ctx)
+
+	if _, ok := c.authors[id]; !ok {
+		return domain.ErrAuthorNotFound
+	}
+	for _, book := range c.books {
+		for _, author := range book.Authors {
+			if author.AuthorID == id {
+				return domain.ErrAuthorInUse
+			}
+		}
+	}
+	delete(c.authors, id)
+	return nil
+}
+
+// compareByName orders authors and categories like their lists: by name
+// ignoring case, then by ID.
+func compareByName(nameA string, idA uuid.UUID, nameB string, idB uuid.UUID) int {
+	if c := strings.Compare(strings.ToLower(nameA), strings.ToLower(nameB)); c != 0 {
+		return c
+	}
+	return bytes.Compare(idA[:], idB[:])
+}
+
+func compareAuthorsByAge(a, b domain.Author) int {
+	if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+		return c
+	}
+	return bytes.Compare(a.ID[:], b.ID[:])
+}
+
+// MemoryCategoryRepository is the in-memory counterpart of
+// CategoryRepository, sharing the store of a MemoryBookRepository.
+type MemoryCategoryRepository struct {
+	store *MemoryBookRepository
+}
+
+// Categories returns the category repository backed by the same store as r.
+func (r *MemoryBookRepository) Categories() *MemoryCategoryRepository {
+	return &MemoryCategoryRepository{store: r}
+}
+
+func (r *MemoryCategoryRepository) Create(ctx context.Context, category domain.Category) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	c := r.store.catalog(ctx)
+
+	if _, exists := c.categories[category.ID]; exists {
+		return fmt.Errorf("category %s already exists", category.ID)
+	}
+	if err := c.checkPlacement(category); err != nil {
+		return err
+	}
+	c.categories[category.ID] = cloneCategory(category)
+	return nil
+}
+
+func (r *MemoryCategoryRepository) Get(ctx context.Context, id uuid.UUID) (domain.Category, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	c := r.store.catalog(ctx)
+
+	category, ok := c.categories[id]
+	if !ok {
+		return domain.Category{}, domain.ErrCategoryNotFound
+	}
+	return cloneCategory(category), nil
+}
+
+func (r *MemoryCategoryRepository) List(ctx context.Context) ([]domain.Category, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	c := r.store.catalog(ctx)
+
+	categories := make([]domain.Category, 0, len(c.categories))
+	for _, category := range c.categories {
+		categories = append(categories, cloneCategory(category))
+	}
+	slices.SortFunc(categories, func(a, b domain.Category) int {
+		return compareByName(a.Name, a.ID, b.Name, b.ID)
+	})
+	return categories, nil
+}
+
+func (r *MemoryCategoryRepository) Update(ctx context.Context, category domain.Category) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	c := r.store.catalog(ctx)
+
+	stored, ok := c.categories[category.ID]
+	if !ok {
+		return domain.ErrCategoryNotFound
+	}
+	if err := c.checkPlacement(category); err != nil {
+		return err
+	}
+	stored.ParentID = category.ParentID
+	stored.Name = category.Name
+	stored.UpdatedAt = category.UpdatedAt
+	c.categories[category.ID] = cloneCategory(stored)
+	return nil
+}
+
+func (r *MemoryCategoryRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	c := r.store.catalog(ctx)
+
+	if _, ok := c.categories[id]; !ok {
+		return domain.ErrCategoryNotFound
+	}
+	for _, category := range c.categories {
+		if category.ParentID != nil && *category.ParentID == id {
+			return domain.ErrCategoryInUse
+		}
+	}
+	for _, book := range c.books {
+		for _, category := range book.Categories {
+			if category.CategoryID == id {
+				return domain.ErrCategoryInUse
+			}
+		}
+	}
+	delete(c.categories, id)
+	return nil
+}
+
+// checkPlacement enforces the parent foreign key and the unique sibling
+// name index. Callers must hold the lock.
+func (c *memoryCatalog) checkPlacement(category domain.Category) error {
+	if category.ParentID != nil {
+		if _, ok := c.categories[*category.ParentID]; !ok {
+			return domain.ErrCategoryNotFound
+		}
+	}
+	for _, sibling := range c.categories {
+		if sibling.ID != category.ID && sameParent(sibling.ParentID, category.ParentID) && strings.EqualFold(sibling.Name, category.Name) {
+			return domain.ErrDuplicateCategory
+		}
+	}
+	return nil
+}
+
+func sameParent(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func cloneCategory(category domain.Category) domain.Category {
+	if category.ParentID != nil {
+		parentID := *category.ParentID
+		category.ParentID = &parentID
+	}
+	return category
+}
+
+// MemoryTagRepository is the in-memory counterpart of TagRepository,
+// sharing the store of a MemoryBookRepository.
+type MemoryTagRepository struct {
+	store *MemoryBookRepository
+}
+
+// Tags returns the tag repository backed by the same store as r.
+func (r *MemoryBookRepository) Tags() *MemoryTagRepository {
+	return &MemoryTagRepository{store: r}
+}
+
+func (r *MemoryTagRepository) Create(ctx context.Context, tag domain.Tag) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	c := r.store.catalog(ctx)
+
+	if _, exists := c.tagNamed(tag.Name); exists {
+		return domain.ErrDuplicateTag
+	}
+	c.tags[tag.ID] = tag
+	return nil
+}
+
+func (r *MemoryTagRepository) Ensure(ctx context.Context, names []string, createdAt time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	c := r.store.catalog(ctx)
+
+	for _, name := range names {
+		if _, exists := c.tagNamed(name); !exists {
+			id := uuid.New()
+			c.tags[id] = domain.Tag{ID: id, Name: name, CreatedAt: createdAt}
+		}
+	}
+	return nil
+}
+
+func (r *MemoryTagRepository) Get(ctx context.Context, id uuid.UUID) (domain.Tag, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	c := r.store.catalog(ctx)
+
+	tag, ok := c.tags[id]
+	if !ok {
+		return domain.Tag{}, domain.ErrTagNotFound
+	}
+	return tag, nil
+}
+
+func (r *MemoryTagRepository) List(ctx context.Context, prefix string) ([]domain.Tag, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	c := r.store.catalog(ctx)
+
+	tags := []domain.Tag{}
+	for _, tag := range c.tags {
+		if strings.HasPrefix(tag.Name, prefix) {
+			tags = append(tags, tag)
+		}
+	}
+	slices.SortFunc(tags, func(a, b domain.Tag) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return tags, nil
+}
+
+// Update renames the tag. Stored books hold tag names, so they are renamed
+// too, which the join in bookColumns does for Postgres.
+func (r *MemoryTagRepository) Update(ctx context.Context, tag domain.Tag) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	c := r.store.catalog(ctx)
+
+	stored, ok := c.tags[tag.ID]
+	if !ok {
+		return domain.ErrTagNotFound
+	}
+	if other, exists := c.tagNamed(tag.Name); exists && other.ID != tag.ID {
+		return domain.ErrDuplicateTag
+	}
+	for id, book := range c.books {
+		if i := slices.Index(book.Tags, stored.Name); i >= 0 {
+			book.Tags = slices.Clone(book.Tags)
+			book.Tags[i] = tag.Name
+			slices.Sort(book.Tags)
+			c.books[id] = book
+		}
+	}
+	stored.Name = tag.Name
+	c.tags[tag.ID] = stored
+	return nil
+}
+
+func (r *MemoryTagRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	c := r.store.catalog(ctx)
+
+	tag, ok := c.tags[id]
+	if !ok {
+		return domain.ErrTagNotFound
+	}
+	for _, book := range c.books {
+		if slices.Contains(book.Tags, tag.Name) {
+			return domain.ErrTagInUse
+		}
+	}
+	delete(c.tags, id)
+	return nil
+}
@@ -0,0 +1,357 @@
+package repo
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/bookapi/internal/domain"
+)
+
+func TestMemoryBookRepository_CRUD(t *testing.T) {
+	ctx := context.Background()
+	r := NewMemoryBookRepository()
+	now := time.Date(2025, 11, 10, 12, 0, 0, 0, time.UTC)
+	book := domain.Book{ID: uuid.New(), Title: "Dune", Author: "Frank Herbert", Price: domain.Money{Currency: "USD"}, CreatedAt: now, UpdatedAt: now, Version: 1}
+
+	require.NoError(t, r.Create(ctx, book))
+	require.Error(t, r.Create(ctx, book))
+
+	_, err := r.Get(ctx, uuid.New())
+	require.ErrorIs(t, err, domain.ErrBookNotFound)
+
+	book.Title = "Dune Messiah"
+	require.NoError(t, r.Update(ctx, book))
+	require.ErrorIs(t, r.Update(ctx, book), domain.ErrVersionConflict)
+
+	stored, err := r.Get(ctx, book.ID)
+	require.NoError(t, err)
+	require.Equal(t, "Dune Messiah", stored.Title)
+	require.Equal(t, int64(2), stored.Version)
+
+	stale := int64(1)
+	_, err = r.Delete(ctx, book.ID, &stale, now)
+	require.ErrorIs(t, err, domain.ErrVersionConflict)
+	deleted, err := r.Delete(ctx, book.ID, nil, now)
+	require.NoError(t, err)
+	require.Equal(t, now, *deleted.DeletedAt)
+	_, err = r.Get(ctx, book.ID)
+	require.ErrorIs(t, err, domain.ErrBookNotFound)
+	require.ErrorIs(t, r.Update(ctx, stored), domain.ErrBookNotFound)
+
+	restored, deletedAt, err := r.Restore(ctx, book.ID, now)
+	require.NoError(t, err)
+	require.Nil(t, restored.DeletedAt)
+	require.Equal(t, now, deletedAt)
+	_, _, err = r.Restore(ctx, book.ID, now)
+	require.ErrorIs(t, err, domain.ErrBookNotDeleted)
+
+	_, err = r.Delete(ctx, book.ID, nil, now)
+	require.NoError(t, err)
+	purged, err := r.Purge(ctx, now.Add(time.Second))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), purged)
+	_, _, err = r.Restore(ctx, book.ID, now)
+	require.ErrorIs(t, err, domain.ErrBookNotFound)
+}
+
+func TestMemoryBookRepository_Tenants(t *testing.T) {
+	shopA := domain.ContextWithTenant(context.Background(), "shop-a")
+	shopB := domain.ContextWithTenant(context.Background(), "shop-b")
+	r := NewMemoryBookRepository()
+	tags := r.Tags()
+	now := time.Date(2025, 11, 10, 12, 0, 0, 0, time.UTC)
+
+	require.NoError(t, tags.Ensure(shopA, []string{"classic"}, now))
+	require.NoError(t, tags.Ensure(shopB, []string{"classic"}, now), "tag names are unique per tenant")
+	book := domain.Book{ID: uuid.New(), Title: "Dune", Author: "Frank Herbert", ISBN: "9780441013593", Tags: []string{"classic"}, CreatedAt: now, UpdatedAt: now, Version: 1}
+	require.NoError(t, r.Create(shopA, book))
+
+	_, err := r.Get(shopB, book.ID)
+	require.ErrorIs(t, err, domain.ErrBookNotFound)
+	_, err = r.GetByISBN(shopB, book.ISBN)
+	require.ErrorIs(t, err, domain.ErrBookNotFound)
+	page, err := r.List(shopB, domain.BookQuery{Sort: domain.DefaultBookSort, Limit: 10})
+	require.NoError(t, err)
+	require.Empty(t, page.Books)
+	require.ErrorIs(t, r.Update(shopB, book), domain.ErrBookNotFound)
+	_, err = r.Delete(shopB, book.ID, nil, now)
+	require.ErrorIs(t, err, domain.ErrBookNotFound)
+
+	other := book
+	other.ID = uuid.New()
+	require.NoError(t, r.Create(shopB, other), "ISBNs are unique per tenant")
+	_, err = r.Delete(shopB, other.ID, nil, now)
+	require.NoError(t, err)
+	purged, err := r.Purge(shopB, now.Add(time.Second))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), purged)
+
+	stored, err := r.Get(shopA, book.ID)
+	require.NoError(t, err)
+	require.Equal(t, "Dune", stored.Title)
+	listed, err := tags.List(shopA, "")
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	require.ErrorIs(t, tags.Delete(shopA, listed[0].ID), domain.ErrTagInUse)
+	require.ErrorIs(t, tags.Delete(shopB, listed[0].ID), domain.ErrTagNotFound)
+}
+
+func TestMemoryBookRepository_AdjustStock(t *testing.T) {
+	ctx := context.Background()
+	r := NewMemoryBookRepository()
+	now := time.Date(2025, 11, 10, 12, 0, 0, 0, time.UTC)
+	book := domain.Book{ID: uuid.New(), Title: "Dune", Author: "Frank Herbert", Price: domain.Money{Currency: "USD"}, Stock: 5, CreatedAt: now, UpdatedAt: now, Version: 1}
+	require.NoError(t, r.Create(ctx, book))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = r.AdjustStock(ctx, book.ID, -1, now)
+		}()
+	}
+	wg.Wait()
+
+	stored, err := r.Get(ctx, book.ID)
+	require.NoError(t, err)
+	require.Equal(t, 0, stored.Stock)
+	require.Equal(t, int64(6), stored.Version)
+
+	_, err = r.AdjustStock(ctx, book.ID, -1, now)
+	require.ErrorIs(t, err, domain.ErrInsufficientStock)
+	adjusted, err := r.AdjustStock(ctx, book.ID, 3, now.Add(time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, 3, adjusted.Stock)
+	require.Equal(t, now.Add(time.Minute), adjusted.UpdatedAt)
+	_, err = r.AdjustStock(ctx, uuid.New(), 1, now)
+	require.ErrorIs(t, err, domain.ErrBookNotFound)
+}
+
+func TestMemoryBookRepository_ISBN(t *testing.T) {
+	ctx := context.Background()
+	r := NewMemoryBookRepository()
+	now := time.Date(2025, 11, 10, 12, 0, 0, 0, time.UTC)
+	newBook := func(isbn string) domain.Book {
+		return domain.Book{ID: uuid.New(), Title: "Dune", Author: "Frank Herbert", ISBN: isbn, Price: domain.Money{Currency: "USD"}, CreatedAt: now, UpdatedAt: now, Version: 1}
+	}
+
+	dune := newBook("9780441172719")
+	require.NoError(t, r.Create(ctx, dune))
+	require.NoError(t, r.Create(ctx, newBook("")))
+	require.NoError(t, r.Create(ctx, newBook("")))
+	require.ErrorIs(t, r.Create(ctx, newBook(dune.ISBN)), domain.ErrDuplicateISBN)
+	require.ErrorIs(t, r.CreateMany(ctx, []domain.Book{newBook("9780441013593"), newBook("9780441013593")}), domain.ErrDuplicateISBN)
+
+	found, err := r.GetByISBN(ctx, dune.ISBN)
+	require.NoError(t, err)
+	require.Equal(t, dune.ID, found.ID)
+
+	// A soft-deleted book keeps its ISBN, so it can be restored.
+	_, err = r.Delete(ctx, dune.ID, nil, now)
+	require.NoError(t, err)
+	_, err = r.GetByISBN(ctx, dune.ISBN)
+	require.ErrorIs(t, err, domain.ErrBookNotFound)
+	require.ErrorIs(t, r.Create(ctx, newBook(dune.ISBN)), domain.ErrDuplicateISBN)
+}
+
+func TestMemoryBookRepository_ListPaginates(t *testing.T) {
+	ctx := context.Background()
+	r := NewMemoryBookRepository()
+	base := time.Date(2025, 11, 10, 12, 0, 0, 0, time.UTC)
+	prices := []string{"10", "30", "20", "30.000", "5.5"}
+	for i, amount := range prices {
+		price, err := domain.ParseMoney(amount, "USD")
+		require.NoError(t, err)
+		require.NoError(t, r.Create(ctx, domain.Book{
+			ID:        uuid.New(),
+			Title:     string(rune('A' + i)),
+			Author:    "Author",
+			Price:     price,
+			Stock:     i,
+			CreatedAt: base.Add(time.Duration(i) * time.Minute),
+		}))
+	}
+
+	sorts := []domain.BookSort{{Field: domain.SortByPrice, Desc: true}, {Field: domain.SortByTitle}}
+	query := domain.BookQuery{Sort: sorts, Limit: 2}
+
+	var titles []string
+	var pages []domain.BookPage
+	for {
+		page, err := r.List(ctx, query)
+		require.NoError(t, err)
+		pages = append(pages, page)
+		for _, book := range page.Books {
+			titles = append(titles, book.Title)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor, err := domain.DecodeCursor(page.NextCursor)
+		require.NoError(t, err)
+		query.Cursor = &cursor
+	}
+	require.Equal(t, []string{"B", "D", "C", "A", "E"}, titles)
+
+	prev, err := domain.DecodeCursor(pages[1].PrevCursor)
+	require.NoError(t, err)
+	page, err := r.List(ctx, domain.BookQuery{Sort: sorts, Limit: 2, Cursor: &prev})
+	require.NoError(t, err)
+	require.Equal(t, pages[0].Books, page.Books)
+	require.Empty(t, page.PrevCursor)
+
+	page, err = r.List(ctx, domain.BookQuery{Sort: domain.DefaultBookSort, Limit: 10, InStock: true, Author: "auth"})
+	require.NoError(t, err)
+	require.Len(t, page.Books, 4)
+}
+
+func TestMemoryBookRepository_Search(t *testing.T) {
+	ctx := context.Background()
+	r := NewMemoryBookRepository()
+	require.NoError(t, r.Create(ctx, domain.Book{ID: uuid.New(), Title: "The Go Programming Language", Author: "Alan Donovan"}))
+	require.NoError(t, r.Create(ctx, domain.Book{ID: uuid.New(), Title: "Learning Go", Author: "Jon Bodner"}))
+
+	results, err := r.Search(ctx, domain.BookSearchQuery{Query: "go -learning", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, "The <mark>Go</mark> Programming Language by Alan Donovan", results[0].Snippet)
+
+	results, err = r.Search(ctx, domain.BookSearchQuery{Query: "donovan", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	require.NoError(t, r.Create(ctx, domain.Book{ID: uuid.New(), Title: `Rust <img src=x onerror="alert(1)"> & Friends`, Author: "Mallory"}))
+	results, err = r.Search(ctx, domain.BookSearchQuery{Query: "rust", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, `<mark>Rust</mark> &lt;img src=x onerror="alert(1)"&gt; &amp; Friends by Mallory`, results[0].Snippet)
+}
+
+func TestMemoryAuthorRepository(t *testing.T) {
+	ctx := context.Background()
+	books := NewMemoryBookRepository()
+	r := books.Authors()
+	now := time.Date(2025, 11, 10, 12, 0, 0, 0, time.UTC)
+
+	tolkien := domain.Author{ID: uuid.New(), Name: "J. R. R. Tolkien", CreatedAt: now, UpdatedAt: now}
+	christopher := domain.Author{ID: uuid.New(), Name: "Christopher Tolkien", CreatedAt: now, UpdatedAt: now}
+	require.NoError(t, r.Create(ctx, tolkien))
+	require.NoError(t, r.Create(ctx, christopher))
+
+	found, err := r.FindByName(ctx, "j. r. r. tolkien")
+	require.NoError(t, err)
+	require.Equal(t, tolkien.ID, found.ID)
+	_, err = r.FindByName(ctx, "JRR Tolkien")
+	require.ErrorIs(t, err, domain.ErrAuthorNotFound)
+
+	page, err := r.List(ctx, domain.AuthorQuery{Name: "tolkien", Limit: 1})
+	require.NoError(t, err)
+	require.Equal(t, []domain.Author{christopher}, page.Authors)
+	after, err := domain.DecodeCursor(page.NextCursor)
+	require.NoError(t, err)
+	page, err = r.List(ctx, domain.AuthorQuery{Limit: 1, After: &after})
+	require.NoError(t, err)
+	require.Equal(t, []domain.Author{tolkien}, page.Authors)
+	require.Empty(t, page.NextCursor)
+
+	book := domain.Book{
+		ID: uuid.New(), Title: "The Silmarillion", Author: "J. R. R. Tolkien",
+		Authors: []domain.BookAuthor{
+			{AuthorID: tolkien.ID, Role: domain.RoleAuthor},
+			{AuthorID: christopher.ID, Role: domain.RoleEditor},
+		},
+		Price: domain.Money{Currency: "USD"}, CreatedAt: now, UpdatedAt: now, Version: 1,
+	}
+	require.NoError(t, books.Create(ctx, book))
+	unknown := book
+	unknown.ID = uuid.New()
+	unknown.Authors = []domain.BookAuthor{{AuthorID: uuid.New(), Role: domain.RoleAuthor}}
+	require.ErrorIs(t, books.Create(ctx, unknown), domain.ErrAuthorNotFound)
+
+	tolkien.Name = "John Ronald Reuel Tolkien"
+	require.NoError(t, r.Update(ctx, tolkien))
+	stored, err := books.Get(ctx, book.ID)
+	require.NoError(t, err)
+	require.Equal(t, "John Ronald Reuel Tolkien", stored.Authors[0].Name)
+	require.Equal(t, domain.RoleEditor, stored.Authors[1].Role)
+
+	credited, err := books.List(ctx, domain.BookQuery{AuthorID: &christopher.ID, Sort: domain.DefaultBookSort, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, credited.Books, 1)
+
+	require.ErrorIs(t, r.Delete(ctx, christopher.ID), domain.ErrAuthorInUse)
+	_, err = books.Delete(ctx, book.ID, nil, now)
+	require.NoError(t, err)
+	require.ErrorIs(t, r.Delete(ctx, christopher.ID), domain.ErrAuthorInUse, "soft-deleted books keep their credits")
+	_, err = books.Purge(ctx, now.Add(time.Second))
+	require.NoError(t, err)
+	require.NoError(t, r.Delete(ctx, christopher.ID))
+	require.ErrorIs(t, r.Delete(ctx, christopher.ID), domain.ErrAuthorNotFound)
+}
+
+func TestMemoryTaxonomy(t *testing.T) {
+	ctx := context.Background()
+	books := NewMemoryBookRepository()
+	categories := books.Categories()
+	tags := books.Tags()
+	now := time.Date(2025, 11, 10, 12, 0, 0, 0, time.UTC)
+
+	fiction := domain.Category{ID: uuid.New(), Name: "Fiction", CreatedAt: now, UpdatedAt: now}
+	fantasy := domain.Category{ID: uuid.New(), ParentID: &fiction.ID, Name: "Fantasy", CreatedAt: now, UpdatedAt: now}
+	poetry := domain.Category{ID: uuid.New(), Name: "Poetry", CreatedAt: now, UpdatedAt: now}
+	require.NoError(t, categories.Create(ctx, fiction))
+	require.NoError(t, categories.Create(ctx, fantasy))
+	require.NoError(t, categories.Create(ctx, poetry))
+	duplicate := domain.Category{ID: uuid.New(), ParentID: &fiction.ID, Name: "FANTASY", CreatedAt: now, UpdatedAt: now}
+	require.ErrorIs(t, categories.Create(ctx, duplicate), domain.ErrDuplicateCategory)
+	orphan := domain.Category{ID: uuid.New(), ParentID: &duplicate.ID, Name: "Orphan", CreatedAt: now, UpdatedAt: now}
+	require.ErrorIs(t, categories.Create(ctx, orphan), domain.ErrCategoryNotFound)
+
+	require.NoError(t, tags.Ensure(ctx, []string{"dragons", "epic"}, now))
+	require.NoError(t, tags.Ensure(ctx, []string{"epic"}, now))
+	listed, err := tags.List(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, listed, 2)
+
+	book := domain.Book{
+		ID: uuid.New(), Title: "The Hobbit", Author: "J. R. R. Tolkien",
+		Categories: []domain.BookCategory{{CategoryID: fantasy.ID}},
+		Tags:       []string{"dragons"},
+		Price:      domain.Money{Currency: "USD"}, CreatedAt: now, UpdatedAt: now, Version: 1,
+	}
+	require.NoError(t, books.Create(ctx, book))
+	untagged := book
+	untagged.ID = uuid.New()
+	untagged.Tags = []string{"unknown"}
+	require.ErrorIs(t, books.Create(ctx, untagged), domain.ErrTagNotFound)
+
+	page, err := books.List(ctx, domain.BookQuery{CategoryID: &fiction.ID, Sort: domain.DefaultBookSort, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, page.Books, 1, "books in subcategories match")
+	require.Equal(t, "Fantasy", page.Books[0].Categories[0].Name)
+	page, err = books.List(ctx, domain.BookQuery{CategoryID: &poetry.ID, Sort: domain.DefaultBookSort, Limit: 10})
+	require.NoError(t, err)
+	require.Empty(t, page.Books)
+	page, err = books.List(ctx, domain.BookQuery{Tag: "dragons", Sort: domain.DefaultBookSort, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, page.Books, 1)
+
+	dragons := listed[0]
+	dragons.Name = "wyrms"
+	require.NoError(t, tags.Update(ctx, dragons))
+	stored, err := books.Get(ctx, book.ID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"wyrms"}, stored.Tags)
+
+	require.ErrorIs(t, categories.Delete(ctx, fiction.ID), domain.ErrCategoryInUse, "fiction has a subcategory")
+	require.ErrorIs(t, categories.Delete(ctx, fantasy.ID), domain.ErrCategoryInUse, "fantasy has a book")
+	require.ErrorIs(t, tags.Delete(ctx, dragons.ID), domain.ErrTagInUse)
+	require.NoError(t, tags.Delete(ctx, listed[1].ID))
+	require.NoError(t, categories.Delete(ctx, poetry.ID))
+}
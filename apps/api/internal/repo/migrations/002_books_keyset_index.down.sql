@@ -0,0 +1 @@
+DROP INDEX IF EXISTS books_created_at_id_idx;
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS books_created_at_id_idx ON books (created_at, id);
@@ -0,0 +1,6 @@
+-- Without the column, soft-deleted rows would reappear as live books.
+DELETE FROM books WHERE deleted_at IS NOT NULL;
+
+DROP INDEX books_deleted_at_idx;
+
+ALTER TABLE books DROP COLUMN deleted_at;
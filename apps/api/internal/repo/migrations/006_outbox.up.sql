@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS outbox (
+    id UUID PRIMARY KEY,
+    event_type TEXT NOT NULL,
+    aggregate_id UUID NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL,
+    next_attempt_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX outbox_next_attempt_at_idx ON outbox (next_attempt_at, created_at);
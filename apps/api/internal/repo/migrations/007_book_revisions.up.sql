@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS book_revisions (
+    id UUID PRIMARY KEY,
+    book_id UUID NOT NULL,
+    version BIGINT NOT NULL,
+    action TEXT NOT NULL CHECK (action IN ('create', 'update', 'delete', 'restore')),
+    actor TEXT NOT NULL,
+    snapshot JSONB NOT NULL,
+    changes JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    -- No foreign key: the audit trail outlives purged books.
+    UNIQUE (book_id, version)
+);
@@ -0,0 +1,2 @@
+-- Rounds three-decimal amounts to cents.
+ALTER TABLE books ALTER COLUMN price TYPE NUMERIC(12,2);
@@ -0,0 +1,2 @@
+-- Three decimal places fit every supported currency's minor unit (e.g. KWD fils).
+ALTER TABLE books ALTER COLUMN price TYPE NUMERIC(15,3);
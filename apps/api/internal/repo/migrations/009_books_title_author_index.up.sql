@@ -0,0 +1,2 @@
+-- Imports look books up by title and author, ignoring case.
+CREATE INDEX books_title_author_idx ON books (lower(title), lower(author)) WHERE deleted_at IS NULL;
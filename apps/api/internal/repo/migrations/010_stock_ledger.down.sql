@@ -0,0 +1,2 @@
+DROP TABLE stock_ledger;
+DROP FUNCTION stock_ledger_reject_change();
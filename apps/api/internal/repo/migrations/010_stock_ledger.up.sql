@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS stock_ledger (
+    id UUID PRIMARY KEY,
+    book_id UUID NOT NULL,
+    delta INTEGER NOT NULL CHECK (delta <> 0),
+    reason TEXT NOT NULL CHECK (reason IN ('sale', 'return', 'restock', 'shrinkage')),
+    reference TEXT NOT NULL DEFAULT '',
+    actor TEXT NOT NULL,
+    stock_after INTEGER NOT NULL CHECK (stock_after >= 0),
+    book_version BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    -- No foreign key: like book_revisions, the ledger outlives purged books.
+    UNIQUE (book_id, book_version)
+);
+
+-- The ledger is append-only; corrections are recorded as new adjustments.
+CREATE FUNCTION stock_ledger_reject_change() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'stock_ledger is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER stock_ledger_append_only
+    BEFORE UPDATE OR DELETE ON stock_ledger
+    FOR EACH STATEMENT EXECUTE FUNCTION stock_ledger_reject_change();
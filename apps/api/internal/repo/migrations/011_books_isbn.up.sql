@@ -0,0 +1,5 @@
+-- Normalized ISBN-13; NULL for books without one. Soft-deleted books keep
+-- their ISBN so that restoring them cannot create a duplicate.
+ALTER TABLE books ADD COLUMN isbn CHAR(13) CHECK (isbn ~ '^97[89][0-9]{10}$');
+
+CREATE UNIQUE INDEX books_isbn_key ON books (isbn);
@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS authors (
+    id UUID PRIMARY KEY,
+    name VARCHAR(200) NOT NULL CHECK (btrim(name) <> ''),
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+-- Authors are listed by name and books without links are matched to an
+-- author by name, ignoring case.
+CREATE INDEX authors_name_idx ON authors (lower(name), id);
+
+-- Credits a book's authors, editors and translators, in position order.
+-- Links of purged books go with them; authors cannot be deleted while they
+-- are credited on a book.
+CREATE TABLE IF NOT EXISTS book_authors (
+    book_id UUID NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+    author_id UUID NOT NULL REFERENCES authors (id) ON DELETE RESTRICT,
+    role TEXT NOT NULL CHECK (role IN ('author', 'editor', 'translator')),
+    position INTEGER NOT NULL CHECK (position >= 0),
+    PRIMARY KEY (book_id, author_id, role),
+    UNIQUE (book_id, position)
+);
+
+CREATE INDEX book_authors_author_id_idx ON book_authors (author_id);
+
+-- Backfill: one author per distinct author string, credited as the only
+-- author of every book that has that string.
+INSERT INTO authors (id, name, created_at, updated_at)
+SELECT uuid_generate_v4(), min(btrim(author)), min(created_at), min(created_at)
+FROM books
+WHERE btrim(author) <> ''
+GROUP BY lower(btrim(author));
+
+INSERT INTO book_authors (book_id, author_id, role, position)
+SELECT b.id, a.id, 'author', 0
+FROM books b
+JOIN authors a ON lower(a.name) = lower(btrim(b.author));
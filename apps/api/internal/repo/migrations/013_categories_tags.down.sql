@@ -0,0 +1,4 @@
+DROP TABLE book_tags;
+DROP TABLE book_categories;
+DROP TABLE tags;
+DROP TABLE categories;
@@ -0,0 +1,41 @@
+-- Categories form a tree. Sibling names are unique, ignoring case; a
+-- category cannot be deleted while it has subcategories.
+CREATE TABLE IF NOT EXISTS categories (
+    id UUID PRIMARY KEY,
+    parent_id UUID REFERENCES categories (id) ON DELETE RESTRICT,
+    name VARCHAR(100) NOT NULL CHECK (btrim(name) <> ''),
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    CHECK (parent_id <> id)
+);
+
+CREATE UNIQUE INDEX categories_parent_name_key
+    ON categories (coalesce(parent_id, '00000000-0000-0000-0000-000000000000'::uuid), lower(name));
+
+CREATE INDEX categories_parent_id_idx ON categories (parent_id);
+
+-- Tag names are stored normalized: lowercase with single spaces.
+CREATE TABLE IF NOT EXISTS tags (
+    id UUID PRIMARY KEY,
+    name VARCHAR(50) NOT NULL CHECK (name <> '' AND name = lower(name)),
+    created_at TIMESTAMPTZ NOT NULL,
+    CONSTRAINT tags_name_key UNIQUE (name)
+);
+
+-- Links of purged books go with them; categories and tags cannot be deleted
+-- while a book uses them.
+CREATE TABLE IF NOT EXISTS book_categories (
+    book_id UUID NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+    category_id UUID NOT NULL REFERENCES categories (id) ON DELETE RESTRICT,
+    PRIMARY KEY (book_id, category_id)
+);
+
+CREATE INDEX book_categories_category_id_idx ON book_categories (category_id);
+
+CREATE TABLE IF NOT EXISTS book_tags (
+    book_id UUID NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+    tag_id UUID NOT NULL REFERENCES tags (id) ON DELETE RESTRICT,
+    PRIMARY KEY (book_id, tag_id)
+);
+
+CREATE INDEX book_tags_tag_id_idx ON book_tags (tag_id);
@@ -0,0 +1,2 @@
+DROP TABLE fx_rates;
+DROP TABLE book_prices;
@@ -0,0 +1,19 @@
+-- Explicit prices of a book in currencies other than its base currency.
+-- Books without one for a currency are converted with fx_rates.
+CREATE TABLE IF NOT EXISTS book_prices (
+    book_id UUID NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+    currency CHAR(3) NOT NULL,
+    price NUMERIC(15,3) NOT NULL CHECK (price >= 0),
+    PRIMARY KEY (book_id, currency)
+);
+
+-- One unit of base is worth rate units of quote. Rates are replaced in place
+-- when they are reloaded.
+CREATE TABLE IF NOT EXISTS fx_rates (
+    base CHAR(3) NOT NULL,
+    quote CHAR(3) NOT NULL,
+    rate NUMERIC(24,12) NOT NULL CHECK (rate > 0),
+    updated_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (base, quote),
+    CHECK (base <> quote)
+);
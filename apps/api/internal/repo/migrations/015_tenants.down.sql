@@ -0,0 +1,55 @@
+-- Rolling back merges every tenant's catalog; it fails if two tenants share
+-- an ISBN, a sibling category name or a tag name.
+ALTER TABLE book_prices NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE book_prices DISABLE ROW LEVEL SECURITY;
+ALTER TABLE book_tags NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE book_tags DISABLE ROW LEVEL SECURITY;
+ALTER TABLE book_categories NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE book_categories DISABLE ROW LEVEL SECURITY;
+ALTER TABLE book_authors NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE book_authors DISABLE ROW LEVEL SECURITY;
+ALTER TABLE stock_ledger NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE stock_ledger DISABLE ROW LEVEL SECURITY;
+ALTER TABLE book_revisions NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE book_revisions DISABLE ROW LEVEL SECURITY;
+ALTER TABLE tags NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE tags DISABLE ROW LEVEL SECURITY;
+ALTER TABLE categories NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE categories DISABLE ROW LEVEL SECURITY;
+ALTER TABLE authors NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE authors DISABLE ROW LEVEL SECURITY;
+ALTER TABLE books NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE books DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY book_prices_tenant_isolation ON book_prices;
+DROP POLICY book_tags_tenant_isolation ON book_tags;
+DROP POLICY book_categories_tenant_isolation ON book_categories;
+DROP POLICY book_authors_tenant_isolation ON book_authors;
+DROP POLICY stock_ledger_tenant_isolation ON stock_ledger;
+DROP POLICY book_revisions_tenant_isolation ON book_revisions;
+DROP POLICY tags_tenant_isolation ON tags;
+DROP POLICY categories_tenant_isolation ON categories;
+DROP POLICY authors_tenant_isolation ON authors;
+DROP POLICY books_tenant_isolation ON books;
+
+DROP INDEX books_tenant_created_at_id_idx;
+
+DROP INDEX authors_name_idx;
+CREATE INDEX authors_name_idx ON authors (lower(name), id);
+
+ALTER TABLE tags DROP CONSTRAINT tags_name_key;
+ALTER TABLE tags ADD CONSTRAINT tags_name_key UNIQUE (name);
+
+DROP INDEX categories_parent_name_key;
+CREATE UNIQUE INDEX categories_parent_name_key
+    ON categories (coalesce(parent_id, '00000000-0000-0000-0000-000000000000'::uuid), lower(name));
+
+DROP INDEX books_isbn_key;
+CREATE UNIQUE INDEX books_isbn_key ON books (isbn);
+
+ALTER TABLE stock_ledger DROP COLUMN tenant_id;
+ALTER TABLE book_revisions DROP COLUMN tenant_id;
+ALTER TABLE tags DROP COLUMN tenant_id;
+ALTER TABLE categories DROP COLUMN tenant_id;
+ALTER TABLE authors DROP COLUMN tenant_id;
+ALTER TABLE books DROP COLUMN tenant_id;
@@ -0,0 +1,86 @@
+-- Every catalog row belongs to one tenant (storefront). The API sets
+-- app.tenant_id on each connection to the tenant of the request; new rows take
+-- it as their tenant_id and row-level security hides and protects the rows of
+-- every other tenant, so a query without a tenant filter cannot leak them.
+-- Existing rows belong to the default tenant. FORCE applies the policies to
+-- the table owner too; only superusers and BYPASSRLS roles skip them.
+ALTER TABLE books ADD COLUMN tenant_id TEXT NOT NULL DEFAULT 'default' CHECK (tenant_id <> '');
+ALTER TABLE authors ADD COLUMN tenant_id TEXT NOT NULL DEFAULT 'default' CHECK (tenant_id <> '');
+ALTER TABLE categories ADD COLUMN tenant_id TEXT NOT NULL DEFAULT 'default' CHECK (tenant_id <> '');
+ALTER TABLE tags ADD COLUMN tenant_id TEXT NOT NULL DEFAULT 'default' CHECK (tenant_id <> '');
+
+-- Revisions and stock adjustments outlive purged books, so they carry their
+-- own tenant instead of following their book's.
+ALTER TABLE book_revisions ADD COLUMN tenant_id TEXT NOT NULL DEFAULT 'default' CHECK (tenant_id <> '');
+ALTER TABLE stock_ledger ADD COLUMN tenant_id TEXT NOT NULL DEFAULT 'default' CHECK (tenant_id <> '');
+
+ALTER TABLE books ALTER COLUMN tenant_id SET DEFAULT current_setting('app.tenant_id');
+ALTER TABLE authors ALTER COLUMN tenant_id SET DEFAULT current_setting('app.tenant_id');
+ALTER TABLE categories ALTER COLUMN tenant_id SET DEFAULT current_setting('app.tenant_id');
+ALTER TABLE tags ALTER COLUMN tenant_id SET DEFAULT current_setting('app.tenant_id');
+ALTER TABLE book_revisions ALTER COLUMN tenant_id SET DEFAULT current_setting('app.tenant_id');
+ALTER TABLE stock_ledger ALTER COLUMN tenant_id SET DEFAULT current_setting('app.tenant_id');
+
+-- ISBNs, sibling category names and tag names are unique per tenant.
+DROP INDEX books_isbn_key;
+CREATE UNIQUE INDEX books_isbn_key ON books (tenant_id, isbn);
+
+DROP INDEX categories_parent_name_key;
+CREATE UNIQUE INDEX categories_parent_name_key
+    ON categories (tenant_id, coalesce(parent_id, '00000000-0000-0000-0000-000000000000'::uuid), lower(name));
+
+ALTER TABLE tags DROP CONSTRAINT tags_name_key;
+ALTER TABLE tags ADD CONSTRAINT tags_name_key UNIQUE (tenant_id, name);
+
+DROP INDEX authors_name_idx;
+CREATE INDEX authors_name_idx ON authors (tenant_id, lower(name), id);
+
+CREATE INDEX books_tenant_created_at_id_idx ON books (tenant_id, created_at, id);
+
+CREATE POLICY books_tenant_isolation ON books
+    USING (tenant_id = current_setting('app.tenant_id', true));
+CREATE POLICY authors_tenant_isolation ON authors
+    USING (tenant_id = current_setting('app.tenant_id', true));
+CREATE POLICY categories_tenant_isolation ON categories
+    USING (tenant_id = current_setting('app.tenant_id', true));
+CREATE POLICY tags_tenant_isolation ON tags
+    USING (tenant_id = current_setting('app.tenant_id', true));
+CREATE POLICY book_revisions_tenant_isolation ON book_revisions
+    USING (tenant_id = current_setting('app.tenant_id', true));
+CREATE POLICY stock_ledger_tenant_isolation ON stock_ledger
+    USING (tenant_id = current_setting('app.tenant_id', true));
+
+-- Links and explicit prices go with their book: they are visible when the
+-- book is, and may only point at authors, categories and tags of its tenant.
+CREATE POLICY book_authors_tenant_isolation ON book_authors
+    USING (book_id IN (SELECT id FROM books))
+    WITH CHECK (book_id IN (SELECT id FROM books) AND author_id IN (SELECT id FROM authors));
+CREATE POLICY book_categories_tenant_isolation ON book_categories
+    USING (book_id IN (SELECT id FROM books))
+    WITH CHECK (book_id IN (SELECT id FROM books) AND category_id IN (SELECT id FROM categories));
+CREATE POLICY book_tags_tenant_isolation ON book_tags
+    USING (book_id IN (SELECT id FROM books))
+    WITH CHECK (book_id IN (SELECT id FROM books) AND tag_id IN (SELECT id FROM tags));
+CREATE POLICY book_prices_tenant_isolation ON book_prices
+    USING (book_id IN (SELECT id FROM books));
+
+ALTER TABLE books ENABLE ROW LEVEL SECURITY;
+ALTER TABLE books FORCE ROW LEVEL SECURITY;
+ALTER TABLE authors ENABLE ROW LEVEL SECURITY;
+ALTER TABLE authors FORCE ROW LEVEL SECURITY;
+ALTER TABLE categories ENABLE ROW LEVEL SECURITY;
+ALTER TABLE categories FORCE ROW LEVEL SECURITY;
+ALTER TABLE tags ENABLE ROW LEVEL SECURITY;
+ALTER TABLE tags FORCE ROW LEVEL SECURITY;
+ALTER TABLE book_revisions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE book_revisions FORCE ROW LEVEL SECURITY;
+ALTER TABLE stock_ledger ENABLE ROW LEVEL SECURITY;
+ALTER TABLE stock_ledger FORCE ROW LEVEL SECURITY;
+ALTER TABLE book_authors ENABLE ROW LEVEL SECURITY;
+ALTER TABLE book_authors FORCE ROW LEVEL SECURITY;
+ALTER TABLE book_categories ENABLE ROW LEVEL SECURITY;
+ALTER TABLE book_categories FORCE ROW LEVEL SECURITY;
+ALTER TABLE book_tags ENABLE ROW LEVEL SECURITY;
+ALTER TABLE book_tags FORCE ROW LEVEL SECURITY;
+ALTER TABLE book_prices ENABLE ROW LEVEL SECURITY;
+ALTER TABLE book_prices FORCE ROW LEVEL SECURITY;
@@ -0,0 +1 @@
+ALTER TABLE outbox ADD COLUMN actor TEXT NOT NULL DEFAULT 'anonymous';
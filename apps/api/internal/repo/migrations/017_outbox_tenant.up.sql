@@ -0,0 +1,4 @@
+-- Events name the tenant whose catalog they are about, so consumers serving
+-- one storefront can tell its events from the others'. Events enqueued
+-- before are taken to belong to the default tenant.
+ALTER TABLE outbox ADD COLUMN tenant_id TEXT NOT NULL DEFAULT 'default' CHECK (tenant_id <> '');
@@ -0,0 +1,463 @@
+package migrations
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// advisoryLockKey serialises migration runs across processes sharing a
+// database, e.g. several API tasks starting at once.
+const advisoryLockKey int64 = 0x626f6f6b617069 // "bookapi"
+
+const createSchemaMigrationsTable = `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version BIGINT PRIMARY KEY,
+		name TEXT NOT NULL,
+		checksum TEXT NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)
+`
+
+var fileNamePattern = regexp.MustCompile(`^(\d+)_([A-Za-z0-9_]+)\.(up|down)\.sql$`)
+
+var (
+	// ErrChecksumMismatch is returned when an already applied migration file
+	// has been edited since it ran.
+	ErrChecksumMismatch = errors.New("applied migration has been modified")
+	// ErrIrreversible is returned when a rollback includes a migration that
+	// has no down file.
+	ErrIrreversible = errors.New("migration has no down file")
+	// ErrStalePlan is returned when the database changed between planning and
+	// executing a plan.
+	ErrStalePlan = errors.New("migration plan is out of date")
+)
+
+// Migration is a single versioned schema change. DownSQL is empty for
+// migrations that cannot be rolled back. Checksum covers the up SQL only.
+type Migration struct {
+	Version  int64
+	Name     string
+	SQL      string
+	DownSQL  string
+	Checksum string
+}
+
+// Direction is the way a plan moves the schema.
+type Direction string
+
+const (
+	DirectionUp   Direction = "up"
+	DirectionDown Direction = "down"
+)
+
+// Plan is an ordered list of migrations to apply or roll back.
+type Plan struct {
+	Direction  Direction
+	Migrations []Migration
+}
+
+// State describes how a migration relates to the database.
+type State string
+
+const (
+	StateApplied  State = "applied"
+	StatePending  State = "pending"
+	StateModified State = "modified"
+	StateMissing  State = "missing"
+)
+
+// MigrationStatus reports the state of one migration. AppliedAt is zero for
+// pending migrations; Missing migrations were applied but no longer exist as
+// files.
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	State     State
+	AppliedAt time.Time
+}
+
+// Load reads paired NNN_name.up.sql / NNN_name.down.sql files from the root
+// of fsys, ordered by version. Every version needs an up file; the down file
+// is optional.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("read migrations: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("migration %s: name must match NNN_name.up.sql or NNN_name.down.sql", entry.Name())
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: invalid version: %w", entry.Name(), err)
+		}
+		sqlBytes, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("read migration %s: %w", entry.Name(), err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %s: version %d already used by %s", entry.Name(), version, migration.Name)
+		}
+
+		if match[3] == "up" {
+			migration.SQL = string(sqlBytes)
+			migration.Checksum = checksum(sqlBytes)
+		} else {
+			migration.DownSQL = string(sqlBytes)
+		}
+	}
+
+	result := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Checksum == "" {
+			return nil, fmt.Errorf("migration %03d_%s: missing up file", migration.Version, migration.Name)
+		}
+		result = append(result, *migration)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+	return result, nil
+}
+
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// Migrator applies migrations and records them in schema_migrations.
+type Migrator struct {
+	pool       *pgxpool.Pool
+	migrations []Migration
+	logger     *slog.Logger
+}
+
+// NewMigrator loads the migrations in fsys for application against pool.
+func NewMigrator(pool *pgxpool.Pool, fsys fs.FS, logger *slog.Logger) (*Migrator, error) {
+	loaded, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Migrator{pool: pool, migrations: loaded, logger: logger}, nil
+}
+
+type appliedMigration struct {
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+// Up applies every pending migration, each in its own transaction, while
+// holding a session advisory lock. It refuses to run if an applied migration
+// has been modified.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var ran []Migration
+	err := m.withLock(ctx, func(conn *pgxpool.Conn, applied map[int64]appliedMigration) error {
+		plan := planUp(m.migrations, applied, -1)
+		var err error
+		ran, err = m.execute(ctx, conn, plan)
+		return err
+	})
+	return ran, err
+}
+
+// PlanDown returns the plan that rolls back the steps most recently applied
+// migrations.
+func (m *Migrator) PlanDown(ctx context.Context, steps int) (Plan, error) {
+	if steps < 1 {
+		return Plan{}, fmt.Errorf("steps must be >= 1")
+	}
+	applied, err := m.readAppliedIfExists(ctx)
+	if err != nil {
+		return Plan{}, err
+	}
+	return planDown(m.migrations, applied, steps)
+}
+
+// PlanTo returns the plan that moves the schema to version, applying or
+// rolling back migrations as required. Version 0 rolls back everything.
+func (m *Migrator) PlanTo(ctx context.Context, version int64) (Plan, error) {
+	if version < 0 {
+		return Plan{}, fmt.Errorf("version must be >= 0")
+	}
+	applied, err := m.readAppliedIfExists(ctx)
+	if err != nil {
+		return Plan{}, err
+	}
+	return planTo(m.migrations, applied, version)
+}
+
+// Execute runs a plan produced by PlanDown or PlanTo. The plan is checked
+// against the database again once the lock is held so a concurrent change
+// cannot make it apply twice.
+func (m *Migrator) Execute(ctx context.Context, plan Plan) ([]Migration, error) {
+	var ran []Migration
+	err := m.withLock(ctx, func(conn *pgxpool.Conn, applied map[int64]appliedMigration) error {
+		for _, migration := range plan.Migrations {
+			_, isApplied := applied[migration.Version]
+			if isApplied != (plan.Direction == DirectionDown) {
+				return fmt.Errorf("%w: %03d_%s", ErrStalePlan, migration.Version, migration.Name)
+			}
+		}
+		var err error
+		ran, err = m.execute(ctx, conn, plan)
+		return err
+	})
+	return ran, err
+}
+
+// Status compares the loaded migrations with those recorded in the database.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := m.readAppliedIfExists(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return buildStatus(m.migrations, applied), nil
+}
+
+func (m *Migrator) withLock(ctx context.Context, fn func(*pgxpool.Conn, map[int64]appliedMigration) error) error {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock($1)", advisoryLockKey); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer func() {
+		if _, err := conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1)", advisoryLockKey); err != nil {
+			m.logger.Error("failed to release migration lock", "error", err)
+		}
+	}()
+
+	if _, err := conn.Exec(ctx, createSchemaMigrationsTable); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	// Read the applied set only once the lock is held so that migrations
+	// applied by a concurrent runner are seen.
+	applied, err := readApplied(ctx, conn)
+	if err != nil {
+		return err
+	}
+	if err := verifyChecksums(m.migrations, applied); err != nil {
+		return err
+	}
+	return fn(conn, applied)
+}
+
+func (m *Migrator) execute(ctx context.Context, conn *pgxpool.Conn, plan Plan) ([]Migration, error) {
+	var ran []Migration
+	for _, migration := range plan.Migrations {
+		start := time.Now()
+		tx, err := conn.Begin(ctx)
+		if err != nil {
+			return ran, fmt.Errorf("begin migration %d: %w", migration.Version, err)
+		}
+
+		sql := migration.SQL
+		record := "INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)"
+		recordArgs := []any{migration.Version, migration.Name, migration.Checksum}
+		if plan.Direction == DirectionDown {
+			sql = migration.DownSQL
+			record = "DELETE FROM schema_migrations WHERE version = $1"
+			recordArgs = recordArgs[:1]
+		}
+
+		if _, err := tx.Exec(ctx, sql); err != nil {
+			_ = tx.Rollback(ctx)
+			return ran, fmt.Errorf("exec migration %d_%s %s: %w", migration.Version, migration.Name, plan.Direction, err)
+		}
+		if _, err := tx.Exec(ctx, record, recordArgs...); err != nil {
+			_ = tx.Rollback(ctx)
+			return ran, fmt.Errorf("record migration %d: %w", migration.Version, err)
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return ran, fmt.Errorf("commit migration %d: %w", migration.Version, err)
+		}
+
+		m.logger.Info("migration finished",
+			"version", migration.Version,
+			"name", migration.Name,
+			"direction", plan.Direction,
+			"duration", time.Since(start).String(),
+		)
+		ran = append(ran, migration)
+	}
+	return ran, nil
+}
+
+func (m *Migrator) readAppliedIfExists(ctx context.Context) (map[int64]appliedMigration, error) {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	var exists bool
+	if err := conn.QueryRow(ctx, "SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&exists); err != nil {
+		return nil, fmt.Errorf("check schema_migrations: %w", err)
+	}
+	if !exists {
+		return map[int64]appliedMigration{}, nil
+	}
+	return readApplied(ctx, conn)
+}
+
+// planUp returns the pending migrations up to and including target, or all
+// pending migrations when target is negative.
+func planUp(migrations []Migration, applied map[int64]appliedMigration, target int64) Plan {
+	plan := Plan{Direction: DirectionUp}
+	for _, migration := range migrations {
+		if target >= 0 && migration.Version > target {
+			break
+		}
+		if _, ok := applied[migration.Version]; !ok {
+			plan.Migrations = append(plan.Migrations, migration)
+		}
+	}
+	return plan
+}
+
+func planDown(migrations []Migration, applied map[int64]appliedMigration, steps int) (Plan, error) {
+	plan := Plan{Direction: DirectionDown}
+	for i := len(migrations) - 1; i >= 0 && len(plan.Migrations) < steps; i-- {
+		if _, ok := applied[migrations[i].Version]; ok {
+			plan.Migrations = append(plan.Migrations, migrations[i])
+		}
+	}
+	return plan, checkReversible(plan)
+}
+
+func planTo(migrations []Migration, applied map[int64]appliedMigration, target int64) (Plan, error) {
+	if target > 0 && !hasVersion(migrations, target) {
+		return Plan{}, fmt.Errorf("unknown migration version %d", target)
+	}
+
+	var current int64
+	for version := range applied {
+		current = max(current, version)
+	}
+	if target >= current {
+		return planUp(migrations, applied, target), nil
+	}
+
+	plan := Plan{Direction: DirectionDown}
+	for i := len(migrations) - 1; i >= 0 && migrations[i].Version > target; i-- {
+		if _, ok := applied[migrations[i].Version]; ok {
+			plan.Migrations = append(plan.Migrations, migrations[i])
+		}
+	}
+	return plan, checkReversible(plan)
+}
+
+func hasVersion(migrations []Migration, version int64) bool {
+	for _, migration := range migrations {
+		if migration.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+func checkReversible(plan Plan) error {
+	for _, migration := range plan.Migrations {
+		if migration.DownSQL == "" {
+			return fmt.Errorf("%w: %03d_%s", ErrIrreversible, migration.Version, migration.Name)
+		}
+	}
+	return nil
+}
+
+func buildStatus(migrations []Migration, applied map[int64]appliedMigration) []MigrationStatus {
+	result := make([]MigrationStatus, 0, len(migrations))
+	known := make(map[int64]bool, len(migrations))
+	for _, migration := range migrations {
+		known[migration.Version] = true
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name, State: StatePending}
+		if record, ok := applied[migration.Version]; ok {
+			status.AppliedAt = record.appliedAt
+			status.State = StateApplied
+			if record.checksum != migration.Checksum {
+				status.State = StateModified
+			}
+		}
+		result = append(result, status)
+	}
+
+	for version, record := range applied {
+		if known[version] {
+			continue
+		}
+		result = append(result, MigrationStatus{
+			Version:   version,
+			Name:      record.name,
+			State:     StateMissing,
+			AppliedAt: record.appliedAt,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+	return result
+}
+
+func verifyChecksums(migrations []Migration, applied map[int64]appliedMigration) error {
+	for _, migration := range migrations {
+		record, ok := applied[migration.Version]
+		if ok && record.checksum != migration.Checksum {
+			return fmt.Errorf("%w: %d_%s", ErrChecksumMismatch, migration.Version, migration.Name)
+		}
+	}
+	return nil
+}
+
+func readApplied(ctx context.Context, conn *pgxpool.Conn) (map[int64]appliedMigration, error) {
+	rows, err := conn.Query(ctx, "SELECT version, name, checksum, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("read schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]appliedMigration)
+	for rows.Next() {
+		var (
+			version int64
+			record  appliedMigration
+		)
+		if err := rows.Scan(&version, &record.name, &record.checksum, &record.appliedAt); err != nil {
+			return nil, fmt.Errorf("scan schema_migrations: %w", err)
+		}
+		applied[version] = record
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return applied, nil
+}
@@ -0,0 +1,123 @@
+package migrations
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_OrdersByVersion(t *testing.T) {
+	fsys := fstest.MapFS{
+		"010_later.up.sql":   {Data: []byte("SELECT 10;")},
+		"002_second.up.sql":  {Data: []byte("SELECT 2;")},
+		"001_first.up.sql":   {Data: []byte("SELECT 1;")},
+		"001_first.down.sql": {Data: []byte("SELECT -1;")},
+		"README.md":          {Data: []byte("ignored")},
+	}
+
+	loaded, err := Load(fsys)
+	require.NoError(t, err)
+	require.Len(t, loaded, 3)
+	require.Equal(t, []int64{1, 2, 10}, []int64{loaded[0].Version, loaded[1].Version, loaded[2].Version})
+	require.Equal(t, "first", loaded[0].Name)
+	require.Equal(t, "SELECT 1;", loaded[0].SQL)
+	require.Equal(t, "SELECT -1;", loaded[0].DownSQL)
+	require.Empty(t, loaded[1].DownSQL)
+	require.Len(t, loaded[0].Checksum, 64)
+}
+
+func TestLoad_RejectsInvalidFiles(t *testing.T) {
+	_, err := Load(fstest.MapFS{
+		"001_first.up.sql":   {Data: []byte("SELECT 1;")},
+		"1_duplicate.up.sql": {Data: []byte("SELECT 1;")},
+	})
+	require.ErrorContains(t, err, "already used")
+
+	_, err = Load(fstest.MapFS{"001_init.sql": {Data: []byte("SELECT 1;")}})
+	require.ErrorContains(t, err, "must match")
+
+	_, err = Load(fstest.MapFS{"001_init.down.sql": {Data: []byte("SELECT 1;")}})
+	require.ErrorContains(t, err, "missing up file")
+}
+
+func TestLoad_EmbeddedFiles(t *testing.T) {
+	loaded, err := Load(Files)
+	require.NoError(t, err)
+	require.NotEmpty(t, loaded)
+	require.Equal(t, int64(1), loaded[0].Version)
+	for _, migration := range loaded {
+		require.NotEmpty(t, migration.DownSQL, "migration %d has no down file", migration.Version)
+	}
+}
+
+func TestPlans(t *testing.T) {
+	loaded := []Migration{
+		{Version: 1, Name: "init", DownSQL: "DROP 1"},
+		{Version: 2, Name: "index", DownSQL: "DROP 2"},
+		{Version: 3, Name: "search", DownSQL: "DROP 3"},
+		{Version: 4, Name: "pending", DownSQL: "DROP 4"},
+	}
+	applied := map[int64]appliedMigration{1: {}, 2: {}, 3: {}}
+	versions := func(plan Plan) []int64 {
+		var result []int64
+		for _, migration := range plan.Migrations {
+			result = append(result, migration.Version)
+		}
+		return result
+	}
+
+	plan, err := planDown(loaded, applied, 2)
+	require.NoError(t, err)
+	require.Equal(t, DirectionDown, plan.Direction)
+	require.Equal(t, []int64{3, 2}, versions(plan))
+
+	plan, err = planTo(loaded, applied, 1)
+	require.NoError(t, err)
+	require.Equal(t, DirectionDown, plan.Direction)
+	require.Equal(t, []int64{3, 2}, versions(plan))
+
+	plan, err = planTo(loaded, applied, 0)
+	require.NoError(t, err)
+	require.Equal(t, []int64{3, 2, 1}, versions(plan))
+
+	plan, err = planTo(loaded, applied, 4)
+	require.NoError(t, err)
+	require.Equal(t, DirectionUp, plan.Direction)
+	require.Equal(t, []int64{4}, versions(plan))
+
+	_, err = planTo(loaded, applied, 7)
+	require.ErrorContains(t, err, "unknown migration version")
+
+	loaded[1].DownSQL = ""
+	_, err = planDown(loaded, applied, 2)
+	require.True(t, errors.Is(err, ErrIrreversible))
+}
+
+func TestBuildStatus(t *testing.T) {
+	appliedAt := time.Date(2025, 11, 10, 12, 0, 0, 0, time.UTC)
+	loaded := []Migration{
+		{Version: 1, Name: "init", Checksum: "a"},
+		{Version: 2, Name: "edited", Checksum: "b"},
+		{Version: 3, Name: "new", Checksum: "c"},
+	}
+	applied := map[int64]appliedMigration{
+		1: {name: "init", checksum: "a", appliedAt: appliedAt},
+		2: {name: "edited", checksum: "old", appliedAt: appliedAt},
+		9: {name: "removed", checksum: "z", appliedAt: appliedAt},
+	}
+
+	statuses := buildStatus(loaded, applied)
+	require.Equal(t, []MigrationStatus{
+		{Version: 1, Name: "init", State: StateApplied, AppliedAt: appliedAt},
+		{Version: 2, Name: "edited", State: StateModified, AppliedAt: appliedAt},
+		{Version: 3, Name: "new", State: StatePending},
+		{Version: 9, Name: "removed", State: StateMissing, AppliedAt: appliedAt},
+	}, statuses)
+
+	err := verifyChecksums(loaded, applied)
+	require.True(t, errors.Is(err, ErrChecksumMismatch))
+	require.ErrorContains(t, err, "2_edited")
+}
@@ -0,0 +1,105 @@
+package repo
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/example/bookapi/internal/domain"
+)
+
+// OutboxRepository stores domain events until the relay has delivered them.
+type OutboxRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewOutboxRepository(pool *pgxpool.Pool) *OutboxRepository {
+	return &OutboxRepository{pool: pool}
+}
+
+// Enqueue stores event; call it inside TxManager.WithinTx so the event is
+// committed atomically with the change it describes.
+func (r *OutboxRepository) Enqueue(ctx context.Context, event domain.OutboxEvent) error {
+	const query = `
+		INSERT INTO outbox (id, event_type, aggregate_id, payload, created_at, next_attempt_at, actor, tenant_id)
+		VALUES ($1, $2, $3, $4, $5, $5, $6, $7)
+	`
+	_, err := dbFrom(ctx, r.pool).Exec(ctx, query,
+		event.ID,
+		event.Type,
+		event.AggregateID,
+		event.Payload,
+		event.CreatedAt,
+		event.Actor,
+		event.Tenant,
+	)
+	return err
+}
+
+// Claim leases up to limit due events, oldest first, by pushing their next
+// attempt to now+lease. Rows locked by another relay are skipped, and an
+// event whose relay dies before acknowledging it becomes due again once the
+// lease expires.
+func (r *OutboxRepository) Claim(ctx context.Context, limit int, now time.Time, lease time.Duration) ([]domain.OutboxEvent, error) {
+	const query = `
+		UPDATE outbox
+		SET next_attempt_at = $3
+		WHERE id IN (
+			SELECT id FROM outbox
+			WHERE next_attempt_at <= $2
+			ORDER BY created_at, id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, event_type, aggregate_id, payload, created_at, actor, tenant_id, attempts
+	`
+	rows, err := dbFrom(ctx, r.pool).Query(ctx, query, limit, now, now.Add(lease))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []domain.OutboxEvent
+	for rows.Next() {
+		var event domain.OutboxEvent
+		if err := rows.Scan(&event.ID, &event.Type, &event.AggregateID, &event.Payload, &event.CreatedAt, &event.Actor, &event.Tenant, &event.Attempts); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// UPDATE ... RETURNING does not preserve the subquery order.
+	slices.SortFunc(events, func(a, b domain.OutboxEvent) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return bytes.Compare(a.ID[:], b.ID[:])
+	})
+	return events, nil
+}
+
+// MarkDelivered removes a delivered event.
+func (r *OutboxRepository) MarkDelivered(ctx context.Context, id uuid.UUID) error {
+	_, err := dbFrom(ctx, r.pool).Exec(ctx, `DELETE FROM outbox WHERE id = $1`, id)
+	return err
+}
+
+// MarkFailed records a failed delivery and schedules the next attempt.
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id uuid.UUID, nextAttemptAt time.Time, reason string) error {
+	const query = `
+		UPDATE outbox
+		SET attempts = attempts + 1,
+			last_error = $3,
+			next_attempt_at = $2
+		WHERE id = $1
+	`
+	_, err := dbFrom(ctx, r.pool).Exec(ctx, query, id, nextAttemptAt, reason)
+	return err
+}
@@ -0,0 +1,35 @@
+package repo
+
+import (
+	"slices"
+
+	"github.com/example/bookapi/internal/domain"
+)
+
+// buildPage turns up to query.Limit+1 rows, fetched in cursor direction,
+// into a page in query order with cursors to the neighbouring pages.
+func buildPage(rows []domain.Book, query domain.BookQuery) domain.BookPage {
+	hasMore := len(rows) > query.Limit
+	if hasMore {
+		rows = rows[:query.Limit]
+	}
+
+	backward := query.Cursor != nil && query.Cursor.Backward
+	if backward {
+		slices.Reverse(rows)
+	}
+
+	page := domain.BookPage{Books: rows}
+	if len(rows) == 0 {
+		return page
+	}
+
+	first, last := rows[0], rows[len(rows)-1]
+	if (backward && hasMore) || (!backward && query.Cursor != nil) {
+		page.PrevCursor = domain.NewCursor(first, query.Sort, true).Encode()
+	}
+	if (!backward && hasMore) || backward {
+		page.NextCursor = domain.NewCursor(last, query.Sort, false).Encode()
+	}
+	return page
+}
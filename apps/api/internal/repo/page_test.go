@@ -0,0 +1,51 @@
+package repo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/bookapi/internal/domain"
+)
+
+func TestBuildPage(t *testing.T) {
+	base := time.Date(2025, 11, 10, 12, 0, 0, 0, time.UTC)
+	books := make([]domain.Book, 5)
+	for i := range books {
+		books[i] = domain.Book{ID: uuid.New(), CreatedAt: base.Add(time.Duration(i) * time.Minute)}
+	}
+
+	t.Run("first page with more rows", func(t *testing.T) {
+		page := buildPage(books[:3], domain.BookQuery{Sort: domain.DefaultBookSort, Limit: 2})
+		require.Equal(t, books[:2], page.Books)
+		require.Empty(t, page.PrevCursor)
+
+		next, err := domain.DecodeCursor(page.NextCursor)
+		require.NoError(t, err)
+		require.Equal(t, books[1].ID, next.ID)
+		require.False(t, next.Backward)
+	})
+
+	t.Run("last page after cursor", func(t *testing.T) {
+		cursor := domain.NewCursor(books[2], domain.DefaultBookSort, false)
+		page := buildPage(books[3:], domain.BookQuery{Sort: domain.DefaultBookSort, Limit: 2, Cursor: &cursor})
+		require.Equal(t, books[3:], page.Books)
+		require.Empty(t, page.NextCursor)
+
+		prev, err := domain.DecodeCursor(page.PrevCursor)
+		require.NoError(t, err)
+		require.Equal(t, books[3].ID, prev.ID)
+		require.True(t, prev.Backward)
+	})
+
+	t.Run("backward page is returned in ascending order", func(t *testing.T) {
+		cursor := domain.NewCursor(books[3], domain.DefaultBookSort, true)
+		rows := []domain.Book{books[2], books[1], books[0]}
+		page := buildPage(rows, domain.BookQuery{Sort: domain.DefaultBookSort, Limit: 2, Cursor: &cursor})
+		require.Equal(t, []domain.Book{books[1], books[2]}, page.Books)
+		require.NotEmpty(t, page.PrevCursor)
+		require.NotEmpty(t, page.NextCursor)
+	})
+}
@@ -2,17 +2,40 @@ package repo
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/example/bookapi/internal/domain"
 )
 
-var ErrNotFound = errors.New("book not found")
+// bookColumns is the select list read by scanBook, for queries on books
+// without a table alias. The credited authors come last, as a JSON array in
+// credit order, followed by the categories as a JSON array and the tag names
+// as a text array, both ordered by name, and the explicit prices as a JSON
+// array ordered by currency.
+const bookColumns = `id, title, author, price, currency, stock, created_at, updated_at, version, deleted_at, isbn,
+			(SELECT coalesce(json_agg(json_build_object('authorId', a.id, 'name', a.name, 'role', ba.role) ORDER BY ba.position), '[]')
+				FROM book_authors ba JOIN authors a ON a.id = ba.author_id
+				WHERE ba.book_id = books.id) AS authors,
+			(SELECT coalesce(json_agg(json_build_object('categoryId', c.id, 'name', c.name) ORDER BY lower(c.name), c.id), '[]')
+				FROM book_categories bc JOIN categories c ON c.id = bc.category_id
+				WHERE bc.book_id = books.id) AS categories,
+			(SELECT coalesce(array_agg(t.name ORDER BY t.name), '{}')
+				FROM book_tags bt JOIN tags t ON t.id = bt.tag_id
+				WHERE bt.book_id = books.id) AS tags,
+			(SELECT coalesce(json_agg(json_build_object('amount', bp.price::text, 'currency', bp.currency) ORDER BY bp.currency), '[]')
+				FROM book_prices bp
+				WHERE bp.book_id = books.id) AS prices`
 
 type BookRepository struct {
 	pool *pgxpool.Pool
@@ -22,51 +45,274 @@ func NewBookRepository(pool *pgxpool.Pool) *BookRepository {
 	return &BookRepository{pool: pool}
 }
 
+func (r *BookRepository) db(ctx context.Context) querier {
+	return dbFrom(ctx, r.pool)
+}
+
+// Create stores the book and its author, category and tag links in one
+// transaction.
 func (r *BookRepository) Create(ctx context.Context, book domain.Book) error {
 	const query = `
-		INSERT INTO books (id, title, author, price, currency, stock, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO books (id, title, author, price, currency, stock, created_at, updated_at, version, isbn)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 	`
-	_, err := r.pool.Exec(ctx, query,
-		book.ID,
-		book.Title,
-		book.Author,
-		book.Price,
-		book.Currency,
-		book.Stock,
-		book.CreatedAt,
-		book.UpdatedAt,
+	return NewTxManager(r.pool).WithinTx(ctx, func(ctx context.Context) error {
+		_, err := r.db(ctx).Exec(ctx, query,
+			book.ID,
+			book.Title,
+			book.Author,
+			priceNumeric(book.Price),
+			book.Price.Currency,
+			book.Stock,
+			book.CreatedAt,
+			book.UpdatedAt,
+			book.Version,
+			nullableISBN(book.ISBN),
+		)
+		if err != nil {
+			return translateWriteError(err)
+		}
+		if err := r.insertAuthors(ctx, book); err != nil {
+			return err
+		}
+		if err := r.insertTaxonomy(ctx, []domain.Book{book}); err != nil {
+			return err
+		}
+		return r.insertPrices(ctx, []domain.Book{book})
+	})
+}
+
+// CreateMany copies books, their author links and their explicit prices in
+// with one COPY each, then inserts their category and tag links. Either every
+// book is stored or, on error, none is.
+func (r *BookRepository) CreateMany(ctx context.Context, books []domain.Book) error {
+	return NewTxManager(r.pool).WithinTx(ctx, func(ctx context.Context) error {
+		if err := r.copyBooks(ctx, books); err != nil {
+			return err
+		}
+		if err := r.copyAuthors(ctx, books); err != nil {
+			return err
+		}
+		if err := r.insertTaxonomy(ctx, books); err != nil {
+			return err
+		}
+		return r.copyPrices(ctx, books)
+	})
+}
+
+func (r *BookRepository) copyBooks(ctx context.Context, books []domain.Book) error {
+	columns := []stagedColumn{
+		{"id", "uuid"}, {"title", "text"}, {"author", "text"}, {"price", "numeric"}, {"currency", "text"},
+		{"stock", "int"}, {"created_at", "timestamptz"}, {"updated_at", "timestamptz"}, {"version", "bigint"}, {"isbn", "text"},
+	}
+	err := copyStaged(ctx, r.db(ctx), "books", columns,
+		pgx.CopyFromSlice(len(books), func(i int) ([]any, error) {
+			book := books[i]
+			return []any{
+				book.ID,
+				book.Title,
+				book.Author,
+				priceNumeric(book.Price),
+				book.Price.Currency,
+				book.Stock,
+				book.CreatedAt,
+				book.UpdatedAt,
+				book.Version,
+				nullableISBN(book.ISBN),
+			}, nil
+		}),
 	)
+	return translateWriteError(err)
+}
+
+// copyAuthors links books to their authors in credit order.
+func (r *BookRepository) copyAuthors(ctx context.Context, books []domain.Book) error {
+	var links [][]any
+	for _, book := range books {
+		for position, author := range book.Authors {
+			links = append(links, []any{book.ID, author.AuthorID, string(author.Role), position})
+		}
+	}
+	if len(links) == 0 {
+		return nil
+	}
+	columns := []stagedColumn{{"book_id", "uuid"}, {"author_id", "uuid"}, {"role", "text"}, {"position", "int"}}
+	err := copyStaged(ctx, r.db(ctx), "book_authors", columns, pgx.CopyFromRows(links))
+	return translateWriteError(err)
+}
+
+// copyPrices stores the explicit prices of books.
+func (r *BookRepository) copyPrices(ctx context.Context, books []domain.Book) error {
+	var prices [][]any
+	for _, book := range books {
+		for _, price := range book.Prices {
+			prices = append(prices, []any{book.ID, price.Currency, priceNumeric(price)})
+		}
+	}
+	if len(prices) == 0 {
+		return nil
+	}
+	columns := []stagedColumn{{"book_id", "uuid"}, {"currency", "text"}, {"price", "numeric"}}
+	err := copyStaged(ctx, r.db(ctx), "book_prices", columns, pgx.CopyFromRows(prices))
+	return translateWriteError(err)
+}
+
+// stagedColumn is a column of a table loaded by copyStaged, with the type it
+// is staged as.
+type stagedColumn struct {
+	name, typ string
+}
+
+// copyStaged bulk-loads rows into table with COPY. Postgres refuses COPY FROM
+// into tables with row-level security, so the rows are copied into a
+// temporary table without policies and moved into table with INSERT ...
+// SELECT, which the policies check and which fills in the tenant_id of the
+// connection. It must run in a transaction, at whose end the temporary table
+// is dropped at the latest.
+func copyStaged(ctx context.Context, db querier, table string, columns []stagedColumn, rows pgx.CopyFromSource) error {
+	staging := "staged_" + table
+	names := make([]string, len(columns))
+	definitions := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = column.name
+		definitions[i] = column.name + " " + column.typ
+	}
+	list := strings.Join(names, ", ")
+
+	create := `CREATE TEMPORARY TABLE ` + staging + ` (` + strings.Join(definitions, ", ") + `) ON COMMIT DROP`
+	if _, err := db.Exec(ctx, create); err != nil {
+		return fmt.Errorf("stage %s: %w", table, err)
+	}
+	if _, err := db.CopyFrom(ctx, pgx.Identifier{staging}, names, rows); err != nil {
+		return fmt.Errorf("copy %s: %w", table, err)
+	}
+	if _, err := db.Exec(ctx, `INSERT INTO `+table+` (`+list+`) SELECT `+list+` FROM `+staging); err != nil {
+		return err
+	}
+	_, err := db.Exec(ctx, `DROP TABLE `+staging)
 	return err
 }
 
+// insertAuthors links book to its authors in credit order.
+func (r *BookRepository) insertAuthors(ctx context.Context, book domain.Book) error {
+	if len(book.Authors) == 0 {
+		return nil
+	}
+	const query = `
+		INSERT INTO book_authors (book_id, author_id, role, position)
+		SELECT $1, a.author_id, a.role, a.position - 1
+		FROM unnest($2::uuid[], $3::text[]) WITH ORDINALITY AS a(author_id, role, position)
+	`
+	ids := make([]string, 0, len(book.Authors))
+	roles := make([]string, 0, len(book.Authors))
+	for _, author := range book.Authors {
+		ids = append(ids, author.AuthorID.String())
+		roles = append(roles, string(author.Role))
+	}
+	_, err := r.db(ctx).Exec(ctx, query, book.ID, ids, roles)
+	return translateWriteError(err)
+}
+
+// insertTaxonomy links books to their categories and, by name, to their
+// tags. Every tag must already exist.
+func (r *BookRepository) insertTaxonomy(ctx context.Context, books []domain.Book) error {
+	var (
+		categoryBooks, categories []string
+		tagBooks, tags            []string
+	)
+	for _, book := range books {
+		for _, category := range book.Categories {
+			categoryBooks = append(categoryBooks, book.ID.String())
+			categories = append(categories, category.CategoryID.String())
+		}
+		for _, tag := range book.Tags {
+			tagBooks = append(tagBooks, book.ID.String())
+			tags = append(tags, tag)
+		}
+	}
+
+	if len(categories) > 0 {
+		const query = `
+			INSERT INTO book_categories (book_id, category_id)
+			SELECT * FROM unnest($1::uuid[], $2::uuid[])
+		`
+		if _, err := r.db(ctx).Exec(ctx, query, categoryBooks, categories); err != nil {
+			return translateWriteError(err)
+		}
+	}
+	if len(tags) > 0 {
+		const query = `
+			INSERT INTO book_tags (book_id, tag_id)
+			SELECT l.book_id, t.id
+			FROM unnest($1::uuid[], $2::text[]) AS l(book_id, name)
+			JOIN tags t ON t.name = l.name
+		`
+		tag, err := r.db(ctx).Exec(ctx, query, tagBooks, tags)
+		if err != nil {
+			return translateWriteError(err)
+		}
+		if tag.RowsAffected() != int64(len(tags)) {
+			return domain.ErrTagNotFound
+		}
+	}
+	return nil
+}
+
+// insertPrices stores the explicit prices of books.
+func (r *BookRepository) insertPrices(ctx context.Context, books []domain.Book) error {
+	const query = `
+		INSERT INTO book_prices (book_id, currency, price)
+		SELECT * FROM unnest($1::uuid[], $2::text[], $3::numeric[])
+	`
+	var (
+		bookIDs, currencies []string
+		prices              []pgtype.Numeric
+	)
+	for _, book := range books {
+		for _, price := range book.Prices {
+			bookIDs = append(bookIDs, book.ID.String())
+			currencies = append(currencies, price.Currency)
+			prices = append(prices, priceNumeric(price))
+		}
+	}
+	if len(bookIDs) == 0 {
+		return nil
+	}
+	_, err := r.db(ctx).Exec(ctx, query, bookIDs, currencies, prices)
+	return translateWriteError(err)
+}
+
 func (r *BookRepository) Get(ctx context.Context, id uuid.UUID) (domain.Book, error) {
 	const query = `
-		SELECT id, title, author, price, currency, stock, created_at, updated_at
+		SELECT ` + bookColumns + `
 		FROM books
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
-	row := r.pool.QueryRow(ctx, query, id)
+	row := r.db(ctx).QueryRow(ctx, query, id)
 
 	book, err := scanBook(row)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return domain.Book{}, ErrNotFound
+			return domain.Book{}, domain.ErrBookNotFound
 		}
 		return domain.Book{}, err
 	}
 	return book, nil
 }
 
-func (r *BookRepository) List(ctx context.Context) ([]domain.Book, error) {
+// GetByTitleAuthor returns the live book with the given title and author,
+// compared case-insensitively.
+func (r *BookRepository) GetByTitleAuthor(ctx context.Context, title, author string) (domain.Book, error) {
 	const query = `
-		SELECT id, title, author, price, currency, stock, created_at, updated_at
+		SELECT ` + bookColumns + `
 		FROM books
-		ORDER BY created_at ASC
+		WHERE lower(title) = lower($1) AND lower(author) = lower($2) AND deleted_at IS NULL
+		ORDER BY created_at, id
+		LIMIT 2
 	`
-	rows, err := r.pool.Query(ctx, query)
+	rows, err := r.db(ctx).Query(ctx, query, title, author)
 	if err != nil {
-		return nil, err
+		return domain.Book{}, err
 	}
 	defer rows.Close()
 
@@ -74,16 +320,159 @@ func (r *BookRepository) List(ctx context.Context) ([]domain.Book, error) {
 	for rows.Next() {
 		book, err := scanBook(rows)
 		if err != nil {
+			return domain.Book{}, err
+		}
+		books = append(books, book)
+	}
+	if rows.Err() != nil {
+		return domain.Book{}, rows.Err()
+	}
+	switch len(books) {
+	case 0:
+		return domain.Book{}, domain.ErrBookNotFound
+	case 1:
+		return books[0], nil
+	default:
+		return domain.Book{}, domain.ErrAmbiguousBook
+	}
+}
+
+// GetByISBN returns the live book with the given normalized ISBN-13.
+func (r *BookRepository) GetByISBN(ctx context.Context, isbn string) (domain.Book, error) {
+	const query = `
+		SELECT ` + bookColumns + `
+		FROM books
+		WHERE isbn = $1 AND deleted_at IS NULL
+	`
+	book, err := scanBook(r.db(ctx).QueryRow(ctx, query, isbn))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Book{}, domain.ErrBookNotFound
+		}
+		return domain.Book{}, err
+	}
+	return book, nil
+}
+
+// TakenISBNs returns those of isbns that a book already has. Soft-deleted
+// books count, as they keep their ISBN.
+func (r *BookRepository) TakenISBNs(ctx context.Context, isbns []string) ([]string, error) {
+	const query = `SELECT isbn FROM books WHERE isbn = ANY($1)`
+	rows, err := r.db(ctx).Query(ctx, query, isbns)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var taken []string
+	for rows.Next() {
+		var isbn string
+		if err := rows.Scan(&isbn); err != nil {
 			return nil, err
 		}
+		taken = append(taken, isbn)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return taken, nil
+}
+
+func (r *BookRepository) List(ctx context.Context, query domain.BookQuery) (domain.BookPage, error) {
+	sql, args := buildListQuery(query)
+	rows, err := r.db(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return domain.BookPage{}, err
+	}
+	defer rows.Close()
+
+	var books []domain.Book
+	for rows.Next() {
+		book, err := scanBook(rows)
+		if err != nil {
+			return domain.BookPage{}, err
+		}
 		books = append(books, book)
 	}
+	if rows.Err() != nil {
+		return domain.BookPage{}, rows.Err()
+	}
+	return buildPage(books, query), nil
+}
+
+// Export calls fn for every book matching query, in query order, as rows
+// arrive from the database. Rows are never collected in memory, so exports
+// of any size run in constant memory. query.Limit and query.Cursor must be
+// unset. An error from fn stops the export and is returned.
+func (r *BookRepository) Export(ctx context.Context, query domain.BookQuery, fn func(domain.Book) error) error {
+	sql, args := buildListQuery(query)
+	rows, err := r.db(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		book, err := scanBook(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(book); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (r *BookRepository) Search(ctx context.Context, search domain.BookSearchQuery) ([]domain.BookSearchResult, error) {
+	// ts_headline is comparatively expensive, so it only runs over the
+	// ranked page rather than every matching row. Its <mark> tags are the
+	// only markup of the snippet, so the text is HTML-escaped first.
+	const query = `
+		WITH q AS (SELECT websearch_to_tsquery('english', $1) AS query)
+		SELECT m.id, m.title, m.author, m.price, m.currency, m.stock, m.created_at, m.updated_at, m.version, m.deleted_at, m.isbn,
+			m.authors, m.categories, m.tags, m.prices, m.rank,
+			ts_headline('english',
+				replace(replace(replace(m.title || ' by ' || m.author, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), q.query,
+				'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS snippet
+		FROM (
+			SELECT ` + bookColumns + `,
+				ts_rank(search_vector, q.query) AS rank
+			FROM books, q
+			WHERE search_vector @@ q.query AND deleted_at IS NULL
+			ORDER BY rank DESC, created_at ASC, id ASC
+			LIMIT $2 OFFSET $3
+		) AS m, q
+		ORDER BY m.rank DESC, m.created_at ASC, m.id ASC
+	`
+	rows, err := r.db(ctx).Query(ctx, query, search.Query, search.Limit, search.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []domain.BookSearchResult{}
+	for rows.Next() {
+		var result domain.BookSearchResult
+		var row bookRow
+		err := rows.Scan(append(row.targets(), &result.Rank, &result.Snippet)...)
+		if err != nil {
+			return nil, fmt.Errorf("scan search result: %w", err)
+		}
+		if result.Book, err = row.toBook(); err != nil {
+			return nil, fmt.Errorf("scan search result: %w", err)
+		}
+		results = append(results, result)
+	}
 	if rows.Err() != nil {
 		return nil, rows.Err()
 	}
-	return books, nil
+	return results, nil
 }
 
+// Update stores book and replaces its author, category and tag links if its
+// version still matches book.Version, bumping the stored version by one. It
+// returns domain.ErrVersionConflict when another writer got there first.
 func (r *BookRepository) Update(ctx context.Context, book domain.Book) error {
 	const query = `
 		UPDATE books
@@ -92,53 +481,286 @@ func (r *BookRepository) Update(ctx context.Context, book domain.Book) error {
 			price = $4,
 			currency = $5,
 			stock = $6,
-			updated_at = $7
-		WHERE id = $1
+			updated_at = $7,
+			isbn = $9,
+			version = version + 1
+		WHERE id = $1 AND version = $8 AND deleted_at IS NULL
 	`
-	tag, err := r.pool.Exec(ctx, query,
-		book.ID,
-		book.Title,
-		book.Author,
-		book.Price,
-		book.Currency,
-		book.Stock,
-		book.UpdatedAt,
+	return NewTxManager(r.pool).WithinTx(ctx, func(ctx context.Context) error {
+		tag, err := r.db(ctx).Exec(ctx, query,
+			book.ID,
+			book.Title,
+			book.Author,
+			priceNumeric(book.Price),
+			book.Price.Currency,
+			book.Stock,
+			book.UpdatedAt,
+			book.Version,
+			nullableISBN(book.ISBN),
+		)
+		if err != nil {
+			return translateWriteError(err)
+		}
+		if tag.RowsAffected() == 0 {
+			return r.missingOrConflict(ctx, book.ID)
+		}
+		for _, table := range []string{"book_authors", "book_categories", "book_tags", "book_prices"} {
+			if _, err := r.db(ctx).Exec(ctx, `DELETE FROM `+table+` WHERE book_id = $1`, book.ID); err != nil {
+				return err
+			}
+		}
+		if err := r.insertAuthors(ctx, book); err != nil {
+			return err
+		}
+		if err := r.insertTaxonomy(ctx, []domain.Book{book}); err != nil {
+			return err
+		}
+		return r.insertPrices(ctx, []domain.Book{book})
+	})
+}
+
+// Delete soft-deletes the book by stamping deleted_at and returns it. When
+// expectedVersion is set the delete only applies to that version.
+func (r *BookRepository) Delete(ctx context.Context, id uuid.UUID, expectedVersion *int64, deletedAt time.Time) (domain.Book, error) {
+	const query = `
+		UPDATE books
+		SET deleted_at = $3,
+			updated_at = $3,
+			version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL AND ($2::bigint IS NULL OR version = $2)
+		RETURNING ` + bookColumns + `
+	`
+	book, err := scanBook(r.db(ctx).QueryRow(ctx, query, id, expectedVersion, deletedAt))
+	if err == nil {
+		return book, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return domain.Book{}, err
+	}
+	return domain.Book{}, r.missingOrConflict(ctx, id)
+}
+
+// Restore clears deleted_at on a soft-deleted book and returns the restored
+// book together with the time it had been deleted at.
+func (r *BookRepository) Restore(ctx context.Context, id uuid.UUID, restoredAt time.Time) (domain.Book, time.Time, error) {
+	const query = `
+		UPDATE books
+		SET deleted_at = NULL,
+			updated_at = $2,
+			version = books.version + 1
+		FROM (
+			SELECT id AS old_id, deleted_at AS old_deleted_at FROM books
+			WHERE id = $1 AND deleted_at IS NOT NULL
+			FOR UPDATE
+		) AS old
+		WHERE books.id = old.old_id
+		RETURNING ` + bookColumns + `,
+			old.old_deleted_at
+	`
+	var (
+		row       bookRow
+		deletedAt time.Time
 	)
-	if err != nil {
-		return err
+	err := r.db(ctx).QueryRow(ctx, query, id, restoredAt).Scan(append(row.targets(), &deletedAt)...)
+	if err == nil {
+		book, err := row.toBook()
+		if err != nil {
+			return domain.Book{}, time.Time{}, fmt.Errorf("scan book: %w", err)
+		}
+		return book, deletedAt, nil
 	}
-	if tag.RowsAffected() == 0 {
-		return ErrNotFound
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return domain.Book{}, time.Time{}, fmt.Errorf("scan book: %w", err)
+	}
+
+	if err := r.missingOrConflict(ctx, id); errors.Is(err, domain.ErrVersionConflict) {
+		return domain.Book{}, time.Time{}, domain.ErrBookNotDeleted
+	} else {
+		return domain.Book{}, time.Time{}, err
+	}
+}
+
+// AdjustStock adds delta to the stock of a live book in a single UPDATE, so
+// concurrent adjustments never overwrite each other, and returns the updated
+// book. A delta that would take stock below zero changes nothing and returns
+// domain.ErrInsufficientStock.
+func (r *BookRepository) AdjustStock(ctx context.Context, id uuid.UUID, delta int, updatedAt time.Time) (domain.Book, error) {
+	const query = `
+		UPDATE books
+		SET stock = stock + $2,
+			updated_at = $3,
+			version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL AND stock + $2 >= 0
+		RETURNING ` + bookColumns + `
+	`
+	book, err := scanBook(r.db(ctx).QueryRow(ctx, query, id, delta, updatedAt))
+	if err == nil {
+		return book, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return domain.Book{}, err
+	}
+
+	if err := r.missingOrConflict(ctx, id); errors.Is(err, domain.ErrVersionConflict) {
+		return domain.Book{}, domain.ErrInsufficientStock
+	} else {
+		return domain.Book{}, err
 	}
-	return nil
 }
 
-func (r *BookRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	const query = `DELETE FROM books WHERE id = $1`
-	tag, err := r.pool.Exec(ctx, query, id)
+// Purge permanently removes books soft-deleted before the cutoff.
+func (r *BookRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	const query = `DELETE FROM books WHERE deleted_at IS NOT NULL AND deleted_at < $1`
+	tag, err := r.db(ctx).Exec(ctx, query, deletedBefore)
 	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// missingOrConflict explains why a versioned write matched no rows: the
+// book is either gone (or soft-deleted) or was changed by another writer.
+func (r *BookRepository) missingOrConflict(ctx context.Context, id uuid.UUID) error {
+	const query = `SELECT EXISTS (SELECT 1 FROM books WHERE id = $1 AND deleted_at IS NULL)`
+	var exists bool
+	if err := r.db(ctx).QueryRow(ctx, query, id).Scan(&exists); err != nil {
 		return err
 	}
-	if tag.RowsAffected() == 0 {
-		return ErrNotFound
+	if exists {
+		return domain.ErrVersionConflict
 	}
-	return nil
+	return domain.ErrBookNotFound
 }
 
 func scanBook(row pgx.Row) (domain.Book, error) {
-	var book domain.Book
-	err := row.Scan(
-		&book.ID,
-		&book.Title,
-		&book.Author,
-		&book.Price,
-		&book.Currency,
-		&book.Stock,
-		&book.CreatedAt,
-		&book.UpdatedAt,
-	)
+	var scanned bookRow
+	if err := row.Scan(scanned.targets()...); err != nil {
+		return domain.Book{}, fmt.Errorf("scan book: %w", err)
+	}
+	book, err := scanned.toBook()
 	if err != nil {
 		return domain.Book{}, fmt.Errorf("scan book: %w", err)
 	}
 	return book, nil
 }
+
+// bookRow holds a scanned books row until the NUMERIC price can be turned
+// into minor units of its currency.
+type bookRow struct {
+	book       domain.Book
+	price      pgtype.Numeric
+	isbn       pgtype.Text
+	authors    []byte
+	categories []byte
+	tags       []string
+	prices     []byte
+}
+
+// targets returns scan destinations matching bookColumns: id, title, author,
+// price, currency, stock, created_at, updated_at, version, deleted_at, isbn,
+// authors, categories, tags, prices.
+func (r *bookRow) targets() []any {
+	return []any{
+		&r.book.ID,
+		&r.book.Title,
+		&r.book.Author,
+		&r.price,
+		&r.book.Price.Currency,
+		&r.book.Stock,
+		&r.book.CreatedAt,
+		&r.book.UpdatedAt,
+		&r.book.Version,
+		&r.book.DeletedAt,
+		&r.isbn,
+		&r.authors,
+		&r.categories,
+		&r.tags,
+		&r.prices,
+	}
+}
+
+func (r *bookRow) toBook() (domain.Book, error) {
+	amount, err := decimalFromNumeric(r.price)
+	if err != nil {
+		return domain.Book{}, err
+	}
+	book := r.book
+	book.ISBN = r.isbn.String
+	if err := json.Unmarshal(r.authors, &book.Authors); err != nil {
+		return domain.Book{}, fmt.Errorf("authors: %w", err)
+	}
+	if len(book.Authors) == 0 {
+		book.Authors = nil
+	}
+	if err := json.Unmarshal(r.categories, &book.Categories); err != nil {
+		return domain.Book{}, fmt.Errorf("categories: %w", err)
+	}
+	if len(book.Categories) == 0 {
+		book.Categories = nil
+	}
+	if len(r.tags) > 0 {
+		book.Tags = r.tags
+	}
+	if err := json.Unmarshal(r.prices, &book.Prices); err != nil {
+		return domain.Book{}, fmt.Errorf("prices: %w", err)
+	}
+	if len(book.Prices) == 0 {
+		book.Prices = nil
+	}
+	if book.Price, err = domain.NewMoney(amount, book.Price.Currency); err != nil {
+		return domain.Book{}, fmt.Errorf("price %s: %w", amount, err)
+	}
+	return book, nil
+}
+
+// nullableISBN stores books without an ISBN as NULL, which the unique index
+// ignores.
+func nullableISBN(isbn string) any {
+	if isbn == "" {
+		return nil
+	}
+	return isbn
+}
+
+// translateWriteError maps constraint violations callers can act on to
+// repository errors.
+func translateWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+	switch {
+	case pgErr.Code == "23505" && pgErr.ConstraintName == "books_isbn_key":
+		return domain.ErrDuplicateISBN
+	case pgErr.Code == "23503" && pgErr.ConstraintName == "book_authors_author_id_fkey":
+		return domain.ErrAuthorNotFound
+	case pgErr.Code == "23503" && pgErr.ConstraintName == "book_categories_category_id_fkey":
+		return domain.ErrCategoryNotFound
+	}
+	return err
+}
+
+// priceNumeric encodes money as an exact NUMERIC in major units.
+func priceNumeric(price domain.Money) pgtype.Numeric {
+	return pgtype.Numeric{
+		Int:   big.NewInt(price.Amount),
+		Exp:   -int32(domain.CurrencyExponent(price.Currency)),
+		Valid: true,
+	}
+}
+
+func decimalFromNumeric(n pgtype.Numeric) (domain.Decimal, error) {
+	if !n.Valid || n.NaN || n.InfinityModifier != pgtype.Finite {
+		return domain.Decimal{}, fmt.Errorf("price is not a finite number")
+	}
+	unscaled := new(big.Int).Set(n.Int)
+	scale := -int(n.Exp)
+	if scale < 0 {
+		unscaled.Mul(unscaled, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-scale)), nil))
+		scale = 0
+	}
+	if !unscaled.IsInt64() {
+		return domain.Decimal{}, fmt.Errorf("price %s is out of range", unscaled)
+	}
+	return domain.Decimal{Unscaled: unscaled.Int64(), Scale: scale}, nil
+}
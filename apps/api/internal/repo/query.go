@@ -0,0 +1,175 @@
+package repo
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/example/bookapi/internal/domain"
+)
+
+// sortColumns maps sort fields to their column and the type used to cast
+// cursor values back from their string form.
+var sortColumns = map[domain.BookSortField]struct {
+	column string
+	cast   string
+}{
+	domain.SortByTitle:     {column: "title", cast: "text"},
+	domain.SortByPrice:     {column: "price", cast: "numeric"},
+	domain.SortByStock:     {column: "stock", cast: "integer"},
+	domain.SortByCreatedAt: {column: "created_at", cast: "timestamptz"},
+	domain.SortByUpdatedAt: {column: "updated_at", cast: "timestamptz"},
+}
+
+type queryBuilder struct {
+	conditions []string
+	args       []any
+}
+
+func (b *queryBuilder) arg(value any) string {
+	b.args = append(b.args, value)
+	return fmt.Sprintf("$%d", len(b.args))
+}
+
+func (b *queryBuilder) where(format string, values ...any) {
+	placeholders := make([]any, 0, len(values))
+	for _, value := range values {
+		placeholders = append(placeholders, b.arg(value))
+	}
+	b.conditions = append(b.conditions, fmt.Sprintf(format, placeholders...))
+}
+
+func (b *queryBuilder) whereClause() string {
+	if len(b.conditions) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(b.conditions, " AND ")
+}
+
+// buildListQuery compiles query into a parameterised SELECT that fetches one
+// row more than the page limit so callers can tell whether more rows exist.
+// A zero limit selects every matching row, which is what exports use.
+func buildListQuery(query domain.BookQuery) (string, []any) {
+	b := &queryBuilder{}
+
+	if !query.IncludeDeleted {
+		b.conditions = append(b.conditions, "deleted_at IS NULL")
+	}
+
+	if query.Author != "" {
+		b.where(`author ILIKE '%%' || %s || '%%'`, escapeLike(query.Author))
+	}
+	if query.AuthorID != nil {
+		b.where("id IN (SELECT book_id FROM book_authors WHERE author_id = %s)", *query.AuthorID)
+	}
+	if query.CategoryID != nil {
+		b.where(`id IN (SELECT book_id FROM book_categories WHERE category_id IN (
+			WITH RECURSIVE subtree AS (
+				SELECT id FROM categories WHERE id = %s
+				UNION
+				SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+			)
+			SELECT id FROM subtree
+		))`, *query.CategoryID)
+	}
+	if query.Tag != "" {
+		b.where("id IN (SELECT bt.book_id FROM book_tags bt JOIN tags t ON t.id = bt.tag_id WHERE t.name = %s)", query.Tag)
+	}
+	if query.Currency != "" {
+		b.where("currency = %s", query.Currency)
+	}
+	if query.MinPrice != nil {
+		b.where("price >= %s::numeric", query.MinPrice.String())
+	}
+	if query.MaxPrice != nil {
+		b.where("price <= %s::numeric", query.MaxPrice.String())
+	}
+	if query.InStock {
+		b.conditions = append(b.conditions, "stock > 0")
+	}
+	if query.CreatedAfter != nil {
+		b.where("created_at >= %s", *query.CreatedAfter)
+	}
+	if query.CreatedBefore != nil {
+		b.where("created_at < %s", *query.CreatedBefore)
+	}
+	if query.UpdatedAfter != nil {
+		b.where("updated_at >= %s", *query.UpdatedAfter)
+	}
+	if query.UpdatedBefore != nil {
+		b.where("updated_at < %s", *query.UpdatedBefore)
+	}
+
+	backward := query.Cursor != nil && query.Cursor.Backward
+	if query.Cursor != nil {
+		b.conditions = append(b.conditions, keysetCondition(b, query.Sort, *query.Cursor))
+	}
+
+	order := make([]string, 0, len(query.Sort)+1)
+	for _, sort := range query.Sort {
+		order = append(order, sortColumns[sort.Field].column+" "+direction(sort.Desc != backward))
+	}
+	order = append(order, "id "+direction(backward))
+
+	limit := ""
+	if query.Limit > 0 {
+		limit = "LIMIT " + b.arg(query.Limit+1)
+	}
+
+	sql := fmt.Sprintf(`
+		SELECT %s
+		FROM books
+		%s
+		ORDER BY %s
+		%s
+	`, bookColumns, b.whereClause(), strings.Join(order, ", "), limit)
+	return sql, b.args
+}
+
+// keysetCondition expands the cursor into the lexicographic comparison
+// (a > $1) OR (a = $1 AND b > $2) OR ..., which, unlike a row comparison,
+// supports sort keys with mixed directions.
+func keysetCondition(b *queryBuilder, sorts []domain.BookSort, cursor domain.Cursor) string {
+	type key struct {
+		expr        string
+		placeholder string
+		desc        bool
+	}
+	keys := make([]key, 0, len(sorts)+1)
+	for i, sort := range sorts {
+		col := sortColumns[sort.Field]
+		keys = append(keys, key{
+			expr:        col.column,
+			placeholder: fmt.Sprintf("%s::%s", b.arg(cursor.Values[i]), col.cast),
+			desc:        sort.Desc != cursor.Backward,
+		})
+	}
+	keys = append(keys, key{expr: "id", placeholder: b.arg(cursor.ID), desc: cursor.Backward})
+
+	disjuncts := make([]string, 0, len(keys))
+	for i, k := range keys {
+		terms := make([]string, 0, i+1)
+		for _, prev := range keys[:i] {
+			terms = append(terms, fmt.Sprintf("%s = %s", prev.expr, prev.placeholder))
+		}
+		op := ">"
+		if k.desc {
+			op = "<"
+		}
+		terms = append(terms, fmt.Sprintf("%s %s %s", k.expr, op, k.placeholder))
+		disjuncts = append(disjuncts, "("+strings.Join(terms, " AND ")+")")
+	}
+	return "(" + strings.Join(disjuncts, " OR ") + ")"
+}
+
+func direction(desc bool) string {
+	if desc {
+		return "DESC"
+	}
+	return "ASC"
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func escapeLike(value string) string {
+	return likeEscaper.Replace(value)
+}
@@ -0,0 +1,88 @@
+package repo
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/bookapi/internal/domain"
+)
+
+func TestBuildListQuery_Filters(t *testing.T) {
+	minPrice := domain.Decimal{Unscaled: 550, Scale: 2}
+	after := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	sql, args := buildListQuery(domain.BookQuery{
+		Author:       "50%_off",
+		Currency:     "EUR",
+		MinPrice:     &minPrice,
+		InStock:      true,
+		CreatedAfter: &after,
+		Sort:         domain.DefaultBookSort,
+		Limit:        10,
+	})
+
+	require.Contains(t, sql, "deleted_at IS NULL")
+	require.Contains(t, sql, `author ILIKE '%' || $1 || '%'`)
+	require.Contains(t, sql, "currency = $2")
+	require.Contains(t, sql, "price >= $3::numeric")
+	require.Contains(t, sql, "stock > 0")
+	require.Contains(t, sql, "created_at >= $4")
+	require.Contains(t, sql, "ORDER BY created_at ASC, id ASC")
+	require.Contains(t, sql, "LIMIT $5")
+	require.Equal(t, []any{`50\%\_off`, "EUR", "5.50", after, 11}, args)
+}
+
+func TestBuildListQuery_Taxonomy(t *testing.T) {
+	category := uuid.New()
+	sql, args := buildListQuery(domain.BookQuery{CategoryID: &category, Tag: "epic fantasy", Sort: domain.DefaultBookSort, Limit: 10})
+
+	require.Contains(t, sql, "WITH RECURSIVE subtree")
+	require.Contains(t, sql, "SELECT id FROM categories WHERE id = $1")
+	require.Contains(t, sql, "WHERE t.name = $2")
+	require.Equal(t, []any{category, "epic fantasy", 11}, args)
+}
+
+func TestBuildListQuery_NoLimit(t *testing.T) {
+	sql, args := buildListQuery(domain.BookQuery{Sort: domain.DefaultBookSort, InStock: true})
+
+	require.NotContains(t, sql, "LIMIT")
+	require.Contains(t, sql, "ORDER BY created_at ASC, id ASC")
+	require.Empty(t, args)
+}
+
+func TestBuildListQuery_IncludeDeleted(t *testing.T) {
+	sql, args := buildListQuery(domain.BookQuery{Sort: domain.DefaultBookSort, Limit: 10, IncludeDeleted: true})
+
+	require.NotContains(t, sql, "deleted_at IS NULL")
+	require.Equal(t, []any{11}, args)
+}
+
+func TestBuildListQuery_MixedDirectionKeyset(t *testing.T) {
+	sorts := []domain.BookSort{{Field: domain.SortByPrice, Desc: true}, {Field: domain.SortByTitle}}
+	book := domain.Book{ID: uuid.New(), Title: "Go", Price: domain.Money{Amount: 1250, Currency: "USD"}}
+
+	t.Run("forward", func(t *testing.T) {
+		cursor := domain.NewCursor(book, sorts, false)
+		sql, args := buildListQuery(domain.BookQuery{Sort: sorts, Limit: 2, Cursor: &cursor})
+
+		require.Contains(t, sql, "((price < $1::numeric) OR (price = $1::numeric AND title > $2::text) OR "+
+			"(price = $1::numeric AND title = $2::text AND id > $3))")
+		require.Contains(t, sql, "ORDER BY price DESC, title ASC, id ASC")
+		require.Equal(t, []any{"12.50", "Go", book.ID, 3}, args)
+	})
+
+	t.Run("backward", func(t *testing.T) {
+		cursor := domain.NewCursor(book, sorts, true)
+		sql, _ := buildListQuery(domain.BookQuery{Sort: sorts, Limit: 2, Cursor: &cursor})
+
+		require.Contains(t, sql, "(price > $1::numeric)")
+		require.Contains(t, sql, "title < $2::text")
+		require.Contains(t, sql, "id < $3")
+		require.Contains(t, sql, "ORDER BY price ASC, title DESC, id DESC")
+		require.False(t, strings.Contains(sql, "id ASC"))
+	})
+}
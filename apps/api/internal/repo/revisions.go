@@ -0,0 +1,108 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/example/bookapi/internal/domain"
+)
+
+// RevisionRepository stores the audit trail of book changes.
+type RevisionRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewRevisionRepository(pool *pgxpool.Pool) *RevisionRepository {
+	return &RevisionRepository{pool: pool}
+}
+
+// Append stores a revision; call it inside TxManager.WithinTx so it commits
+// together with the change it records.
+func (r *RevisionRepository) Append(ctx context.Context, revision domain.BookRevision) error {
+	const query = `
+		INSERT INTO book_revisions (id, book_id, version, action, actor, snapshot, changes, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	snapshot, err := json.Marshal(revision.Snapshot)
+	if err != nil {
+		return fmt.Errorf("encode revision snapshot: %w", err)
+	}
+	changes, err := json.Marshal(revision.Changes)
+	if err != nil {
+		return fmt.Errorf("encode revision changes: %w", err)
+	}
+
+	_, err = dbFrom(ctx, r.pool).Exec(ctx, query,
+		revision.ID,
+		revision.BookID,
+		revision.Version,
+		revision.Action,
+		revision.Actor,
+		snapshot,
+		changes,
+		revision.CreatedAt,
+	)
+	return err
+}
+
+// List returns a page of a book's revisions, newest first.
+func (r *RevisionRepository) List(ctx context.Context, query domain.BookRevisionQuery) (domain.BookRevisionPage, error) {
+	const sql = `
+		SELECT id, book_id, version, action, actor, snapshot, changes, created_at
+		FROM book_revisions
+		WHERE book_id = $1 AND ($2::bigint IS NULL OR version < $2)
+		ORDER BY version DESC
+		LIMIT $3
+	`
+	rows, err := dbFrom(ctx, r.pool).Query(ctx, sql, query.BookID, query.BeforeVersion, query.Limit+1)
+	if err != nil {
+		return domain.BookRevisionPage{}, err
+	}
+	defer rows.Close()
+
+	var revisions []domain.BookRevision
+	for rows.Next() {
+		var (
+			revision          domain.BookRevision
+			snapshot, changes []byte
+		)
+		if err := rows.Scan(
+			&revision.ID,
+			&revision.BookID,
+			&revision.Version,
+			&revision.Action,
+			&revision.Actor,
+			&snapshot,
+			&changes,
+			&revision.CreatedAt,
+		); err != nil {
+			return domain.BookRevisionPage{}, err
+		}
+		if err := json.Unmarshal(snapshot, &revision.Snapshot); err != nil {
+			return domain.BookRevisionPage{}, fmt.Errorf("decode revision snapshot: %w", err)
+		}
+		if err := json.Unmarshal(changes, &revision.Changes); err != nil {
+			return domain.BookRevisionPage{}, fmt.Errorf("decode revision changes: %w", err)
+		}
+		revisions = append(revisions, revision)
+	}
+	if err := rows.Err(); err != nil {
+		return domain.BookRevisionPage{}, err
+	}
+
+	return buildRevisionPage(revisions, query.Limit), nil
+}
+
+// buildRevisionPage trims rows fetched with one extra row to the limit and
+// sets the cursor when older revisions remain.
+func buildRevisionPage(revisions []domain.BookRevision, limit int) domain.BookRevisionPage {
+	page := domain.BookRevisionPage{Revisions: revisions}
+	if len(revisions) > limit {
+		page.Revisions = revisions[:limit]
+		page.NextCursor = domain.EncodeRevisionCursor(page.Revisions[limit-1].Version)
+	}
+	return page
+}
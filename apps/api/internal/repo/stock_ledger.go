@@ -0,0 +1,90 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/example/bookapi/internal/domain"
+)
+
+// StockLedgerRepository stores the append-only stock_ledger.
+type StockLedgerRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewStockLedgerRepository(pool *pgxpool.Pool) *StockLedgerRepository {
+	return &StockLedgerRepository{pool: pool}
+}
+
+// Append records an adjustment; call it inside TxManager.WithinTx so it
+// commits together with the stock change it records.
+func (r *StockLedgerRepository) Append(ctx context.Context, adjustment domain.StockAdjustment) error {
+	const query = `
+		INSERT INTO stock_ledger (id, book_id, delta, reason, reference, actor, stock_after, book_version, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	_, err := dbFrom(ctx, r.pool).Exec(ctx, query,
+		adjustment.ID,
+		adjustment.BookID,
+		adjustment.Delta,
+		adjustment.Reason,
+		adjustment.Reference,
+		adjustment.Actor,
+		adjustment.StockAfter,
+		adjustment.BookVersion,
+		adjustment.CreatedAt,
+	)
+	return err
+}
+
+// List returns a page of a book's adjustments, newest first.
+func (r *StockLedgerRepository) List(ctx context.Context, query domain.StockLedgerQuery) (domain.StockLedgerPage, error) {
+	const sql = `
+		SELECT id, book_id, delta, reason, reference, actor, stock_after, book_version, created_at
+		FROM stock_ledger
+		WHERE book_id = $1 AND ($2::bigint IS NULL OR book_version < $2)
+		ORDER BY book_version DESC
+		LIMIT $3
+	`
+	rows, err := dbFrom(ctx, r.pool).Query(ctx, sql, query.BookID, query.BeforeVersion, query.Limit+1)
+	if err != nil {
+		return domain.StockLedgerPage{}, err
+	}
+	defer rows.Close()
+
+	var adjustments []domain.StockAdjustment
+	for rows.Next() {
+		var adjustment domain.StockAdjustment
+		if err := rows.Scan(
+			&adjustment.ID,
+			&adjustment.BookID,
+			&adjustment.Delta,
+			&adjustment.Reason,
+			&adjustment.Reference,
+			&adjustment.Actor,
+			&adjustment.StockAfter,
+			&adjustment.BookVersion,
+			&adjustment.CreatedAt,
+		); err != nil {
+			return domain.StockLedgerPage{}, err
+		}
+		adjustments = append(adjustments, adjustment)
+	}
+	if err := rows.Err(); err != nil {
+		return domain.StockLedgerPage{}, err
+	}
+
+	return buildStockLedgerPage(adjustments, query.Limit), nil
+}
+
+// buildStockLedgerPage trims rows fetched with one extra row to the limit
+// and sets the cursor when older adjustments remain.
+func buildStockLedgerPage(adjustments []domain.StockAdjustment, limit int) domain.StockLedgerPage {
+	page := domain.StockLedgerPage{Adjustments: adjustments}
+	if len(adjustments) > limit {
+		page.Adjustments = adjustments[:limit]
+		page.NextCursor = domain.EncodeRevisionCursor(page.Adjustments[limit-1].BookVersion)
+	}
+	return page
+}
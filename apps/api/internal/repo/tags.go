@@ -0,0 +1,127 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/example/bookapi/internal/domain"
+)
+
+// TagRepository stores tags. Links between books and tags are written by
+// BookRepository together with the book.
+type TagRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewTagRepository(pool *pgxpool.Pool) *TagRepository {
+	return &TagRepository{pool: pool}
+}
+
+func (r *TagRepository) Create(ctx context.Context, tag domain.Tag) error {
+	const query = `INSERT INTO tags (id, name, created_at) VALUES ($1, $2, $3)`
+	_, err := dbFrom(ctx, r.pool).Exec(ctx, query, tag.ID, tag.Name, tag.CreatedAt)
+	return translateTagError(err)
+}
+
+// Ensure creates the tags in names that do not exist yet. Names must be
+// normalized.
+func (r *TagRepository) Ensure(ctx context.Context, names []string, createdAt time.Time) error {
+	if len(names) == 0 {
+		return nil
+	}
+	const query = `
+		INSERT INTO tags (id, name, created_at)
+		SELECT t.id, t.name, $3 FROM unnest($1::uuid[], $2::text[]) AS t(id, name)
+		ON CONFLICT (tenant_id, name) DO NOTHING
+	`
+	ids := make([]string, 0, len(names))
+	for range names {
+		ids = append(ids, uuid.NewString())
+	}
+	_, err := dbFrom(ctx, r.pool).Exec(ctx, query, ids, names, createdAt)
+	return err
+}
+
+func (r *TagRepository) Get(ctx context.Context, id uuid.UUID) (domain.Tag, error) {
+	const query = `SELECT id, name, created_at FROM tags WHERE id = $1`
+	return scanTag(dbFrom(ctx, r.pool).QueryRow(ctx, query, id))
+}
+
+// List returns the tags whose name starts with prefix, ordered by name.
+func (r *TagRepository) List(ctx context.Context, prefix string) ([]domain.Tag, error) {
+	const query = `
+		SELECT id, name, created_at
+		FROM tags
+		WHERE name LIKE $1 || '%'
+		ORDER BY name
+	`
+	rows, err := dbFrom(ctx, r.pool).Query(ctx, query, escapeLike(prefix))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []domain.Tag{}
+	for rows.Next() {
+		tag, err := scanTag(rows)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+// Update renames the tag.
+func (r *TagRepository) Update(ctx context.Context, tag domain.Tag) error {
+	result, err := dbFrom(ctx, r.pool).Exec(ctx, `UPDATE tags SET name = $2 WHERE id = $1`, tag.ID, tag.Name)
+	if err != nil {
+		return translateTagError(err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrTagNotFound
+	}
+	return nil
+}
+
+// Delete removes a tag that is on no book.
+func (r *TagRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := dbFrom(ctx, r.pool).Exec(ctx, `DELETE FROM tags WHERE id = $1`, id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return domain.ErrTagInUse
+		}
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrTagNotFound
+	}
+	return nil
+}
+
+func scanTag(row pgx.Row) (domain.Tag, error) {
+	var tag domain.Tag
+	if err := row.Scan(&tag.ID, &tag.Name, &tag.CreatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Tag{}, domain.ErrTagNotFound
+		}
+		return domain.Tag{}, fmt.Errorf("scan tag: %w", err)
+	}
+	return tag, nil
+}
+
+func translateTagError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "tags_name_key" {
+		return domain.ErrDuplicateTag
+	}
+	return err
+}
@@ -0,0 +1,44 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/example/bookapi/internal/domain"
+)
+
+// NewPool connects to dsn with a pool that scopes every connection it hands
+// out to the tenant of the acquiring context, see domain.TenantFromContext.
+// The tenant is stored in the app.tenant_id setting, which the row-level
+// security policies on the catalog tables match rows against and which new
+// books, authors, categories and tags take as their tenant_id. Queries on a
+// pool created otherwise see no catalog rows and cannot write any.
+func NewPool(ctx context.Context, dsn string) (*pgxpool.Pool, error) {
+	config, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, err
+	}
+	config.PrepareConn = func(ctx context.Context, conn *pgx.Conn) (bool, error) {
+		const query = `SELECT set_config('app.tenant_id', $1, false)`
+		if _, err := conn.Exec(ctx, query, domain.TenantFromContext(ctx)); err != nil {
+			return false, fmt.Errorf("set tenant: %w", err)
+		}
+		return true, nil
+	}
+	return pgxpool.NewWithConfig(ctx, config)
+}
+
+// BypassesRowSecurity reports whether the role the pool connects as skips
+// row-level security, as superusers and roles with BYPASSRLS do. Such a role
+// sees the rows of every tenant.
+func BypassesRowSecurity(ctx context.Context, pool *pgxpool.Pool) (bool, error) {
+	const query = `SELECT rolsuper OR rolbypassrls FROM pg_roles WHERE rolname = current_user`
+	var bypasses bool
+	if err := pool.QueryRow(ctx, query).Scan(&bypasses); err != nil {
+		return false, fmt.Errorf("check row-level security: %w", err)
+	}
+	return bypasses, nil
+}
@@ -0,0 +1,288 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/example/bookapi/internal/domain"
+)
+
+// MaxBookAuthors bounds the number of authors credited on one book.
+const MaxBookAuthors = 20
+
+// errAuthorsNotConfigured is returned by author operations when the service
+// was built without WithAuthors.
+var errAuthorsNotConfigured = errors.New("author store is not configured")
+
+// AuthorStore keeps authors. Books reference them through domain.BookAuthor
+// links stored by the BookRepository.
+type AuthorStore interface {
+	Create(ctx context.Context, author domain.Author) error
+	Get(ctx context.Context, id uuid.UUID) (domain.Author, error)
+	// FindByName returns the oldest author with this name, ignoring case.
+	FindByName(ctx context.Context, name string) (domain.Author, error)
+	List(ctx context.Context, query domain.AuthorQuery) (domain.AuthorPage, error)
+	Update(ctx context.Context, author domain.Author) error
+	// Delete fails with domain.ErrAuthorInUse while the author is credited on
+	// a book.
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type AuthorInput struct {
+	Name string
+}
+
+type AuthorListInput struct {
+	// Name filters authors whose name contains it, ignoring case.
+	Name   string
+	Limit  int
+	Cursor string
+}
+
+// BookAuthorInput credits an author on a book. Role defaults to author.
+type BookAuthorInput struct {
+	AuthorID uuid.UUID
+	Role     string
+}
+
+func (s *BookService) CreateAuthor(ctx context.Context, input AuthorInput) (domain.Author, error) {
+	if s.authors == nil {
+		return domain.Author{}, errAuthorsNotConfigured
+	}
+	name, err := validateAuthorName(input.Name)
+	if err != nil {
+		return domain.Author{}, err
+	}
+	now := s.now().UTC()
+	author := domain.Author{ID: uuid.New(), Name: name, CreatedAt: now, UpdatedAt: now}
+	if err := s.authors.Create(ctx, author); err != nil {
+		return domain.Author{}, err
+	}
+	return author, nil
+}
+
+func (s *BookService) GetAuthor(ctx context.Context, id uuid.UUID) (domain.Author, error) {
+	if s.authors == nil {
+		return domain.Author{}, domain.ErrAuthorNotFound
+	}
+	return s.authors.Get(ctx, id)
+}
+
+// ListAuthors returns a page of authors ordered by name.
+func (s *BookService) ListAuthors(ctx context.Context, input AuthorListInput) (domain.AuthorPage, error) {
+	errors := make(map[string]FieldError)
+	query := domain.AuthorQuery{Name: strings.TrimSpace(input.Name), Limit: input.Limit}
+
+	if !withinLength(query.Name, 0, 200) {
+		errors["name"] = FieldError{domain.ViolationInvalidLength, "must be at most 200 characters"}
+	}
+	if query.Limit == 0 {
+		query.Limit = domain.DefaultPageLimit
+	} else if query.Limit < 1 || query.Limit > domain.MaxPageLimit {
+		errors["limit"] = FieldError{domain.ViolationOutOfRange, fmt.Sprintf("must be between 1 and %d", domain.MaxPageLimit)}
+	}
+	if cursor := strings.TrimSpace(input.Cursor); cursor != "" {
+		decoded, err := domain.DecodeCursor(cursor)
+		if err != nil || decoded.Sort != domain.AuthorCursorSort || len(decoded.Values) != 1 {
+			errors["cursor"] = FieldError{domain.ViolationInvalid, "invalid"}
+		} else {
+			query.After = &decoded
+		}
+	}
+	if len(errors) > 0 {
+		return domain.AuthorPage{}, ValidationError{Fields: errors}
+	}
+
+	if s.authors == nil {
+		return domain.AuthorPage{}, nil
+	}
+	return s.authors.List(ctx, query)
+}
+
+// UpdateAuthor renames an author. Books keep their author credit line as it
+// was printed; their authors list shows the new name.
+func (s *BookService) UpdateAuthor(ctx context.Context, id uuid.UUID, input AuthorInput) (domain.Author, error) {
+	if s.authors == nil {
+		return domain.Author{}, errAuthorsNotConfigured
+	}
+	name, err := validateAuthorName(input.Name)
+	if err != nil {
+		return domain.Author{}, err
+	}
+	author, err := s.authors.Get(ctx, id)
+	if err != nil {
+		return domain.Author{}, err
+	}
+	author.Name = name
+	author.UpdatedAt = s.now().UTC()
+	if err := s.authors.Update(ctx, author); err != nil {
+		return domain.Author{}, err
+	}
+	return author, nil
+}
+
+// DeleteAuthor removes an author who is not credited on any book, including
+// soft-deleted ones.
+func (s *BookService) DeleteAuthor(ctx context.Context, id uuid.UUID) error {
+	if s.authors == nil {
+		return errAuthorsNotConfigured
+	}
+	return s.authors.Delete(ctx, id)
+}
+
+// ListAuthorBooks returns a page of the live books crediting an author in any
+// role. Input takes the list filters, sort and pagination of ListBooks.
+func (s *BookService) ListAuthorBooks(ctx context.Context, id uuid.UUID, input BookListInput) (domain.BookPage, error) {
+	query, err := toBookQuery(input)
+	if err != nil {
+		return domain.BookPage{}, err
+	}
+	if _, err := s.GetAuthor(ctx, id); err != nil {
+		return domain.BookPage{}, err
+	}
+	query.AuthorID = &id
+	return s.repo.List(ctx, query)
+}
+
+// linkAuthors completes the author links of a book about to be written. With
+// links, every author must exist and an empty credit line is derived from
+// their names. Without links, the book is credited to the author named by its
+// credit line, who is created if needed, so books written by clients that
+// only know the author string still get an author. Call it inside the
+// transaction that writes the book.
+func (s *BookService) linkAuthors(ctx context.Context, book *domain.Book) error {
+	if s.authors == nil {
+		if len(book.Authors) > 0 {
+			return errAuthorsNotConfigured
+		}
+		return nil
+	}
+
+	if len(book.Authors) == 0 {
+		author, err := s.authorNamed(ctx, book.Author)
+		if err != nil {
+			return err
+		}
+		book.Authors = []domain.BookAuthor{{AuthorID: author.ID, Name: author.Name, Role: domain.RoleAuthor}}
+		return nil
+	}
+
+	invalid := make(map[string]FieldError)
+	for i, link := range book.Authors {
+		author, err := s.authors.Get(ctx, link.AuthorID)
+		if errors.Is(err, domain.ErrAuthorNotFound) {
+			invalid[fmt.Sprintf("authors[%d].authorId", i)] = FieldError{domain.ViolationUnknownReference, "unknown author"}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		book.Authors[i].Name = author.Name
+	}
+	if len(invalid) > 0 {
+		return ValidationError{Fields: invalid}
+	}
+
+	if book.Author == "" {
+		book.Author = domain.CreditLine(book.Authors)
+		if !withinLength(book.Author, 1, 200) {
+			return violation("author", domain.ViolationRequired, "required when the author names are longer than 200 characters together")
+		}
+	}
+	return nil
+}
+
+// relinkCreditedAuthor credits a book whose credit line was changed without
+// new links to the author of that name, replacing its previous authors and
+// keeping editors and translators.
+func (s *BookService) relinkCreditedAuthor(ctx context.Context, book *domain.Book) error {
+	if s.authors == nil {
+		return nil
+	}
+	author, err := s.authorNamed(ctx, book.Author)
+	if err != nil {
+		return err
+	}
+	links := []domain.BookAuthor{{AuthorID: author.ID, Name: author.Name, Role: domain.RoleAuthor}}
+	for _, link := range book.Authors {
+		if link.Role != domain.RoleAuthor {
+			links = append(links, link)
+		}
+	}
+	book.Authors = links
+	return nil
+}
+
+// authorNamed finds the author with this name or creates one.
+func (s *BookService) authorNamed(ctx context.Context, name string) (domain.Author, error) {
+	author, err := s.authors.FindByName(ctx, name)
+	if !errors.Is(err, domain.ErrAuthorNotFound) {
+		return author, err
+	}
+	now := s.now().UTC()
+	author = domain.Author{ID: uuid.New(), Name: name, CreatedAt: now, UpdatedAt: now}
+	if err := s.authors.Create(ctx, author); err != nil {
+		return domain.Author{}, fmt.Errorf("create author %q: %w", name, err)
+	}
+	return author, nil
+}
+
+// toBookAuthors validates author links in credit order, recording errors
+// under authors[i].
+func toBookAuthors(inputs []BookAuthorInput, errors map[string]FieldError) []domain.BookAuthor {
+	if len(inputs) > MaxBookAuthors {
+		errors["authors"] = FieldError{domain.ViolationInvalidLength, fmt.Sprintf("must include at most %d authors", MaxBookAuthors)}
+		return nil
+	}
+
+	links := make([]domain.BookAuthor, 0, len(inputs))
+	type credit struct {
+		id   uuid.UUID
+		role domain.AuthorRole
+	}
+	seen := make(map[credit]bool, len(inputs))
+	for i, input := range inputs {
+		role := domain.AuthorRole(strings.ToLower(strings.TrimSpace(input.Role)))
+		if role == "" {
+			role = domain.RoleAuthor
+		}
+		switch {
+		case input.AuthorID == uuid.Nil:
+			errors[fmt.Sprintf("authors[%d].authorId", i)] = FieldError{domain.ViolationRequired, "required"}
+		case !role.Valid():
+			errors[fmt.Sprintf("authors[%d].role", i)] = FieldError{domain.ViolationInvalidChoice, "must be one of author, editor, translator"}
+		case seen[credit{input.AuthorID, role}]:
+			errors[fmt.Sprintf("authors[%d]", i)] = FieldError{domain.ViolationDuplicate, "duplicates an earlier author with the same role"}
+		}
+		seen[credit{input.AuthorID, role}] = true
+		links = append(links, domain.BookAuthor{AuthorID: input.AuthorID, Role: role})
+	}
+	return links
+}
+
+func validateAuthorName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	switch {
+	case name == "":
+		return "", violation("name", domain.ViolationRequired, "required")
+	case utf8.RuneCountInString(name) > 200:
+		return "", violation("name", domain.ViolationInvalidLength, "must be 1-200 characters")
+	}
+	return name, nil
+}
+
+// WithAuthors enables authors: books are linked to the authors they credit
+// and authors can be managed through the service.
+func WithAuthors(store AuthorStore) BookServiceOption {
+	return func(service *BookService) {
+		if store == nil {
+			return
+		}
+		service.authors = store
+	}
+}
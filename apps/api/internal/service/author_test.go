@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/bookapi/internal/domain"
+	"github.com/example/bookapi/internal/repo"
+)
+
+func TestBookServiceAuthors(t *testing.T) {
+	books := repo.NewMemoryBookRepository()
+	svc := NewBookService(books, WithAuthors(books.Authors()))
+	ctx := context.Background()
+
+	legacy, err := svc.CreateBook(ctx, BookCreateInput{Title: "Dune", Author: "Frank Herbert", Price: "9.99"})
+	require.NoError(t, err)
+	require.Len(t, legacy.Authors, 1)
+	require.Equal(t, "Frank Herbert", legacy.Authors[0].Name)
+	require.Equal(t, domain.RoleAuthor, legacy.Authors[0].Role)
+
+	// A second book by the same name is credited to the same author.
+	again, err := svc.CreateBook(ctx, BookCreateInput{Title: "Dune Messiah", Author: "frank herbert", Price: "9.99"})
+	require.NoError(t, err)
+	require.Equal(t, legacy.Authors[0].AuthorID, again.Authors[0].AuthorID)
+
+	pratchett, err := svc.CreateAuthor(ctx, AuthorInput{Name: " Terry Pratchett "})
+	require.NoError(t, err)
+	require.Equal(t, "Terry Pratchett", pratchett.Name)
+	gaiman, err := svc.CreateAuthor(ctx, AuthorInput{Name: "Neil Gaiman"})
+	require.NoError(t, err)
+
+	omens, err := svc.CreateBook(ctx, BookCreateInput{
+		Title:   "Good Omens",
+		Authors: []BookAuthorInput{{AuthorID: pratchett.ID}, {AuthorID: gaiman.ID, Role: "author"}},
+		Price:   "8.99",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Terry Pratchett, Neil Gaiman", omens.Author)
+	require.Len(t, omens.Authors, 2)
+
+	_, err = svc.CreateBook(ctx, BookCreateInput{
+		Title:   "Nobody",
+		Authors: []BookAuthorInput{{AuthorID: uuid.New()}, {AuthorID: gaiman.ID, Role: "ghost"}},
+		Price:   "1",
+	})
+	validationErr, ok := err.(ValidationError)
+	require.True(t, ok)
+	require.Contains(t, validationErr.Fields, "authors[1].role")
+
+	_, err = svc.CreateBook(ctx, BookCreateInput{Title: "Nobody", Authors: []BookAuthorInput{{AuthorID: uuid.New()}}, Price: "1"})
+	validationErr, ok = err.(ValidationError)
+	require.True(t, ok)
+	require.Equal(t, FieldError{domain.ViolationUnknownReference, "unknown author"}, validationErr.Fields["authors[0].authorId"])
+
+	page, err := svc.ListAuthorBooks(ctx, gaiman.ID, BookListInput{})
+	require.NoError(t, err)
+	require.Len(t, page.Books, 1)
+	require.Equal(t, omens.ID, page.Books[0].ID)
+
+	// Replacing the authors re-derives the credit line.
+	updated, err := svc.UpdateBook(ctx, omens.ID, BookUpdateInput{Authors: &[]BookAuthorInput{{AuthorID: gaiman.ID}}})
+	require.NoError(t, err)
+	require.Equal(t, "Neil Gaiman", updated.Author)
+
+	// Changing only the credit line relinks the named author.
+	author := "Terry Pratchett"
+	updated, err = svc.UpdateBook(ctx, omens.ID, BookUpdateInput{Author: &author})
+	require.NoError(t, err)
+	require.Equal(t, []domain.BookAuthor{{AuthorID: pratchett.ID, Name: "Terry Pratchett", Role: domain.RoleAuthor}}, updated.Authors)
+
+	renamed, err := svc.UpdateAuthor(ctx, pratchett.ID, AuthorInput{Name: "Sir Terry Pratchett"})
+	require.NoError(t, err)
+	got, err := svc.GetBook(ctx, omens.ID)
+	require.NoError(t, err)
+	require.Equal(t, "Terry Pratchett", got.Author)
+	require.Equal(t, renamed.Name, got.Authors[0].Name)
+
+	require.ErrorIs(t, svc.DeleteAuthor(ctx, pratchett.ID), domain.ErrAuthorInUse)
+	require.NoError(t, svc.DeleteAuthor(ctx, gaiman.ID))
+	_, err = svc.GetAuthor(ctx, gaiman.ID)
+	require.ErrorIs(t, err, domain.ErrAuthorNotFound)
+
+	authors, err := svc.ListAuthors(ctx, AuthorListInput{Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, authors.Authors, 1)
+	require.Equal(t, "Frank Herbert", authors.Authors[0].Name)
+	authors, err = svc.ListAuthors(ctx, AuthorListInput{Limit: 1, Cursor: authors.NextCursor})
+	require.NoError(t, err)
+	require.Equal(t, "Sir Terry Pratchett", authors.Authors[0].Name)
+	require.Empty(t, authors.NextCursor)
+}
@@ -2,8 +2,11 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
 	"strings"
 	"time"
 	"unicode/utf8"
@@ -13,31 +16,77 @@ import (
 	"github.com/example/bookapi/internal/domain"
 )
 
-type ValidationError struct {
-	Fields map[string]string
-}
-
-func (v ValidationError) Error() string {
-	return fmt.Sprintf("validation failed: %v", v.Fields)
-}
-
 type BookRepository interface {
 	Create(ctx context.Context, book domain.Book) error
+	// CreateMany stores all books atomically.
+	CreateMany(ctx context.Context, books []domain.Book) error
 	Get(ctx context.Context, id uuid.UUID) (domain.Book, error)
-	List(ctx context.Context) ([]domain.Book, error)
+	// GetByTitleAuthor finds the live book with this title and author,
+	// ignoring case.
+	GetByTitleAuthor(ctx context.Context, title, author string) (domain.Book, error)
+	// GetByISBN finds the live book with this normalized ISBN-13.
+	GetByISBN(ctx context.Context, isbn string) (domain.Book, error)
+	// TakenISBNs returns those of isbns that a book, live or soft-deleted,
+	// already has.
+	TakenISBNs(ctx context.Context, isbns []string) ([]string, error)
+	List(ctx context.Context, query domain.BookQuery) (domain.BookPage, error)
+	// Export streams every book matching query, ignoring its limit and
+	// cursor, without loading them all into memory.
+	Export(ctx context.Context, query domain.BookQuery, fn func(domain.Book) error) error
+	Search(ctx context.Context, query domain.BookSearchQuery) ([]domain.BookSearchResult, error)
 	Update(ctx context.Context, book domain.Book) error
-	Delete(ctx context.Context, id uuid.UUID) error
+	// AdjustStock atomically adds delta to the stock of a live book and
+	// returns the updated book; stock never drops below zero.
+	AdjustStock(ctx context.Context, id uuid.UUID, delta int, updatedAt time.Time) (domain.Book, error)
+	Delete(ctx context.Context, id uuid.UUID, expectedVersion *int64, deletedAt time.Time) (domain.Book, error)
+	// Restore returns the restored book and the time it had been deleted at.
+	Restore(ctx context.Context, id uuid.UUID, restoredAt time.Time) (domain.Book, time.Time, error)
+	Purge(ctx context.Context, deletedBefore time.Time) (int64, error)
 }
 
 // BookEventPublisher emits domain events for books.
 type BookEventPublisher interface {
 	PublishBookCreated(ctx context.Context, book domain.Book) error
+	// PublishBookUpdated announces a change to a live book, including its
+	// restore after a delete.
+	PublishBookUpdated(ctx context.Context, update domain.BookUpdate) error
+	// PublishBookDeleted announces that book was soft-deleted.
+	PublishBookDeleted(ctx context.Context, book domain.Book) error
+	// PublishStockChanged announces a stock adjustment. Stock set by an
+	// update is announced in the update's changes instead.
+	PublishStockChanged(ctx context.Context, adjustment domain.StockAdjustment) error
+}
+
+// Transactor runs fn in a single database transaction. Repository calls made
+// with the context passed to fn take part in the transaction.
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// RevisionStore keeps the audit trail of book changes.
+type RevisionStore interface {
+	Append(ctx context.Context, revision domain.BookRevision) error
+	List(ctx context.Context, query domain.BookRevisionQuery) (domain.BookRevisionPage, error)
+}
+
+// OutboxStore records domain events for later delivery by the outbox relay.
+type OutboxStore interface {
+	Enqueue(ctx context.Context, event domain.OutboxEvent) error
 }
 
 type BookService struct {
-	repo      BookRepository
-	now       func() time.Time
-	publisher BookEventPublisher
+	repo       BookRepository
+	now        func() time.Time
+	publisher  BookEventPublisher
+	tx         Transactor
+	outbox     OutboxStore
+	revisions  RevisionStore
+	ledger     StockLedger
+	authors    AuthorStore
+	categories CategoryStore
+	tags       TagStore
+	fxRates    FXRateStore
+	rounding   domain.RoundingMode
 }
 
 func NewBookService(repo BookRepository, opts ...BookServiceOption) *BookService {
@@ -45,6 +94,9 @@ func NewBookService(repo BookRepository, opts ...BookServiceOption) *BookService
 		repo:      repo,
 		now:       time.Now,
 		publisher: noopBookEventPublisher{},
+		revisions: noopRevisionStore{},
+		ledger:    noopStockLedger{},
+		rounding:  domain.RoundHalfUp,
 	}
 
 	for _, opt := range opts {
@@ -55,58 +107,505 @@ func NewBookService(repo BookRepository, opts ...BookServiceOption) *BookService
 }
 
 type BookCreateInput struct {
-	Title    string
-	Author   string
-	Price    float64
+	Title string
+	// Author is the credit line. It may be left empty when Authors is set,
+	// in which case it is derived from the authors' names.
+	Author string
+	// Authors credits authors in order. When empty, the book is credited to
+	// the author named by Author.
+	Authors []BookAuthorInput
+	// CategoryIDs files the book under these categories.
+	CategoryIDs []uuid.UUID
+	// Tags are free-form; missing tags are created.
+	Tags []string
+	// Prices are explicit prices in currencies other than Currency.
+	Prices []PriceInput
+	// ISBN is an optional ISBN-10 or ISBN-13; it is stored as ISBN-13.
+	ISBN string
+	// Price is a decimal amount in major units of Currency, e.g. "24.99".
+	Price    string
 	Currency string
 	Stock    int
 }
 
+// ImportAction reports what ImportBook did with a book.
+type ImportAction string
+
+const (
+	ImportCreated   ImportAction = "created"
+	ImportUpdated   ImportAction = "updated"
+	ImportUnchanged ImportAction = "unchanged"
+)
+
+// MaxBatchCreateItems bounds the number of books a single CreateBooks call
+// accepts.
+const MaxBatchCreateItems = 5000
+
+// BookBatchItemResult is the outcome of one input of CreateBooks: the created
+// book, or the validation errors that kept it out of the batch.
+type BookBatchItemResult struct {
+	Index  int
+	Book   *domain.Book
+	Errors map[string]FieldError
+}
+
+type BookListInput struct {
+	Author         string
+	Category       string
+	Tag            string
+	Currency       string
+	MinPrice       string
+	MaxPrice       string
+	InStock        bool
+	CreatedAfter   *time.Time
+	CreatedBefore  *time.Time
+	UpdatedAfter   *time.Time
+	UpdatedBefore  *time.Time
+	IncludeDeleted bool
+	Sort           string
+	Limit          int
+	Cursor         string
+}
+
+type BookSearchInput struct {
+	Query  string
+	Limit  int
+	Offset int
+}
+
+type BookHistoryInput struct {
+	Limit  int
+	Cursor string
+}
+
 type BookUpdateInput struct {
-	Title    *string
-	Author   *string
-	Price    *float64
+	Title  *string
+	Author *string
+	// Authors replaces the credited authors. When Author is not set with it,
+	// the credit line is derived from the new authors' names.
+	Authors *[]BookAuthorInput
+	// CategoryIDs and Tags replace the book's categories and tags; empty
+	// lists remove them all.
+	CategoryIDs *[]uuid.UUID
+	Tags        *[]string
+	// Prices replaces the explicit prices; an empty list removes them.
+	Prices *[]PriceInput
+	// ISBN replaces the ISBN; an empty string removes it.
+	ISBN     *string
+	Price    *string
 	Currency *string
 	Stock    *int
+	// ExpectedVersion, when set, makes the update fail unless the stored book
+	// is still at this version.
+	ExpectedVersion *int64
 }
 
 func (s *BookService) CreateBook(ctx context.Context, input BookCreateInput) (domain.Book, error) {
-	if err := validateBookCreateInput(input); err != nil {
+	book, err := newBook(input, s.now().UTC())
+	if err != nil {
 		return domain.Book{}, err
 	}
+	return s.create(ctx, book)
+}
+
+func (s *BookService) create(ctx context.Context, book domain.Book) (domain.Book, error) {
+	err := s.inTx(ctx, func(ctx context.Context) error {
+		if err := s.linkAuthors(ctx, &book); err != nil {
+			return err
+		}
+		if err := s.linkTaxonomy(ctx, &book); err != nil {
+			return err
+		}
+		if err := s.repo.Create(ctx, book); err != nil {
+			return err
+		}
+		return s.recordCreated(ctx, book)
+	})
+	if err != nil {
+		return domain.Book{}, linkError(err)
+	}
+
+	s.publishCreated(ctx, book)
+	return book, nil
+}
+
+// linkError reports the authors, categories and tags a book write found
+// missing, deleted after the book was validated, as invalid input rather
+// than as the book's own absence.
+func linkError(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrAuthorNotFound):
+		return &domain.Error{Kind: domain.ErrValidation, Message: "a credited author no longer exists", Err: err}
+	case errors.Is(err, domain.ErrCategoryNotFound), errors.Is(err, domain.ErrTagNotFound):
+		return &domain.Error{Kind: domain.ErrValidation, Message: "a category or tag of the book no longer exists", Err: err}
+	}
+	return err
+}
+
+// ImportBook upserts a book by its ISBN or, failing that, by its title and
+// author compared case-insensitively: a new book is created, while an
+// existing one takes the price, currency and stock of input, and its ISBN
+// when it had none. Input is validated like CreateBook. With dryRun nothing
+// is written and the returned book and action show what the import would
+// do.
+func (s *BookService) ImportBook(ctx context.Context, input BookCreateInput, dryRun bool) (domain.Book, ImportAction, error) {
+	book, err := newBook(input, s.now().UTC())
+	if err != nil {
+		return domain.Book{}, "", err
+	}
+
+	existing, err := s.findImportTarget(ctx, book)
+	if errors.Is(err, domain.ErrBookNotFound) {
+		if dryRun {
+			return book, ImportCreated, nil
+		}
+		created, err := s.create(ctx, book)
+		return created, ImportCreated, err
+	}
+	if err != nil {
+		return domain.Book{}, "", err
+	}
+
+	if book.ISBN == "" {
+		book.ISBN = existing.ISBN
+	}
+	if existing.Price == book.Price && existing.Stock == book.Stock && existing.ISBN == book.ISBN {
+		return existing, ImportUnchanged, nil
+	}
+	if dryRun {
+		existing.Price = book.Price
+		existing.Stock = book.Stock
+		existing.ISBN = book.ISBN
+		return existing, ImportUpdated, nil
+	}
+
+	amount, currency := book.Price.String(), book.Price.Currency
+	updated, err := s.UpdateBook(ctx, existing.ID, BookUpdateInput{
+		ISBN:            &book.ISBN,
+		Price:           &amount,
+		Currency:        &currency,
+		Stock:           &book.Stock,
+		ExpectedVersion: &existing.Version,
+	})
+	return updated, ImportUpdated, err
+}
+
+// findImportTarget returns the stored book an imported book updates. A book
+// found by title and author only matches when it has no ISBN or the same
+// one; otherwise the import is a different edition.
+func (s *BookService) findImportTarget(ctx context.Context, book domain.Book) (domain.Book, error) {
+	if book.ISBN != "" {
+		existing, err := s.repo.GetByISBN(ctx, book.ISBN)
+		if !errors.Is(err, domain.ErrBookNotFound) {
+			return existing, err
+		}
+	}
+	existing, err := s.repo.GetByTitleAuthor(ctx, book.Title, book.Author)
+	if err != nil {
+		return domain.Book{}, err
+	}
+	if book.ISBN != "" && existing.ISBN != "" && existing.ISBN != book.ISBN {
+		return domain.Book{}, domain.ErrBookNotFound
+	}
+	return existing, nil
+}
+
+// CreateBooks validates every input on its own and creates the valid ones
+// in a single transaction. Invalid inputs, including those with an ISBN
+// another book already has, are reported in the result for their index and
+// do not stop the rest of the batch; the returned error is reserved for a
+// rejected batch or a failed write, in which case nothing is created.
+func (s *BookService) CreateBooks(ctx context.Context, inputs []BookCreateInput) ([]BookBatchItemResult, error) {
+	if len(inputs) == 0 {
+		return nil, violation("items", domain.ViolationRequired, "must include at least one item")
+	}
+	if len(inputs) > MaxBatchCreateItems {
+		return nil, violation("items", domain.ViolationInvalidLength, fmt.Sprintf("must include at most %d items", MaxBatchCreateItems))
+	}
 
 	now := s.now().UTC()
-	book := domain.Book{
-		ID:        uuid.New(),
-		Title:     strings.TrimSpace(input.Title),
-		Author:    strings.TrimSpace(input.Author),
-		Price:     input.Price,
-		Currency:  normalizeCurrency(input.Currency),
-		Stock:     input.Stock,
-		CreatedAt: now,
-		UpdatedAt: now,
-	}
-
-	if err := s.repo.Create(ctx, book); err != nil {
+	results := make([]BookBatchItemResult, len(inputs))
+	books := make([]domain.Book, 0, len(inputs))
+	indices := make([]int, 0, len(inputs))
+	isbnIndex := make(map[string]int, len(inputs))
+	for i, input := range inputs {
+		results[i].Index = i
+		book, err := newBook(input, now)
+		if err != nil {
+			var validationErr ValidationError
+			if !errors.As(err, &validationErr) {
+				return nil, err
+			}
+			results[i].Errors = validationErr.Fields
+			continue
+		}
+		if book.ISBN != "" {
+			if first, ok := isbnIndex[book.ISBN]; ok {
+				results[i].Errors = map[string]FieldError{"isbn": {domain.ViolationDuplicate, fmt.Sprintf("duplicates item %d", first)}}
+				continue
+			}
+			isbnIndex[book.ISBN] = i
+		}
+		books = append(books, book)
+		indices = append(indices, i)
+	}
+	if len(books) == 0 {
+		return results, nil
+	}
+
+	var created []domain.Book
+	err := s.inTx(ctx, func(ctx context.Context) error {
+		// A taken ISBN would fail the whole copy, so it is reported for its
+		// item instead.
+		if len(isbnIndex) > 0 {
+			taken, err := s.repo.TakenISBNs(ctx, slices.Collect(maps.Keys(isbnIndex)))
+			if err != nil {
+				return err
+			}
+			for _, isbn := range taken {
+				results[isbnIndex[isbn]].Errors = map[string]FieldError{"isbn": {domain.ViolationDuplicate, domain.ErrDuplicateISBN.Message}}
+			}
+		}
+
+		created = make([]domain.Book, 0, len(books))
+		for i, book := range books {
+			if results[indices[i]].Errors != nil {
+				continue
+			}
+			err := s.linkAuthors(ctx, &book)
+			if err == nil {
+				err = s.linkTaxonomy(ctx, &book)
+			}
+			if err != nil {
+				var validationErr ValidationError
+				if !errors.As(err, &validationErr) {
+					return err
+				}
+				results[indices[i]].Errors = validationErr.Fields
+				continue
+			}
+			created = append(created, book)
+		}
+		if len(created) == 0 {
+			return nil
+		}
+		if err := s.repo.CreateMany(ctx, created); err != nil {
+			return err
+		}
+		for _, book := range created {
+			if err := s.recordCreated(ctx, book); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, linkError(err)
+	}
+
+	for i := range results {
+		if results[i].Errors != nil {
+			continue
+		}
+		results[i].Book = &created[0]
+		created = created[1:]
+		s.publishCreated(ctx, *results[i].Book)
+	}
+	return results, nil
+}
+
+// newBook validates input and builds the book it describes.
+func newBook(input BookCreateInput, now time.Time) (domain.Book, error) {
+	if err := validateBookCreateInput(input); err != nil {
 		return domain.Book{}, err
 	}
 
-	if err := s.publisher.PublishBookCreated(ctx, book); err != nil {
-		slog.Error("failed to publish book created event",
+	price, err := domain.ParseMoney(input.Price, normalizeCurrency(input.Currency))
+	if err != nil {
+		return domain.Book{}, ValidationError{Fields: map[string]FieldError{"price": moneyError(err)}}
+	}
+	isbn, err := normalizeISBN(input.ISBN)
+	if err != nil {
+		return domain.Book{}, ValidationError{Fields: map[string]FieldError{"isbn": isbnError(err)}}
+	}
+	// Validated above.
+	var authors []domain.BookAuthor
+	if len(input.Authors) > 0 {
+		authors = toBookAuthors(input.Authors, make(map[string]FieldError))
+	}
+	categories := toBookCategories(input.CategoryIDs, make(map[string]FieldError))
+	tags := normalizeTags(input.Tags, make(map[string]FieldError))
+	prices := toBookPrices(input.Prices, price.Currency, make(map[string]FieldError))
+
+	return domain.Book{
+		ID:         uuid.New(),
+		Title:      strings.TrimSpace(input.Title),
+		Author:     strings.TrimSpace(input.Author),
+		Authors:    authors,
+		Categories: categories,
+		Tags:       tags,
+		ISBN:       isbn,
+		Price:      price,
+		Prices:     prices,
+		Stock:      input.Stock,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		Version:    1,
+	}, nil
+}
+
+// recordCreated writes the revision and, when an outbox is configured, the
+// book created event for a new book. Call it inside the creating transaction.
+func (s *BookService) recordCreated(ctx context.Context, book domain.Book) error {
+	if err := s.recordRevision(ctx, domain.RevisionCreated, nil, book); err != nil {
+		return err
+	}
+	return s.enqueue(ctx, func() (domain.OutboxEvent, error) {
+		return domain.NewBookCreatedEvent(book)
+	})
+}
+
+// publishCreated sends the book created event directly when there is no
+// outbox to deliver it.
+func (s *BookService) publishCreated(ctx context.Context, book domain.Book) {
+	s.publish(ctx, domain.EventTypeBookCreated, book.ID, func(ctx context.Context) error {
+		return s.publisher.PublishBookCreated(ctx, book)
+	})
+}
+
+// recordUpdated writes the revision of a change from before to after and,
+// when an outbox is configured and a field changed, the book updated event.
+// Call it inside the changing transaction. The returned update has no
+// changes when nothing user-visible changed.
+func (s *BookService) recordUpdated(ctx context.Context, action domain.RevisionAction, before, after domain.Book) (domain.BookUpdate, error) {
+	if err := s.recordRevision(ctx, action, &before, after); err != nil {
+		return domain.BookUpdate{}, err
+	}
+	update := domain.BookUpdate{Book: after, Changes: domain.DiffBooks(&before, after)}
+	if len(update.Changes) == 0 {
+		return update, nil
+	}
+	return update, s.enqueue(ctx, func() (domain.OutboxEvent, error) {
+		return domain.NewBookUpdatedEvent(update)
+	})
+}
+
+// publishUpdated sends the book updated event directly when there is no
+// outbox to deliver it and a field changed.
+func (s *BookService) publishUpdated(ctx context.Context, update domain.BookUpdate) {
+	if len(update.Changes) == 0 {
+		return
+	}
+	s.publish(ctx, domain.EventTypeBookUpdated, update.Book.ID, func(ctx context.Context) error {
+		return s.publisher.PublishBookUpdated(ctx, update)
+	})
+}
+
+// enqueue adds the event built by newEvent to the outbox, when one is
+// configured. Call it inside the transaction making the change it announces.
+func (s *BookService) enqueue(ctx context.Context, newEvent func() (domain.OutboxEvent, error)) error {
+	if s.outbox == nil {
+		return nil
+	}
+	event, err := newEvent()
+	if err != nil {
+		return fmt.Errorf("build outbox event: %w", err)
+	}
+	event.Actor = domain.ActorFromContext(ctx)
+	event.Tenant = domain.TenantFromContext(ctx)
+	return s.outbox.Enqueue(ctx, event)
+}
+
+// publish sends an event with fn when there is no outbox to deliver it.
+// Call it after the change commits. Failures are logged, not returned: the
+// change has been made.
+func (s *BookService) publish(ctx context.Context, eventType string, bookID uuid.UUID, fn func(ctx context.Context) error) {
+	if s.outbox != nil {
+		return
+	}
+	if err := fn(ctx); err != nil {
+		slog.Error("failed to publish book event",
 			"error", err,
-			"bookId", book.ID,
+			"eventType", eventType,
+			"bookId", bookID,
 		)
 	}
-
-	return book, nil
 }
 
 func (s *BookService) GetBook(ctx context.Context, id uuid.UUID) (domain.Book, error) {
 	return s.repo.Get(ctx, id)
 }
 
-func (s *BookService) ListBooks(ctx context.Context) ([]domain.Book, error) {
-	return s.repo.List(ctx)
+// GetBookByISBN looks a book up by its ISBN-10 or ISBN-13.
+func (s *BookService) GetBookByISBN(ctx context.Context, isbn string) (domain.Book, error) {
+	normalized, err := domain.NormalizeISBN(isbn)
+	if err != nil {
+		return domain.Book{}, ValidationError{Fields: map[string]FieldError{"isbn": isbnError(err)}}
+	}
+	return s.repo.GetByISBN(ctx, normalized)
+}
+
+func (s *BookService) ListBooks(ctx context.Context, input BookListInput) (domain.BookPage, error) {
+	query, err := toBookQuery(input)
+	if err != nil {
+		return domain.BookPage{}, err
+	}
+	return s.repo.List(ctx, query)
+}
+
+// BookExport is a validated export that streams its books on demand.
+type BookExport struct {
+	repo  BookRepository
+	query domain.BookQuery
+}
+
+// ExportBooks validates input, which takes the list filters and sort, and
+// returns an export of every matching book. Limit and Cursor are ignored.
+// Validation happens here so callers can reject a bad request before they
+// start writing the export.
+func (s *BookService) ExportBooks(input BookListInput) (*BookExport, error) {
+	input.Limit = 0
+	input.Cursor = ""
+	query, err := toBookQuery(input)
+	if err != nil {
+		return nil, err
+	}
+	query.Limit = 0
+	return &BookExport{repo: s.repo, query: query}, nil
+}
+
+// Each calls fn for every book of the export in order, stopping at the first
+// error.
+func (e *BookExport) Each(ctx context.Context, fn func(domain.Book) error) error {
+	return e.repo.Export(ctx, e.query, fn)
+}
+
+func (s *BookService) SearchBooks(ctx context.Context, input BookSearchInput) ([]domain.BookSearchResult, error) {
+	errors := make(map[string]FieldError)
+	search := domain.BookSearchQuery{
+		Query:  strings.TrimSpace(input.Query),
+		Limit:  input.Limit,
+		Offset: input.Offset,
+	}
+
+	if search.Query == "" {
+		errors["q"] = FieldError{domain.ViolationRequired, "required"}
+	} else if !withinLength(search.Query, 1, 200) {
+		errors["q"] = FieldError{domain.ViolationInvalidLength, "must be 1-200 characters"}
+	}
+	if search.Limit == 0 {
+		search.Limit = domain.DefaultPageLimit
+	} else if search.Limit < 1 || search.Limit > domain.MaxPageLimit {
+		errors["limit"] = FieldError{domain.ViolationOutOfRange, fmt.Sprintf("must be between 1 and %d", domain.MaxPageLimit)}
+	}
+	if search.Offset < 0 {
+		errors["offset"] = FieldError{domain.ViolationOutOfRange, "must be >= 0"}
+	}
+
+	if len(errors) > 0 {
+		return nil, ValidationError{Fields: errors}
+	}
+	return s.repo.Search(ctx, search)
 }
 
 func (s *BookService) UpdateBook(ctx context.Context, id uuid.UUID, input BookUpdateInput) (domain.Book, error) {
@@ -118,32 +617,214 @@ func (s *BookService) UpdateBook(ctx context.Context, id uuid.UUID, input BookUp
 	if err != nil {
 		return domain.Book{}, err
 	}
+	before := existing
 
+	if input.ExpectedVersion != nil {
+		existing.Version = *input.ExpectedVersion
+	}
 	if input.Title != nil {
 		existing.Title = strings.TrimSpace(*input.Title)
 	}
 	if input.Author != nil {
 		existing.Author = strings.TrimSpace(*input.Author)
 	}
-	if input.Price != nil {
-		existing.Price = *input.Price
+	relink := input.Authors == nil && existing.Author != before.Author
+	if input.Authors != nil {
+		// Validated above.
+		existing.Authors = toBookAuthors(*input.Authors, make(map[string]FieldError))
+		if input.Author == nil {
+			existing.Author = ""
+		}
 	}
-	if input.Currency != nil {
-		existing.Currency = normalizeCurrency(*input.Currency)
+	if input.CategoryIDs != nil {
+		// Validated above.
+		existing.Categories = toBookCategories(*input.CategoryIDs, make(map[string]FieldError))
+	}
+	if input.Tags != nil {
+		// Validated above.
+		existing.Tags = normalizeTags(*input.Tags, make(map[string]FieldError))
+	}
+	if input.ISBN != nil {
+		// Validated above.
+		existing.ISBN, _ = normalizeISBN(*input.ISBN)
+	}
+	if input.Price != nil || input.Currency != nil {
+		// A new currency keeps the amount, which must then fit the new
+		// currency's minor unit.
+		amount, currency := existing.Price.String(), existing.Price.Currency
+		if input.Price != nil {
+			amount = *input.Price
+		}
+		if input.Currency != nil {
+			currency = normalizeCurrency(*input.Currency)
+		}
+		price, err := domain.ParseMoney(amount, currency)
+		if err != nil {
+			return domain.Book{}, ValidationError{Fields: map[string]FieldError{"price": moneyError(err)}}
+		}
+		existing.Price = price
+	}
+	if input.Prices != nil {
+		// Validated above.
+		existing.Prices = toBookPrices(*input.Prices, "", make(map[string]FieldError))
+	}
+	if slices.ContainsFunc(existing.Prices, func(price domain.Money) bool { return price.Currency == existing.Price.Currency }) {
+		return domain.Book{}, violation("prices", domain.ViolationDuplicate,
+			fmt.Sprintf("cannot include the base currency %s", existing.Price.Currency))
 	}
 	if input.Stock != nil {
 		existing.Stock = *input.Stock
 	}
 	existing.UpdatedAt = s.now().UTC()
 
-	if err := s.repo.Update(ctx, existing); err != nil {
-		return domain.Book{}, err
+	var update domain.BookUpdate
+	err = s.inTx(ctx, func(ctx context.Context) error {
+		if relink {
+			if err := s.relinkCreditedAuthor(ctx, &existing); err != nil {
+				return err
+			}
+		} else if input.Authors != nil {
+			if err := s.linkAuthors(ctx, &existing); err != nil {
+				return err
+			}
+		}
+		if input.CategoryIDs != nil || input.Tags != nil {
+			if err := s.linkTaxonomy(ctx, &existing); err != nil {
+				return err
+			}
+		}
+		if err := s.repo.Update(ctx, existing); err != nil {
+			return err
+		}
+		existing.Version++
+		var err error
+		update, err = s.recordUpdated(ctx, domain.RevisionUpdated, before, existing)
+		return err
+	})
+	if err != nil {
+		return domain.Book{}, linkError(err)
 	}
+
+	s.publishUpdated(ctx, update)
 	return existing, nil
 }
 
-func (s *BookService) DeleteBook(ctx context.Context, id uuid.UUID) error {
-	return s.repo.Delete(ctx, id)
+// DeleteBook soft-deletes a book; a non-nil expectedVersion makes the delete
+// conditional on the stored version.
+func (s *BookService) DeleteBook(ctx context.Context, id uuid.UUID, expectedVersion *int64) error {
+	var deleted domain.Book
+	err := s.inTx(ctx, func(ctx context.Context) error {
+		var err error
+		deleted, err = s.repo.Delete(ctx, id, expectedVersion, s.now().UTC())
+		if err != nil {
+			return err
+		}
+		before := deleted
+		before.DeletedAt = nil
+		if err := s.recordRevision(ctx, domain.RevisionDeleted, &before, deleted); err != nil {
+			return err
+		}
+		return s.enqueue(ctx, func() (domain.OutboxEvent, error) {
+			return domain.NewBookDeletedEvent(deleted)
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	s.publish(ctx, domain.EventTypeBookDeleted, deleted.ID, func(ctx context.Context) error {
+		return s.publisher.PublishBookDeleted(ctx, deleted)
+	})
+	return nil
+}
+
+// RestoreBook brings a soft-deleted book back. It is announced as an update
+// clearing deletedAt.
+func (s *BookService) RestoreBook(ctx context.Context, id uuid.UUID) (domain.Book, error) {
+	var (
+		restored domain.Book
+		update   domain.BookUpdate
+	)
+	err := s.inTx(ctx, func(ctx context.Context) error {
+		var (
+			deletedAt time.Time
+			err       error
+		)
+		restored, deletedAt, err = s.repo.Restore(ctx, id, s.now().UTC())
+		if err != nil {
+			return err
+		}
+		before := restored
+		before.DeletedAt = &deletedAt
+		update, err = s.recordUpdated(ctx, domain.RevisionRestored, before, restored)
+		return err
+	})
+	if err != nil {
+		return domain.Book{}, err
+	}
+
+	s.publishUpdated(ctx, update)
+	return restored, nil
+}
+
+// ListBookHistory returns the revisions of a book, newest first.
+func (s *BookService) ListBookHistory(ctx context.Context, id uuid.UUID, input BookHistoryInput) (domain.BookRevisionPage, error) {
+	errors := make(map[string]FieldError)
+	query := domain.BookRevisionQuery{BookID: id, Limit: input.Limit}
+
+	if query.Limit == 0 {
+		query.Limit = domain.DefaultPageLimit
+	} else if query.Limit < 1 || query.Limit > domain.MaxPageLimit {
+		errors["limit"] = FieldError{domain.ViolationOutOfRange, fmt.Sprintf("must be between 1 and %d", domain.MaxPageLimit)}
+	}
+	if cursor := strings.TrimSpace(input.Cursor); cursor != "" {
+		version, err := domain.DecodeRevisionCursor(cursor)
+		if err != nil {
+			errors["cursor"] = FieldError{domain.ViolationInvalid, "invalid"}
+		}
+		query.BeforeVersion = &version
+	}
+	if len(errors) > 0 {
+		return domain.BookRevisionPage{}, ValidationError{Fields: errors}
+	}
+
+	page, err := s.revisions.List(ctx, query)
+	if err != nil {
+		return domain.BookRevisionPage{}, err
+	}
+	if len(page.Revisions) == 0 && query.BeforeVersion == nil {
+		// Books created before history was recorded have no revisions yet.
+		if _, err := s.repo.Get(ctx, id); err != nil {
+			return domain.BookRevisionPage{}, err
+		}
+	}
+	return page, nil
+}
+
+// inTx runs fn in a transaction when the service has a Transactor and
+// directly otherwise.
+func (s *BookService) inTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if s.tx == nil {
+		return fn(ctx)
+	}
+	return s.tx.WithinTx(ctx, fn)
+}
+
+func (s *BookService) recordRevision(ctx context.Context, action domain.RevisionAction, before *domain.Book, after domain.Book) error {
+	revision := domain.NewBookRevision(action, domain.ActorFromContext(ctx), before, after, s.now().UTC())
+	if err := s.revisions.Append(ctx, revision); err != nil {
+		return fmt.Errorf("record book revision: %w", err)
+	}
+	return nil
+}
+
+// PurgeDeletedBooks permanently removes books that were soft-deleted more
+// than olderThan ago and reports how many were removed.
+func (s *BookService) PurgeDeletedBooks(ctx context.Context, olderThan time.Duration) (int64, error) {
+	if olderThan < 0 {
+		return 0, violation("olderThan", domain.ViolationOutOfRange, "must be >= 0")
+	}
+	return s.repo.Purge(ctx, s.now().UTC().Add(-olderThan))
 }
 
 func normalizeCurrency(curr string) string {
@@ -154,34 +835,57 @@ func normalizeCurrency(curr string) string {
 	return strings.ToUpper(curr)
 }
 
+// normalizeISBN returns the ISBN-13 form of an optional ISBN; blank input
+// means no ISBN.
+func normalizeISBN(isbn string) (string, error) {
+	if strings.TrimSpace(isbn) == "" {
+		return "", nil
+	}
+	return domain.NormalizeISBN(isbn)
+}
+
 func validateBookCreateInput(input BookCreateInput) error {
-	errors := make(map[string]string)
+	errors := make(map[string]FieldError)
 
 	title := strings.TrimSpace(input.Title)
 	if title == "" {
-		errors["title"] = "required"
+		errors["title"] = FieldError{domain.ViolationRequired, "required"}
 	} else if !withinLength(title, 1, 200) {
-		errors["title"] = "must be 1-200 characters"
+		errors["title"] = FieldError{domain.ViolationInvalidLength, "must be 1-200 characters"}
 	}
 
 	author := strings.TrimSpace(input.Author)
 	if author == "" {
-		errors["author"] = "required"
+		if len(input.Authors) == 0 {
+			errors["author"] = FieldError{domain.ViolationRequired, "required unless authors are given"}
+		}
 	} else if !withinLength(author, 1, 200) {
-		errors["author"] = "must be 1-200 characters"
-	}
-
-	if input.Price < 0 {
-		errors["price"] = "must be >= 0"
+		errors["author"] = FieldError{domain.ViolationInvalidLength, "must be 1-200 characters"}
 	}
+	toBookAuthors(input.Authors, errors)
+	toBookCategories(input.CategoryIDs, errors)
+	normalizeTags(input.Tags, errors)
 
 	currency := normalizeCurrency(input.Currency)
 	if len(currency) != 3 || strings.ToUpper(currency) != currency {
-		errors["currency"] = "must be ISO 4217 code"
+		errors["currency"] = FieldError{domain.ViolationInvalidFormat, "must be ISO 4217 code"}
+	}
+	toBookPrices(input.Prices, currency, errors)
+
+	if strings.TrimSpace(input.Price) == "" {
+		errors["price"] = FieldError{domain.ViolationRequired, "required"}
+	} else if price, err := domain.ParseMoney(input.Price, currency); err != nil {
+		errors["price"] = moneyError(err)
+	} else if price.IsNegative() {
+		errors["price"] = FieldError{domain.ViolationOutOfRange, "must be >= 0"}
 	}
 
 	if input.Stock < 0 {
-		errors["stock"] = "must be >= 0"
+		errors["stock"] = FieldError{domain.ViolationOutOfRange, "must be >= 0"}
+	}
+
+	if _, err := normalizeISBN(input.ISBN); err != nil {
+		errors["isbn"] = isbnError(err)
 	}
 
 	if len(errors) > 0 {
@@ -191,43 +895,72 @@ func validateBookCreateInput(input BookCreateInput) error {
 }
 
 func validateBookUpdateInput(input BookUpdateInput) error {
-	errors := make(map[string]string)
-	if input.Title == nil && input.Author == nil && input.Price == nil && input.Currency == nil && input.Stock == nil {
-		errors["body"] = "must include at least one field"
+	errors := make(map[string]FieldError)
+	if input.Title == nil && input.Author == nil && input.Authors == nil && input.CategoryIDs == nil && input.Tags == nil &&
+		input.ISBN == nil && input.Price == nil && input.Currency == nil && input.Prices == nil && input.Stock == nil {
+		errors["body"] = FieldError{domain.ViolationRequired, "must include at least one field"}
 		return ValidationError{Fields: errors}
 	}
 
 	if input.Title != nil {
 		value := strings.TrimSpace(*input.Title)
 		if value == "" {
-			errors["title"] = "cannot be empty"
+			errors["title"] = FieldError{domain.ViolationRequired, "cannot be empty"}
 		} else if !withinLength(value, 1, 200) {
-			errors["title"] = "must be 1-200 characters"
+			errors["title"] = FieldError{domain.ViolationInvalidLength, "must be 1-200 characters"}
 		}
 	}
 
 	if input.Author != nil {
 		value := strings.TrimSpace(*input.Author)
 		if value == "" {
-			errors["author"] = "cannot be empty"
+			errors["author"] = FieldError{domain.ViolationRequired, "cannot be empty"}
 		} else if !withinLength(value, 1, 200) {
-			errors["author"] = "must be 1-200 characters"
+			errors["author"] = FieldError{domain.ViolationInvalidLength, "must be 1-200 characters"}
+		}
+	}
+
+	if input.Authors != nil {
+		if len(*input.Authors) == 0 {
+			errors["authors"] = FieldError{domain.ViolationRequired, "must include at least one author"}
 		}
+		toBookAuthors(*input.Authors, errors)
+	}
+	if input.CategoryIDs != nil {
+		toBookCategories(*input.CategoryIDs, errors)
+	}
+	if input.Tags != nil {
+		normalizeTags(*input.Tags, errors)
+	}
+	if input.Prices != nil {
+		toBookPrices(*input.Prices, "", errors)
 	}
 
-	if input.Price != nil && *input.Price < 0 {
-		errors["price"] = "must be >= 0"
+	if input.Price != nil {
+		// Precision is checked against the resulting currency once the
+		// stored book is known.
+		if price, err := domain.ParseDecimal(*input.Price); err != nil {
+			errors["price"] = moneyError(err)
+		} else if price.Unscaled < 0 {
+			errors["price"] = FieldError{domain.ViolationOutOfRange, "must be >= 0"}
+		}
 	}
 
 	if input.Currency != nil {
 		value := normalizeCurrency(*input.Currency)
 		if len(value) != 3 || strings.ToUpper(value) != value {
-			errors["currency"] = "must be ISO 4217 code"
+			errors["currency"] = FieldError{domain.ViolationInvalidFormat, "must be ISO 4217 code"}
 		}
 	}
 
 	if input.Stock != nil && *input.Stock < 0 {
-		errors["stock"] = "must be >= 0"
+		errors["stock"] = FieldError{domain.ViolationOutOfRange, "must be >= 0"}
+	}
+
+	if input.ISBN != nil {
+		if _, err := normalizeISBN(*input.ISBN); err != nil {
+			errors["isbn"] = isbnError(err)
+		}
 	}
 
 	if len(errors) > 0 {
@@ -236,6 +969,97 @@ func validateBookUpdateInput(input BookUpdateInput) error {
 	return nil
 }
 
+// parsePriceFilter parses an optional decimal price bound, recording a
+// validation error under field when it is malformed or negative.
+func parsePriceFilter(value, field string, errors map[string]FieldError) *domain.Decimal {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	price, err := domain.ParseDecimal(value)
+	if err != nil {
+		errors[field] = moneyError(err)
+		return nil
+	}
+	if price.Unscaled < 0 {
+		errors[field] = FieldError{domain.ViolationOutOfRange, "must be >= 0"}
+		return nil
+	}
+	return &price
+}
+
+func toBookQuery(input BookListInput) (domain.BookQuery, error) {
+	errors := make(map[string]FieldError)
+	query := domain.BookQuery{
+		Author:         strings.TrimSpace(input.Author),
+		Tag:            domain.NormalizeTag(input.Tag),
+		InStock:        input.InStock,
+		CreatedAfter:   input.CreatedAfter,
+		CreatedBefore:  input.CreatedBefore,
+		UpdatedAfter:   input.UpdatedAfter,
+		UpdatedBefore:  input.UpdatedBefore,
+		IncludeDeleted: input.IncludeDeleted,
+		Limit:          input.Limit,
+	}
+
+	if category := strings.TrimSpace(input.Category); category != "" {
+		id, err := uuid.Parse(category)
+		if err != nil {
+			errors["category"] = FieldError{domain.ViolationInvalid, "invalid"}
+		} else {
+			query.CategoryID = &id
+		}
+	}
+
+	if currency := strings.TrimSpace(input.Currency); currency != "" {
+		query.Currency = strings.ToUpper(currency)
+		if len(query.Currency) != 3 {
+			errors["currency"] = FieldError{domain.ViolationInvalidFormat, "must be ISO 4217 code"}
+		}
+	}
+
+	query.MinPrice = parsePriceFilter(input.MinPrice, "minPrice", errors)
+	query.MaxPrice = parsePriceFilter(input.MaxPrice, "maxPrice", errors)
+	if query.MinPrice != nil && query.MaxPrice != nil && query.MinPrice.Cmp(*query.MaxPrice) > 0 {
+		errors["maxPrice"] = FieldError{domain.ViolationOutOfRange, "must be >= minPrice"}
+	}
+	if query.CreatedAfter != nil && query.CreatedBefore != nil && !query.CreatedAfter.Before(*query.CreatedBefore) {
+		errors["createdBefore"] = FieldError{domain.ViolationOutOfRange, "must be after createdAfter"}
+	}
+	if query.UpdatedAfter != nil && query.UpdatedBefore != nil && !query.UpdatedAfter.Before(*query.UpdatedBefore) {
+		errors["updatedBefore"] = FieldError{domain.ViolationOutOfRange, "must be after updatedAfter"}
+	}
+
+	sorts, err := domain.ParseBookSort(input.Sort)
+	if err != nil {
+		errors["sort"] = FieldError{domain.ViolationInvalid, err.Error()}
+	}
+	query.Sort = sorts
+
+	if query.Limit == 0 {
+		query.Limit = domain.DefaultPageLimit
+	} else if query.Limit < 1 || query.Limit > domain.MaxPageLimit {
+		errors["limit"] = FieldError{domain.ViolationOutOfRange, fmt.Sprintf("must be between 1 and %d", domain.MaxPageLimit)}
+	}
+
+	if cursor := strings.TrimSpace(input.Cursor); cursor != "" {
+		decoded, err := domain.DecodeCursor(cursor)
+		switch {
+		case err != nil:
+			errors["cursor"] = FieldError{domain.ViolationInvalid, "invalid"}
+		case sorts != nil && (decoded.Sort != domain.FormatBookSort(sorts) || len(decoded.Values) != len(sorts)):
+			errors["cursor"] = FieldError{domain.ViolationInvalid, "does not match sort"}
+		default:
+			query.Cursor = &decoded
+		}
+	}
+
+	if len(errors) > 0 {
+		return domain.BookQuery{}, ValidationError{Fields: errors}
+	}
+	return query, nil
+}
+
 func withinLength(value string, min, max int) bool {
 	length := utf8.RuneCountInString(value)
 	return length >= min && length <= max
@@ -247,6 +1071,28 @@ func (noopBookEventPublisher) PublishBookCreated(context.Context, domain.Book) e
 	return nil
 }
 
+func (noopBookEventPublisher) PublishBookUpdated(context.Context, domain.BookUpdate) error {
+	return nil
+}
+
+func (noopBookEventPublisher) PublishBookDeleted(context.Context, domain.Book) error {
+	return nil
+}
+
+func (noopBookEventPublisher) PublishStockChanged(context.Context, domain.StockAdjustment) error {
+	return nil
+}
+
+type noopRevisionStore struct{}
+
+func (noopRevisionStore) Append(context.Context, domain.BookRevision) error {
+	return nil
+}
+
+func (noopRevisionStore) List(context.Context, domain.BookRevisionQuery) (domain.BookRevisionPage, error) {
+	return domain.BookRevisionPage{}, nil
+}
+
 // BookServiceOption configures BookService behavior.
 type BookServiceOption func(*BookService)
 
@@ -259,3 +1105,31 @@ func WithBookEventPublisher(publisher BookEventPublisher) BookServiceOption {
 		service.publisher = publisher
 	}
 }
+
+// WithOutbox stores book events in the outbox within the same transaction as
+// the write that produced them, instead of publishing them directly. The
+// outbox relay delivers them to the publisher afterwards.
+func WithOutbox(tx Transactor, outbox OutboxStore) BookServiceOption {
+	return func(service *BookService) {
+		if tx == nil || outbox == nil {
+			return
+		}
+		service.tx = tx
+		service.outbox = outbox
+	}
+}
+
+// WithRevisions records a revision for every create, update, delete and
+// restore. When tx is non-nil the revision is written in the same
+// transaction as the change.
+func WithRevisions(tx Transactor, store RevisionStore) BookServiceOption {
+	return func(service *BookService) {
+		if store == nil {
+			return
+		}
+		if tx != nil {
+			service.tx = tx
+		}
+		service.revisions = store
+	}
+}
@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -21,7 +23,7 @@ func TestBookServiceCreate_Success(t *testing.T) {
 	input := BookCreateInput{
 		Title:    "  The Go Programming Language ",
 		Author:   "Alan Donovan",
-		Price:    49.99,
+		Price:    "49.99",
 		Currency: "",
 		Stock:    5,
 	}
@@ -31,9 +33,10 @@ func TestBookServiceCreate_Success(t *testing.T) {
 	require.NotEqual(t, uuid.Nil, book.ID)
 	require.Equal(t, "The Go Programming Language", book.Title)
 	require.Equal(t, "Alan Donovan", book.Author)
-	require.Equal(t, "USD", book.Currency)
+	require.Equal(t, domain.Money{Amount: 4999, Currency: "USD"}, book.Price)
 	require.Equal(t, now, book.CreatedAt)
 	require.Equal(t, now, book.UpdatedAt)
+	require.Equal(t, int64(1), book.Version)
 
 	stored, ok := mockRepo.store[book.ID]
 	require.True(t, ok)
@@ -45,7 +48,7 @@ func TestBookServiceCreate_ValidationError(t *testing.T) {
 	svc := NewBookService(mockRepo)
 
 	_, err := svc.CreateBook(context.Background(), BookCreateInput{
-		Price:    -1,
+		Price:    "-1",
 		Currency: "US",
 		Stock:    -5,
 	})
@@ -70,8 +73,7 @@ func TestBookServiceUpdate_PartialSuccess(t *testing.T) {
 		ID:        uuid.New(),
 		Title:     "Original",
 		Author:    "Author",
-		Price:     10,
-		Currency:  "USD",
+		Price:     domain.Money{Amount: 1000, Currency: "USD"},
 		Stock:     5,
 		CreatedAt: now.Add(-time.Hour),
 		UpdatedAt: now.Add(-time.Hour),
@@ -79,7 +81,7 @@ func TestBookServiceUpdate_PartialSuccess(t *testing.T) {
 	mockRepo.store[existing.ID] = existing
 
 	newTitle := "Updated Title"
-	newPrice := 12.5
+	newPrice := "12.5"
 	input := BookUpdateInput{
 		Title: &newTitle,
 		Price: &newPrice,
@@ -88,11 +90,137 @@ func TestBookServiceUpdate_PartialSuccess(t *testing.T) {
 	updated, err := svc.UpdateBook(context.Background(), existing.ID, input)
 	require.NoError(t, err)
 	require.Equal(t, newTitle, updated.Title)
-	require.Equal(t, newPrice, updated.Price)
+	require.Equal(t, domain.Money{Amount: 1250, Currency: "USD"}, updated.Price)
 	require.Equal(t, existing.Author, updated.Author)
 	require.True(t, updated.UpdatedAt.After(existing.UpdatedAt))
 }
 
+func TestBookServicePrice_CurrencyPrecision(t *testing.T) {
+	svc := NewBookService(newMockBookRepo())
+	ctx := context.Background()
+
+	book, err := svc.CreateBook(ctx, BookCreateInput{Title: "Dune", Author: "Frank Herbert", Price: "1.250", Currency: "kwd", Stock: 1})
+	require.NoError(t, err)
+	require.Equal(t, domain.Money{Amount: 1250, Currency: "KWD"}, book.Price)
+
+	_, err = svc.CreateBook(ctx, BookCreateInput{Title: "Dune", Author: "Frank Herbert", Price: "1500.5", Currency: "JPY", Stock: 1})
+	var validationErr ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	require.Equal(t, "must have at most 0 decimal places for JPY", validationErr.Fields["price"].Message)
+
+	jpy := "JPY"
+	_, err = svc.UpdateBook(ctx, book.ID, BookUpdateInput{Currency: &jpy})
+	require.ErrorAs(t, err, &validationErr)
+	require.Contains(t, validationErr.Fields, "price")
+
+	whole := "1500"
+	updated, err := svc.UpdateBook(ctx, book.ID, BookUpdateInput{Price: &whole, Currency: &jpy})
+	require.NoError(t, err)
+	require.Equal(t, "1500", updated.Price.String())
+}
+
+func TestBookServiceISBN(t *testing.T) {
+	svc := NewBookService(newMockBookRepo())
+	ctx := context.Background()
+
+	// ISBN-10 0-441-17271-7 is ISBN-13 978-0-441-17271-9.
+	book, err := svc.CreateBook(ctx, BookCreateInput{Title: "Dune", Author: "Frank Herbert", ISBN: "0-441-17271-7", Price: "9.99", Stock: 1})
+	require.NoError(t, err)
+	require.Equal(t, "9780441172719", book.ISBN)
+
+	found, err := svc.GetBookByISBN(ctx, "978-0441172719")
+	require.NoError(t, err)
+	require.Equal(t, book.ID, found.ID)
+	_, err = svc.GetBookByISBN(ctx, "044117271x7")
+	var validationErr ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	_, err = svc.GetBookByISBN(ctx, "9780306406157")
+	require.ErrorIs(t, err, domain.ErrBookNotFound)
+
+	for isbn, message := range map[string]string{
+		"0441172718":     "has an invalid check digit",
+		"9780441172710":  "has an invalid check digit",
+		"9770441172719":  "must start with 978 or 979",
+		"97804411727":    "must be an ISBN-10 or ISBN-13",
+		"ISBN0441172717": "must be an ISBN-10 or ISBN-13",
+	} {
+		_, err := svc.CreateBook(ctx, BookCreateInput{Title: "Dune", Author: "Frank Herbert", ISBN: isbn, Price: "9.99"})
+		require.ErrorAs(t, err, &validationErr, isbn)
+		require.Equal(t, message, validationErr.Fields["isbn"].Message, isbn)
+	}
+
+	// ISBN-10 check digit X.
+	isbn := "080442957x"
+	updated, err := svc.UpdateBook(ctx, book.ID, BookUpdateInput{ISBN: &isbn})
+	require.NoError(t, err)
+	require.Equal(t, "9780804429573", updated.ISBN)
+	invalid := "0804429579"
+	_, err = svc.UpdateBook(ctx, book.ID, BookUpdateInput{ISBN: &invalid})
+	require.ErrorAs(t, err, &validationErr)
+	require.Contains(t, validationErr.Fields, "isbn")
+	none := ""
+	updated, err = svc.UpdateBook(ctx, book.ID, BookUpdateInput{ISBN: &none})
+	require.NoError(t, err)
+	require.Empty(t, updated.ISBN)
+
+	results, err := svc.CreateBooks(ctx, []BookCreateInput{
+		{Title: "A", Author: "B", ISBN: "9780306406157", Price: "1"},
+		{Title: "C", Author: "D", ISBN: "0-306-40615-2", Price: "1"},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, results[0].Book)
+	require.Equal(t, map[string]FieldError{"isbn": {domain.ViolationDuplicate, "duplicates item 0"}}, results[1].Errors)
+}
+
+func TestBookServiceCreateBooks_TakenISBN(t *testing.T) {
+	ctx := context.Background()
+	svc := NewBookService(repo.NewMemoryBookRepository())
+
+	_, err := svc.CreateBook(ctx, BookCreateInput{Title: "Dune", Author: "Frank Herbert", ISBN: "0-441-17271-7", Price: "9.99"})
+	require.NoError(t, err)
+	deleted, err := svc.CreateBook(ctx, BookCreateInput{Title: "Emma", Author: "Jane Austen", ISBN: "9780306406157", Price: "5"})
+	require.NoError(t, err)
+	require.NoError(t, svc.DeleteBook(ctx, deleted.ID, nil))
+
+	results, err := svc.CreateBooks(ctx, []BookCreateInput{
+		{Title: "Dune Messiah", Author: "Frank Herbert", ISBN: "9780441172719", Price: "9.99"},
+		{Title: "Persuasion", Author: "Jane Austen", Price: "5"},
+		{Title: "Emma", Author: "Jane Austen", ISBN: "0-306-40615-2", Price: "5"},
+		{Title: "Foundation", Author: "Isaac Asimov", ISBN: "0-8044-2957-X", Price: "8"},
+	})
+	require.NoError(t, err)
+	duplicate := map[string]FieldError{"isbn": {domain.ViolationDuplicate, domain.ErrDuplicateISBN.Message}}
+	require.Equal(t, duplicate, results[0].Errors)
+	require.Nil(t, results[0].Book)
+	require.NotNil(t, results[1].Book)
+	require.Equal(t, duplicate, results[2].Errors)
+	require.NotNil(t, results[3].Book)
+	require.Equal(t, "9780804429573", results[3].Book.ISBN)
+}
+
+func TestBookServiceUpdate_VersionConflict(t *testing.T) {
+	mockRepo := newMockBookRepo()
+	svc := NewBookService(mockRepo)
+	bookID := uuid.New()
+	mockRepo.store[bookID] = domain.Book{ID: bookID, Title: "Original", Version: 3}
+
+	title := "First writer"
+	current := int64(3)
+	updated, err := svc.UpdateBook(context.Background(), bookID, BookUpdateInput{Title: &title, ExpectedVersion: &current})
+	require.NoError(t, err)
+	require.Equal(t, int64(4), updated.Version)
+	require.Equal(t, int64(4), mockRepo.store[bookID].Version)
+
+	title = "Second writer"
+	_, err = svc.UpdateBook(context.Background(), bookID, BookUpdateInput{Title: &title, ExpectedVersion: &current})
+	require.ErrorIs(t, err, domain.ErrVersionConflict)
+	require.Equal(t, "First writer", mockRepo.store[bookID].Title)
+
+	err = svc.DeleteBook(context.Background(), bookID, &current)
+	require.ErrorIs(t, err, domain.ErrVersionConflict)
+	require.NoError(t, svc.DeleteBook(context.Background(), bookID, &updated.Version))
+}
+
 func TestBookServiceUpdate_MissingBody(t *testing.T) {
 	mockRepo := newMockBookRepo()
 	svc := NewBookService(mockRepo)
@@ -105,8 +233,416 @@ func TestBookServiceUpdate_MissingBody(t *testing.T) {
 	require.True(t, ok)
 }
 
+func TestBookServiceList_DefaultsAndCursor(t *testing.T) {
+	mockRepo := newMockBookRepo()
+	svc := NewBookService(mockRepo)
+
+	_, err := svc.ListBooks(context.Background(), BookListInput{})
+	require.NoError(t, err)
+	require.Equal(t, domain.DefaultPageLimit, mockRepo.lastListQuery.Limit)
+	require.Equal(t, domain.DefaultBookSort, mockRepo.lastListQuery.Sort)
+	require.Nil(t, mockRepo.lastListQuery.Cursor)
+
+	sorts := []domain.BookSort{{Field: domain.SortByPrice, Desc: true}, {Field: domain.SortByTitle}}
+	book := domain.Book{ID: uuid.New(), Title: "Go", Price: domain.Money{Amount: 1250, Currency: "USD"}}
+	cursor := domain.NewCursor(book, sorts, true)
+
+	_, err = svc.ListBooks(context.Background(), BookListInput{
+		Sort:   "-price,title",
+		Limit:  5,
+		Cursor: cursor.Encode(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, 5, mockRepo.lastListQuery.Limit)
+	require.Equal(t, sorts, mockRepo.lastListQuery.Sort)
+	require.NotNil(t, mockRepo.lastListQuery.Cursor)
+	require.Equal(t, cursor, *mockRepo.lastListQuery.Cursor)
+}
+
+func TestBookServiceList_Filters(t *testing.T) {
+	mockRepo := newMockBookRepo()
+	svc := NewBookService(mockRepo)
+	after := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := svc.ListBooks(context.Background(), BookListInput{
+		Author:       "  Donovan ",
+		Currency:     "eur",
+		MinPrice:     "5",
+		MaxPrice:     "20.00",
+		InStock:      true,
+		CreatedAfter: &after,
+	})
+	require.NoError(t, err)
+
+	query := mockRepo.lastListQuery
+	require.Equal(t, "Donovan", query.Author)
+	require.Equal(t, "EUR", query.Currency)
+	require.Equal(t, &domain.Decimal{Unscaled: 5}, query.MinPrice)
+	require.Equal(t, &domain.Decimal{Unscaled: 2000, Scale: 2}, query.MaxPrice)
+	require.True(t, query.InStock)
+	require.Equal(t, &after, query.CreatedAfter)
+}
+
+func TestBookServiceExport(t *testing.T) {
+	mockRepo := newMockBookRepo()
+	svc := NewBookService(mockRepo)
+	book, err := svc.CreateBook(context.Background(), BookCreateInput{Title: "Dune", Author: "Frank Herbert", Price: "9.99", Stock: 1})
+	require.NoError(t, err)
+
+	export, err := svc.ExportBooks(BookListInput{Currency: "usd", Sort: "-price", Limit: 5, Cursor: "ignored"})
+	require.NoError(t, err)
+	var exported []domain.Book
+	require.NoError(t, export.Each(context.Background(), func(b domain.Book) error {
+		exported = append(exported, b)
+		return nil
+	}))
+	require.Equal(t, []domain.Book{book}, exported)
+	require.Equal(t, "USD", mockRepo.lastListQuery.Currency)
+	require.Zero(t, mockRepo.lastListQuery.Limit)
+	require.Nil(t, mockRepo.lastListQuery.Cursor)
+
+	_, err = svc.ExportBooks(BookListInput{MinPrice: "abc"})
+	var validationErr ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	require.Contains(t, validationErr.Fields, "minPrice")
+}
+
+func TestBookServiceList_ValidationError(t *testing.T) {
+	svc := NewBookService(newMockBookRepo())
+	before := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	after := before.Add(time.Hour)
+	otherSort := domain.NewCursor(domain.Book{ID: uuid.New()}, domain.DefaultBookSort, false)
+
+	_, err := svc.ListBooks(context.Background(), BookListInput{
+		Currency:      "EURO",
+		MinPrice:      "20",
+		MaxPrice:      "5.5",
+		UpdatedAfter:  &after,
+		UpdatedBefore: &before,
+		Sort:          "-price",
+		Limit:         domain.MaxPageLimit + 1,
+		Cursor:        otherSort.Encode(),
+	})
+	require.Error(t, err)
+
+	validationErr, ok := err.(ValidationError)
+	require.True(t, ok)
+	require.Contains(t, validationErr.Fields, "currency")
+	require.Contains(t, validationErr.Fields, "maxPrice")
+	require.Contains(t, validationErr.Fields, "updatedBefore")
+	require.Contains(t, validationErr.Fields, "limit")
+	require.Contains(t, validationErr.Fields, "cursor")
+
+	_, err = svc.ListBooks(context.Background(), BookListInput{Sort: "isbn,-title", Cursor: "not-a-cursor"})
+	validationErr, ok = err.(ValidationError)
+	require.True(t, ok)
+	require.Contains(t, validationErr.Fields, "sort")
+	require.Contains(t, validationErr.Fields, "cursor")
+}
+
+func TestBookServiceSearch(t *testing.T) {
+	mockRepo := newMockBookRepo()
+	svc := NewBookService(mockRepo)
+
+	_, err := svc.SearchBooks(context.Background(), BookSearchInput{Query: "  go programming "})
+	require.NoError(t, err)
+	require.Equal(t, "go programming", mockRepo.lastSearchQuery.Query)
+	require.Equal(t, domain.DefaultPageLimit, mockRepo.lastSearchQuery.Limit)
+
+	_, err = svc.SearchBooks(context.Background(), BookSearchInput{Query: "  ", Offset: -1})
+	validationErr, ok := err.(ValidationError)
+	require.True(t, ok)
+	require.Contains(t, validationErr.Fields, "q")
+	require.Contains(t, validationErr.Fields, "offset")
+}
+
+func TestBookServiceDeleteRestorePurge(t *testing.T) {
+	mockRepo := newMockBookRepo()
+	svc := NewBookService(mockRepo)
+	now := time.Date(2025, 11, 10, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	book, err := svc.CreateBook(context.Background(), BookCreateInput{Title: "Dune", Author: "Frank Herbert", Price: "9.99", Stock: 1})
+	require.NoError(t, err)
+
+	require.NoError(t, svc.DeleteBook(context.Background(), book.ID, nil))
+	_, err = svc.GetBook(context.Background(), book.ID)
+	require.ErrorIs(t, err, domain.ErrBookNotFound)
+	require.ErrorIs(t, svc.DeleteBook(context.Background(), book.ID, nil), domain.ErrBookNotFound)
+
+	restored, err := svc.RestoreBook(context.Background(), book.ID)
+	require.NoError(t, err)
+	require.Nil(t, restored.DeletedAt)
+	require.Equal(t, int64(3), restored.Version)
+	_, err = svc.RestoreBook(context.Background(), book.ID)
+	require.ErrorIs(t, err, domain.ErrBookNotDeleted)
+
+	require.NoError(t, svc.DeleteBook(context.Background(), book.ID, nil))
+	purged, err := svc.PurgeDeletedBooks(context.Background(), 24*time.Hour)
+	require.NoError(t, err)
+	require.Zero(t, purged)
+
+	now = now.Add(48 * time.Hour)
+	purged, err = svc.PurgeDeletedBooks(context.Background(), 24*time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), purged)
+	require.Empty(t, mockRepo.store)
+}
+
+func TestBookServiceCreate_WritesOutbox(t *testing.T) {
+	mockRepo := newMockBookRepo()
+	outbox := &mockOutbox{}
+	publisher := &mockPublisher{}
+	svc := NewBookService(mockRepo,
+		WithBookEventPublisher(publisher),
+		WithOutbox(mockTransactor{}, outbox),
+	)
+
+	ctx := domain.ContextWithTenant(context.Background(), "shop-a")
+	book, err := svc.CreateBook(ctx, BookCreateInput{Title: "Dune", Author: "Frank Herbert", Price: "9.99", Stock: 1})
+	require.NoError(t, err)
+	require.Len(t, outbox.events, 1)
+	require.Equal(t, domain.EventTypeBookCreated, outbox.events[0].Type)
+	require.Equal(t, book.ID, outbox.events[0].AggregateID)
+	require.Equal(t, "shop-a", outbox.events[0].Tenant)
+	require.Empty(t, publisher.created, "events must only be published by the relay")
+}
+
+func TestBookServiceEvents(t *testing.T) {
+	t.Run("published directly without an outbox", func(t *testing.T) {
+		publisher := &mockPublisher{}
+		svc := NewBookService(newMockBookRepo(), WithBookEventPublisher(publisher))
+		ctx := context.Background()
+
+		book, err := svc.CreateBook(ctx, BookCreateInput{Title: "Dune", Author: "Frank Herbert", Price: "9.99", Stock: 1})
+		require.NoError(t, err)
+
+		title := "Dune Messiah"
+		_, err = svc.UpdateBook(ctx, book.ID, BookUpdateInput{Title: &title})
+		require.NoError(t, err)
+		_, err = svc.UpdateBook(ctx, book.ID, BookUpdateInput{Title: &title})
+		require.NoError(t, err)
+		require.Len(t, publisher.updated, 1, "updates changing nothing are not announced")
+		require.Equal(t, []domain.FieldChange{{Field: "title", Before: "Dune", After: "Dune Messiah"}}, publisher.updated[0].Changes)
+		require.Equal(t, int64(2), publisher.updated[0].Book.Version)
+
+		adjustment, _, err := svc.AdjustStock(ctx, book.ID, StockAdjustmentInput{Delta: -1, Reason: "sale"})
+		require.NoError(t, err)
+		require.Equal(t, []domain.StockAdjustment{adjustment}, publisher.stockChanges)
+
+		require.NoError(t, svc.DeleteBook(ctx, book.ID, nil))
+		require.Len(t, publisher.deleted, 1)
+		require.Equal(t, book.ID, publisher.deleted[0].ID)
+		require.NotNil(t, publisher.deleted[0].DeletedAt)
+
+		_, err = svc.RestoreBook(ctx, book.ID)
+		require.NoError(t, err)
+		require.Len(t, publisher.updated, 2)
+		require.Equal(t, "deletedAt", publisher.updated[1].Changes[0].Field)
+		require.Nil(t, publisher.updated[1].Changes[0].After)
+	})
+
+	t.Run("enqueued with an outbox", func(t *testing.T) {
+		outbox := &mockOutbox{}
+		publisher := &mockPublisher{}
+		svc := NewBookService(newMockBookRepo(),
+			WithBookEventPublisher(publisher),
+			WithOutbox(mockTransactor{}, outbox),
+		)
+		ctx := context.Background()
+
+		book, err := svc.CreateBook(ctx, BookCreateInput{Title: "Dune", Author: "Frank Herbert", Price: "9.99", Stock: 1})
+		require.NoError(t, err)
+		price := "10.99"
+		_, err = svc.UpdateBook(ctx, book.ID, BookUpdateInput{Price: &price})
+		require.NoError(t, err)
+		_, _, err = svc.AdjustStock(ctx, book.ID, StockAdjustmentInput{Delta: 4, Reason: "restock"})
+		require.NoError(t, err)
+		require.NoError(t, svc.DeleteBook(ctx, book.ID, nil))
+
+		var types []string
+		for _, event := range outbox.events {
+			require.Equal(t, book.ID, event.AggregateID)
+			types = append(types, event.Type)
+		}
+		require.Equal(t, []string{
+			domain.EventTypeBookCreated,
+			domain.EventTypeBookUpdated,
+			domain.EventTypeStockChanged,
+			domain.EventTypeBookDeleted,
+		}, types)
+		require.Empty(t, publisher.updated, "events must only be published by the relay")
+		require.Empty(t, publisher.stockChanges)
+		require.Empty(t, publisher.deleted)
+	})
+}
+
+func TestBookServiceCreateBooks(t *testing.T) {
+	mockRepo := newMockBookRepo()
+	publisher := &mockPublisher{}
+	svc := NewBookService(mockRepo, WithBookEventPublisher(publisher))
+
+	results, err := svc.CreateBooks(context.Background(), []BookCreateInput{
+		{Title: "Dune", Author: "Frank Herbert", Price: "9.99", Stock: 1},
+		{Title: "", Author: "Nobody", Price: "1", Stock: 1},
+		{Title: "Neuromancer", Author: "William Gibson", Price: "1500", Currency: "JPY", Stock: 2},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	require.Equal(t, "Dune", results[0].Book.Title)
+	require.Nil(t, results[1].Book)
+	require.Equal(t, 1, results[1].Index)
+	require.Contains(t, results[1].Errors, "title")
+	require.Equal(t, "Neuromancer", results[2].Book.Title)
+	require.Len(t, mockRepo.store, 2)
+	require.Len(t, publisher.created, 2)
+
+	_, err = svc.CreateBooks(context.Background(), nil)
+	var validationErr ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	require.Contains(t, validationErr.Fields, "items")
+
+	_, err = svc.CreateBooks(context.Background(), make([]BookCreateInput, MaxBatchCreateItems+1))
+	require.ErrorAs(t, err, &validationErr)
+}
+
+func TestBookServiceImportBook(t *testing.T) {
+	mockRepo := newMockBookRepo()
+	svc := NewBookService(mockRepo)
+	ctx := context.Background()
+	input := BookCreateInput{Title: "Dune", Author: "Frank Herbert", Price: "9.99", Stock: 1}
+
+	_, action, err := svc.ImportBook(ctx, input, true)
+	require.NoError(t, err)
+	require.Equal(t, ImportCreated, action)
+	require.Empty(t, mockRepo.store)
+
+	created, action, err := svc.ImportBook(ctx, input, false)
+	require.NoError(t, err)
+	require.Equal(t, ImportCreated, action)
+
+	input.Title = "DUNE"
+	_, action, err = svc.ImportBook(ctx, input, false)
+	require.NoError(t, err)
+	require.Equal(t, ImportUnchanged, action)
+
+	input.Stock = 7
+	updated, action, err := svc.ImportBook(ctx, input, false)
+	require.NoError(t, err)
+	require.Equal(t, ImportUpdated, action)
+	require.Equal(t, created.ID, updated.ID)
+	require.Equal(t, "Dune", updated.Title)
+	require.Equal(t, 7, mockRepo.store[created.ID].Stock)
+
+	// The book found by title and author takes the ISBN; afterwards the ISBN
+	// alone identifies it, and a different ISBN is another edition.
+	input.ISBN = "0441172717"
+	updated, action, err = svc.ImportBook(ctx, input, false)
+	require.NoError(t, err)
+	require.Equal(t, ImportUpdated, action)
+	require.Equal(t, "9780441172719", updated.ISBN)
+	_, action, err = svc.ImportBook(ctx, BookCreateInput{Title: "Dune (Deluxe)", Author: "F. Herbert", ISBN: "9780441172719", Price: "9.99", Stock: 7}, false)
+	require.NoError(t, err)
+	require.Equal(t, ImportUnchanged, action)
+	input.ISBN = "9780593099322"
+	other, action, err := svc.ImportBook(ctx, input, false)
+	require.NoError(t, err)
+	require.Equal(t, ImportCreated, action)
+	require.NotEqual(t, created.ID, other.ID)
+
+	_, _, err = svc.ImportBook(ctx, BookCreateInput{Title: "Dune", Price: "abc"}, true)
+	var validationErr ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	require.Contains(t, validationErr.Fields, "author")
+	require.Contains(t, validationErr.Fields, "price")
+}
+
+func TestBookServiceHistory(t *testing.T) {
+	svc := NewBookService(newMockBookRepo(), WithRevisions(nil, repo.NewMemoryRevisionRepository()))
+	now := time.Date(2025, 11, 10, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	ctx := domain.ContextWithActor(context.Background(), "alice")
+
+	book, err := svc.CreateBook(ctx, BookCreateInput{Title: "Dune", Author: "Frank Herbert", Price: "9.99", Stock: 1})
+	require.NoError(t, err)
+	price := "12.5"
+	_, err = svc.UpdateBook(ctx, book.ID, BookUpdateInput{Price: &price})
+	require.NoError(t, err)
+	require.NoError(t, svc.DeleteBook(context.Background(), book.ID, nil))
+
+	page, err := svc.ListBookHistory(ctx, book.ID, BookHistoryInput{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page.Revisions, 2)
+	require.Equal(t, domain.RevisionDeleted, page.Revisions[0].Action)
+	require.Equal(t, domain.AnonymousActor, page.Revisions[0].Actor)
+	require.Equal(t, "deletedAt", page.Revisions[0].Changes[0].Field)
+	require.Equal(t, domain.RevisionUpdated, page.Revisions[1].Action)
+	require.Equal(t, "alice", page.Revisions[1].Actor)
+	require.Equal(t, []domain.FieldChange{{Field: "price", Before: "9.99", After: "12.50"}}, page.Revisions[1].Changes)
+	require.NotEmpty(t, page.NextCursor)
+
+	page, err = svc.ListBookHistory(ctx, book.ID, BookHistoryInput{Limit: 2, Cursor: page.NextCursor})
+	require.NoError(t, err)
+	require.Len(t, page.Revisions, 1)
+	require.Equal(t, domain.RevisionCreated, page.Revisions[0].Action)
+	require.Len(t, page.Revisions[0].Changes, 10)
+	require.Empty(t, page.NextCursor)
+
+	_, err = svc.ListBookHistory(ctx, uuid.New(), BookHistoryInput{})
+	require.ErrorIs(t, err, domain.ErrBookNotFound)
+
+	_, err = svc.ListBookHistory(ctx, book.ID, BookHistoryInput{Cursor: "!"})
+	validationErr, ok := err.(ValidationError)
+	require.True(t, ok)
+	require.Contains(t, validationErr.Fields, "cursor")
+}
+
+type mockTransactor struct{}
+
+func (mockTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type mockOutbox struct {
+	events []domain.OutboxEvent
+}
+
+func (m *mockOutbox) Enqueue(_ context.Context, event domain.OutboxEvent) error {
+	m.events = append(m.events, event)
+	return nil
+}
+
+type mockPublisher struct {
+	created      []domain.Book
+	updated      []domain.BookUpdate
+	deleted      []domain.Book
+	stockChanges []domain.StockAdjustment
+}
+
+func (m *mockPublisher) PublishBookCreated(_ context.Context, book domain.Book) error {
+	m.created = append(m.created, book)
+	return nil
+}
+
+func (m *mockPublisher) PublishBookUpdated(_ context.Context, update domain.BookUpdate) error {
+	m.updated = append(m.updated, update)
+	return nil
+}
+
+func (m *mockPublisher) PublishBookDeleted(_ context.Context, book domain.Book) error {
+	m.deleted = append(m.deleted, book)
+	return nil
+}
+
+func (m *mockPublisher) PublishStockChanged(_ context.Context, adjustment domain.StockAdjustment) error {
+	m.stockChanges = append(m.stockChanges, adjustment)
+	return nil
+}
+
 type mockBookRepo struct {
-	store map[uuid.UUID]domain.Book
+	store           map[uuid.UUID]domain.Book
+	lastListQuery   domain.BookQuery
+	lastSearchQuery domain.BookSearchQuery
 }
 
 func newMockBookRepo() *mockBookRepo {
@@ -118,34 +654,138 @@ func (m *mockBookRepo) Create(_ context.Context, book domain.Book) error {
 	return nil
 }
 
+func (m *mockBookRepo) CreateMany(_ context.Context, books []domain.Book) error {
+	for _, book := range books {
+		m.store[book.ID] = book
+	}
+	return nil
+}
+
+func (m *mockBookRepo) TakenISBNs(_ context.Context, isbns []string) ([]string, error) {
+	var taken []string
+	for _, book := range m.store {
+		if book.ISBN != "" && slices.Contains(isbns, book.ISBN) {
+			taken = append(taken, book.ISBN)
+		}
+	}
+	return taken, nil
+}
+
 func (m *mockBookRepo) Get(_ context.Context, id uuid.UUID) (domain.Book, error) {
 	book, ok := m.store[id]
-	if !ok {
-		return domain.Book{}, repo.ErrNotFound
+	if !ok || book.DeletedAt != nil {
+		return domain.Book{}, domain.ErrBookNotFound
 	}
 	return book, nil
 }
 
-func (m *mockBookRepo) List(_ context.Context) ([]domain.Book, error) {
+func (m *mockBookRepo) Export(_ context.Context, query domain.BookQuery, fn func(domain.Book) error) error {
+	m.lastListQuery = query
+	for _, book := range m.store {
+		if err := fn(book); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mockBookRepo) GetByTitleAuthor(_ context.Context, title, author string) (domain.Book, error) {
+	for _, book := range m.store {
+		if book.DeletedAt == nil && strings.EqualFold(book.Title, title) && strings.EqualFold(book.Author, author) {
+			return book, nil
+		}
+	}
+	return domain.Book{}, domain.ErrBookNotFound
+}
+
+func (m *mockBookRepo) GetByISBN(_ context.Context, isbn string) (domain.Book, error) {
+	for _, book := range m.store {
+		if book.DeletedAt == nil && book.ISBN == isbn {
+			return book, nil
+		}
+	}
+	return domain.Book{}, domain.ErrBookNotFound
+}
+
+func (m *mockBookRepo) List(_ context.Context, query domain.BookQuery) (domain.BookPage, error) {
+	m.lastListQuery = query
 	var result []domain.Book
 	for _, book := range m.store {
 		result = append(result, book)
 	}
-	return result, nil
+	return domain.BookPage{Books: result}, nil
+}
+
+func (m *mockBookRepo) Search(_ context.Context, query domain.BookSearchQuery) ([]domain.BookSearchResult, error) {
+	m.lastSearchQuery = query
+	return nil, nil
 }
 
 func (m *mockBookRepo) Update(_ context.Context, book domain.Book) error {
-	if _, ok := m.store[book.ID]; !ok {
-		return repo.ErrNotFound
+	stored, ok := m.store[book.ID]
+	if !ok {
+		return domain.ErrBookNotFound
+	}
+	if stored.Version != book.Version {
+		return domain.ErrVersionConflict
 	}
+	book.Version++
 	m.store[book.ID] = book
 	return nil
 }
 
-func (m *mockBookRepo) Delete(_ context.Context, id uuid.UUID) error {
-	if _, ok := m.store[id]; !ok {
-		return repo.ErrNotFound
+func (m *mockBookRepo) AdjustStock(_ context.Context, id uuid.UUID, delta int, updatedAt time.Time) (domain.Book, error) {
+	stored, ok := m.store[id]
+	if !ok || stored.DeletedAt != nil {
+		return domain.Book{}, domain.ErrBookNotFound
 	}
-	delete(m.store, id)
-	return nil
+	if stored.Stock+delta < 0 {
+		return domain.Book{}, domain.ErrInsufficientStock
+	}
+	stored.Stock += delta
+	stored.UpdatedAt = updatedAt
+	stored.Version++
+	m.store[id] = stored
+	return stored, nil
+}
+
+func (m *mockBookRepo) Delete(_ context.Context, id uuid.UUID, expectedVersion *int64, deletedAt time.Time) (domain.Book, error) {
+	stored, ok := m.store[id]
+	if !ok || stored.DeletedAt != nil {
+		return domain.Book{}, domain.ErrBookNotFound
+	}
+	if expectedVersion != nil && stored.Version != *expectedVersion {
+		return domain.Book{}, domain.ErrVersionConflict
+	}
+	stored.DeletedAt = &deletedAt
+	stored.Version++
+	m.store[id] = stored
+	return stored, nil
+}
+
+func (m *mockBookRepo) Restore(_ context.Context, id uuid.UUID, restoredAt time.Time) (domain.Book, time.Time, error) {
+	stored, ok := m.store[id]
+	if !ok {
+		return domain.Book{}, time.Time{}, domain.ErrBookNotFound
+	}
+	if stored.DeletedAt == nil {
+		return domain.Book{}, time.Time{}, domain.ErrBookNotDeleted
+	}
+	deletedAt := *stored.DeletedAt
+	stored.DeletedAt = nil
+	stored.UpdatedAt = restoredAt
+	stored.Version++
+	m.store[id] = stored
+	return stored, deletedAt, nil
+}
+
+func (m *mockBookRepo) Purge(_ context.Context, deletedBefore time.Time) (int64, error) {
+	var purged int64
+	for id, book := range m.store {
+		if book.DeletedAt != nil && book.DeletedAt.Before(deletedBefore) {
+			delete(m.store, id)
+			purged++
+		}
+	}
+	return purged, nil
 }
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/example/bookapi/internal/domain"
+)
+
+// MaxBookCategories bounds the number of categories a book is filed under.
+const MaxBookCategories = 10
+
+var errCategoriesNotConfigured = errors.New("category store is not configured")
+
+// CategoryStore keeps the category tree. Books reference categories through
+// domain.BookCategory links stored by the BookRepository.
+type CategoryStore interface {
+	Create(ctx context.Context, category domain.Category) error
+	Get(ctx context.Context, id uuid.UUID) (domain.Category, error)
+	// List returns every category ordered by name.
+	List(ctx context.Context) ([]domain.Category, error)
+	Update(ctx context.Context, category domain.Category) error
+	// Delete fails with domain.ErrCategoryInUse while the category has
+	// subcategories or books.
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// CategoryInput names a category and places it in the tree. A nil ParentID
+// makes it a top-level category.
+type CategoryInput struct {
+	Name     string
+	ParentID *uuid.UUID
+}
+
+func (s *BookService) CreateCategory(ctx context.Context, input CategoryInput) (domain.Category, error) {
+	if s.categories == nil {
+		return domain.Category{}, errCategoriesNotConfigured
+	}
+	name, err := validateCategoryName(input.Name)
+	if err != nil {
+		return domain.Category{}, err
+	}
+	if input.ParentID != nil {
+		if _, err := s.categories.Get(ctx, *input.ParentID); errors.Is(err, domain.ErrCategoryNotFound) {
+			return domain.Category{}, violation("parentId", domain.ViolationUnknownReference, "unknown category")
+		} else if err != nil {
+			return domain.Category{}, err
+		}
+	}
+
+	now := s.now().UTC()
+	category := domain.Category{ID: uuid.New(), ParentID: input.ParentID, Name: name, CreatedAt: now, UpdatedAt: now}
+	if err := s.categories.Create(ctx, category); err != nil {
+		return domain.Category{}, err
+	}
+	return category, nil
+}
+
+func (s *BookService) GetCategory(ctx context.Context, id uuid.UUID) (domain.Category, error) {
+	if s.categories == nil {
+		return domain.Category{}, domain.ErrCategoryNotFound
+	}
+	return s.categories.Get(ctx, id)
+}
+
+// ListCategories returns the whole category tree as a list ordered by name;
+// clients assemble the tree from the parent IDs.
+func (s *BookService) ListCategories(ctx context.Context) ([]domain.Category, error) {
+	if s.categories == nil {
+		return []domain.Category{}, nil
+	}
+	return s.categories.List(ctx)
+}
+
+// UpdateCategory renames a category and moves it, with its subcategories,
+// under a new parent.
+func (s *BookService) UpdateCategory(ctx context.Context, id uuid.UUID, input CategoryInput) (domain.Category, error) {
+	if s.categories == nil {
+		return domain.Category{}, errCategoriesNotConfigured
+	}
+	name, err := validateCategoryName(input.Name)
+	if err != nil {
+		return domain.Category{}, err
+	}
+
+	var category domain.Category
+	err = s.inTx(ctx, func(ctx context.Context) error {
+		all, err := s.categories.List(ctx)
+		if err != nil {
+			return err
+		}
+		parents := make(map[uuid.UUID]*uuid.UUID, len(all))
+		for _, c := range all {
+			parents[c.ID] = c.ParentID
+			if c.ID == id {
+				category = c
+			}
+		}
+		if category.ID != id {
+			return domain.ErrCategoryNotFound
+		}
+		if input.ParentID != nil {
+			if _, ok := parents[*input.ParentID]; !ok {
+				return violation("parentId", domain.ViolationUnknownReference, "unknown category")
+			}
+			if domain.IsDescendant(parents, *input.ParentID, id) {
+				return violation("parentId", domain.ViolationInvalid, "cannot be the category itself or one of its subcategories")
+			}
+		}
+
+		category.Name = name
+		category.ParentID = input.ParentID
+		category.UpdatedAt = s.now().UTC()
+		return s.categories.Update(ctx, category)
+	})
+	if err != nil {
+		return domain.Category{}, err
+	}
+	return category, nil
+}
+
+// DeleteCategory removes a category without subcategories or books,
+// including soft-deleted ones.
+func (s *BookService) DeleteCategory(ctx context.Context, id uuid.UUID) error {
+	if s.categories == nil {
+		return errCategoriesNotConfigured
+	}
+	return s.categories.Delete(ctx, id)
+}
+
+// linkTaxonomy completes the category and tag links of a book about to be
+// written: every category must exist and gets its name, and missing tags are
+// created. Call it inside the transaction that writes the book.
+func (s *BookService) linkTaxonomy(ctx context.Context, book *domain.Book) error {
+	if len(book.Categories) > 0 {
+		if s.categories == nil {
+			return errCategoriesNotConfigured
+		}
+		invalid := make(map[string]FieldError)
+		for i, link := range book.Categories {
+			category, err := s.categories.Get(ctx, link.CategoryID)
+			if errors.Is(err, domain.ErrCategoryNotFound) {
+				invalid[fmt.Sprintf("categoryIds[%d]", i)] = FieldError{domain.ViolationUnknownReference, "unknown category"}
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			book.Categories[i].Name = category.Name
+		}
+		if len(invalid) > 0 {
+			return ValidationError{Fields: invalid}
+		}
+		slices.SortFunc(book.Categories, func(a, b domain.BookCategory) int {
+			return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+		})
+	}
+
+	if len(book.Tags) > 0 {
+		if s.tags == nil {
+			return errTagsNotConfigured
+		}
+		return s.tags.Ensure(ctx, book.Tags, s.now().UTC())
+	}
+	return nil
+}
+
+// toBookCategories validates category IDs, recording errors under
+// categoryIds[i].
+func toBookCategories(ids []uuid.UUID, errors map[string]FieldError) []domain.BookCategory {
+	if len(ids) > MaxBookCategories {
+		errors["categoryIds"] = FieldError{domain.ViolationInvalidLength, fmt.Sprintf("must include at most %d categories", MaxBookCategories)}
+		return nil
+	}
+
+	var links []domain.BookCategory
+	seen := make(map[uuid.UUID]bool, len(ids))
+	for i, id := range ids {
+		switch {
+		case id == uuid.Nil:
+			errors[fmt.Sprintf("categoryIds[%d]", i)] = FieldError{domain.ViolationRequired, "required"}
+		case seen[id]:
+			errors[fmt.Sprintf("categoryIds[%d]", i)] = FieldError{domain.ViolationDuplicate, "duplicates an earlier category"}
+		}
+		seen[id] = true
+		links = append(links, domain.BookCategory{CategoryID: id})
+	}
+	return links
+}
+
+func validateCategoryName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if !withinLength(name, 1, 100) {
+		return "", violation("name", domain.ViolationInvalidLength, "must be 1-100 characters")
+	}
+	return name, nil
+}
+
+// WithCategories enables categories: books can be filed in the category tree
+// and listed by category.
+func WithCategories(store CategoryStore) BookServiceOption {
+	return func(service *BookService) {
+		if store == nil {
+			return
+		}
+		service.categories = store
+	}
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/bookapi/internal/domain"
+	"github.com/example/bookapi/internal/repo"
+)
+
+func TestBookServiceTaxonomy(t *testing.T) {
+	books := repo.NewMemoryBookRepository()
+	svc := NewBookService(books, WithCategories(books.Categories()), WithTags(books.Tags()))
+	ctx := context.Background()
+
+	fiction, err := svc.CreateCategory(ctx, CategoryInput{Name: " Fiction "})
+	require.NoError(t, err)
+	require.Equal(t, "Fiction", fiction.Name)
+	fantasy, err := svc.CreateCategory(ctx, CategoryInput{Name: "Fantasy", ParentID: &fiction.ID})
+	require.NoError(t, err)
+
+	_, err = svc.CreateCategory(ctx, CategoryInput{Name: "Orphan", ParentID: &uuid.UUID{1}})
+	validationErr, ok := err.(ValidationError)
+	require.True(t, ok)
+	require.Equal(t, "unknown category", validationErr.Fields["parentId"].Message)
+
+	// A category cannot move below one of its own subcategories.
+	_, err = svc.UpdateCategory(ctx, fiction.ID, CategoryInput{Name: "Fiction", ParentID: &fantasy.ID})
+	validationErr, ok = err.(ValidationError)
+	require.True(t, ok)
+	require.Contains(t, validationErr.Fields, "parentId")
+
+	book, err := svc.CreateBook(ctx, BookCreateInput{
+		Title:       "The Hobbit",
+		Author:      "J.R.R. Tolkien",
+		Price:       "10",
+		CategoryIDs: []uuid.UUID{fantasy.ID},
+		Tags:        []string{"Classic", " dragons ", "classic"},
+	})
+	require.NoError(t, err)
+	require.Len(t, book.Categories, 1)
+	require.Equal(t, "Fantasy", book.Categories[0].Name)
+	require.Equal(t, []string{"classic", "dragons"}, book.Tags)
+
+	_, err = svc.CreateBook(ctx, BookCreateInput{Title: "Nowhere", Author: "A", Price: "1", CategoryIDs: []uuid.UUID{uuid.New()}})
+	validationErr, ok = err.(ValidationError)
+	require.True(t, ok)
+	require.Equal(t, FieldError{domain.ViolationUnknownReference, "unknown category"}, validationErr.Fields["categoryIds[0]"])
+
+	// Listing by a category includes its subcategories.
+	page, err := svc.ListBooks(ctx, BookListInput{Category: fiction.ID.String()})
+	require.NoError(t, err)
+	require.Len(t, page.Books, 1)
+	page, err = svc.ListBooks(ctx, BookListInput{Tag: "DRAGONS"})
+	require.NoError(t, err)
+	require.Len(t, page.Books, 1)
+	_, err = svc.ListBooks(ctx, BookListInput{Category: "fiction"})
+	require.Error(t, err)
+
+	tags, err := svc.ListTags(ctx, "dr")
+	require.NoError(t, err)
+	require.Len(t, tags, 1)
+	require.Equal(t, "dragons", tags[0].Name)
+
+	require.ErrorIs(t, svc.DeleteCategory(ctx, fiction.ID), domain.ErrCategoryInUse)
+	require.ErrorIs(t, svc.DeleteTag(ctx, tags[0].ID), domain.ErrTagInUse)
+
+	updated, err := svc.UpdateBook(ctx, book.ID, BookUpdateInput{CategoryIDs: &[]uuid.UUID{}, Tags: &[]string{}})
+	require.NoError(t, err)
+	require.Empty(t, updated.Categories)
+	require.Empty(t, updated.Tags)
+	require.NoError(t, svc.DeleteCategory(ctx, fantasy.ID))
+	require.NoError(t, svc.DeleteTag(ctx, tags[0].ID))
+}
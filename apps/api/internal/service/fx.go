@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/example/bookapi/internal/domain"
+)
+
+// MaxBookPrices bounds the number of explicit prices on one book.
+const MaxBookPrices = 20
+
+var errFXRatesNotConfigured = errors.New("fx rate store is not configured")
+
+// FXRateStore keeps the exchange rates used to convert prices.
+type FXRateStore interface {
+	List(ctx context.Context) ([]domain.FXRate, error)
+	// Upsert stores rates, replacing the existing rate of each pair.
+	Upsert(ctx context.Context, rates []domain.FXRate) error
+}
+
+// PriceInput is an explicit price of a book in a currency other than its
+// base currency.
+type PriceInput struct {
+	Price    string
+	Currency string
+}
+
+// FXRatesInput quotes currencies against Base: Rates["USD"] = "1.0832" means
+// one unit of Base is worth 1.0832 USD.
+type FXRatesInput struct {
+	Base  string
+	Rates map[string]string
+}
+
+// LoadFXRates validates and stores rates against one base currency. Pairs
+// that are not in input keep their rate.
+func (s *BookService) LoadFXRates(ctx context.Context, input FXRatesInput) ([]domain.FXRate, error) {
+	invalid := make(map[string]FieldError)
+	base := strings.ToUpper(strings.TrimSpace(input.Base))
+	if !isCurrencyCode(base) {
+		invalid["base"] = FieldError{domain.ViolationInvalidFormat, "must be ISO 4217 code"}
+	}
+	if len(input.Rates) == 0 {
+		invalid["rates"] = FieldError{domain.ViolationRequired, "required"}
+	}
+
+	now := s.now().UTC()
+	rates := make([]domain.FXRate, 0, len(input.Rates))
+	for quote, value := range input.Rates {
+		field := "rates." + quote
+		quote = strings.ToUpper(strings.TrimSpace(quote))
+		rate, err := domain.ParseDecimal(value)
+		switch {
+		case !isCurrencyCode(quote):
+			invalid[field] = FieldError{domain.ViolationInvalidFormat, "key must be ISO 4217 code"}
+		case quote == base:
+			invalid[field] = FieldError{domain.ViolationInvalid, "cannot quote the base currency"}
+		case err != nil || rate.Unscaled <= 0:
+			invalid[field] = FieldError{domain.ViolationInvalidFormat, "must be a positive decimal"}
+		case rate.Scale > domain.MaxFXRateScale:
+			invalid[field] = FieldError{domain.ViolationInvalidPrecision, fmt.Sprintf("must have at most %d decimal places", domain.MaxFXRateScale)}
+		default:
+			rates = append(rates, domain.FXRate{Base: base, Quote: quote, Rate: rate, UpdatedAt: now})
+		}
+	}
+	if len(invalid) > 0 {
+		return nil, ValidationError{Fields: invalid}
+	}
+	if s.fxRates == nil {
+		return nil, errFXRatesNotConfigured
+	}
+
+	slices.SortFunc(rates, func(a, b domain.FXRate) int { return strings.Compare(a.Quote, b.Quote) })
+	if err := s.fxRates.Upsert(ctx, rates); err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+// ListFXRates returns every stored rate ordered by base and quote currency.
+func (s *BookService) ListFXRates(ctx context.Context) ([]domain.FXRate, error) {
+	if s.fxRates == nil {
+		return []domain.FXRate{}, nil
+	}
+	return s.fxRates.List(ctx)
+}
+
+// QuotePrices returns the price of each book in currency, explicit when the
+// book has one and otherwise converted from its base price with the stored
+// rates and the service's rounding mode. A book that cannot be priced in
+// currency fails the whole request with a ValidationError.
+func (s *BookService) QuotePrices(ctx context.Context, currency string, books []domain.Book) ([]domain.QuotedPrice, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if !isCurrencyCode(currency) {
+		return nil, violation("currency", domain.ViolationInvalidFormat, "must be ISO 4217 code")
+	}
+
+	var table domain.FXTable
+	loaded := false
+	quotes := make([]domain.QuotedPrice, 0, len(books))
+	for _, book := range books {
+		if price, ok := book.PriceIn(currency); ok {
+			quotes = append(quotes, domain.QuotedPrice{Price: price, Kind: domain.PriceExplicit})
+			continue
+		}
+		if !loaded {
+			rates, err := s.ListFXRates(ctx)
+			if err != nil {
+				return nil, err
+			}
+			table, loaded = domain.NewFXTable(rates), true
+		}
+		quote, err := book.QuotePrice(currency, table, s.rounding)
+		if errors.Is(err, domain.ErrNoFXRate) {
+			return nil, violation("currency", domain.ViolationUnknownReference,
+				fmt.Sprintf("no exchange rate from %s to %s", book.Price.Currency, currency))
+		}
+		if err != nil {
+			return nil, err
+		}
+		quotes = append(quotes, quote)
+	}
+	return quotes, nil
+}
+
+// toBookPrices validates explicit prices, recording errors under prices[i],
+// and returns them ordered by currency. A non-empty base currency cannot be
+// repeated among them.
+func toBookPrices(inputs []PriceInput, base string, errors map[string]FieldError) []domain.Money {
+	if len(inputs) > MaxBookPrices {
+		errors["prices"] = FieldError{domain.ViolationInvalidLength, fmt.Sprintf("must include at most %d prices", MaxBookPrices)}
+		return nil
+	}
+
+	var prices []domain.Money
+	seen := make(map[string]bool, len(inputs))
+	for i, input := range inputs {
+		currency := strings.ToUpper(strings.TrimSpace(input.Currency))
+		switch {
+		case !isCurrencyCode(currency):
+			errors[fmt.Sprintf("prices[%d].currency", i)] = FieldError{domain.ViolationInvalidFormat, "must be ISO 4217 code"}
+			continue
+		case currency == base:
+			errors[fmt.Sprintf("prices[%d].currency", i)] = FieldError{domain.ViolationDuplicate, "duplicates the base currency"}
+			continue
+		case seen[currency]:
+			errors[fmt.Sprintf("prices[%d].currency", i)] = FieldError{domain.ViolationDuplicate, "duplicates an earlier price"}
+			continue
+		}
+		seen[currency] = true
+
+		price, err := domain.ParseMoney(input.Price, currency)
+		switch {
+		case err != nil:
+			errors[fmt.Sprintf("prices[%d].price", i)] = moneyError(err)
+		case price.IsNegative():
+			errors[fmt.Sprintf("prices[%d].price", i)] = FieldError{domain.ViolationOutOfRange, "must be >= 0"}
+		default:
+			prices = append(prices, price)
+		}
+	}
+	slices.SortFunc(prices, func(a, b domain.Money) int { return strings.Compare(a.Currency, b.Currency) })
+	return prices
+}
+
+func isCurrencyCode(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// WithFXRates enables converted prices: books without an explicit price in a
+// requested currency are converted from their base price with the rates of
+// store, rounded to the currency's minor unit with rounding.
+func WithFXRates(store FXRateStore, rounding domain.RoundingMode) BookServiceOption {
+	return func(service *BookService) {
+		if store == nil {
+			return
+		}
+		service.fxRates = store
+		if rounding != "" {
+			service.rounding = rounding
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/bookapi/internal/domain"
+	"github.com/example/bookapi/internal/repo"
+)
+
+func TestBookServicePrices(t *testing.T) {
+	svc := NewBookService(repo.NewMemoryBookRepository(),
+		WithFXRates(repo.NewMemoryFXRateRepository(), domain.RoundHalfEven))
+	ctx := context.Background()
+
+	book, err := svc.CreateBook(ctx, BookCreateInput{
+		Title:    "Dune",
+		Author:   "Frank Herbert",
+		Price:    "10.00",
+		Currency: "USD",
+		Prices:   []PriceInput{{Price: "8.99", Currency: "gbp"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []domain.Money{{Amount: 899, Currency: "GBP"}}, book.Prices)
+
+	_, err = svc.CreateBook(ctx, BookCreateInput{
+		Title:  "Dune",
+		Author: "Frank Herbert",
+		Price:  "10.00",
+		Prices: []PriceInput{{Price: "1", Currency: "USD"}, {Price: "1.5", Currency: "JPY"}},
+	})
+	validationErr, ok := err.(ValidationError)
+	require.True(t, ok)
+	require.Contains(t, validationErr.Fields, "prices[0].currency")
+	require.Contains(t, validationErr.Fields, "prices[1].price")
+
+	// Without rates only explicit prices can be quoted.
+	quotes, err := svc.QuotePrices(ctx, "gbp", []domain.Book{book})
+	require.NoError(t, err)
+	require.Equal(t, domain.PriceExplicit, quotes[0].Kind)
+	require.Equal(t, "8.99", quotes[0].Price.String())
+	_, err = svc.QuotePrices(ctx, "EUR", []domain.Book{book})
+	validationErr, ok = err.(ValidationError)
+	require.True(t, ok)
+	require.Equal(t, "no exchange rate from USD to EUR", validationErr.Fields["currency"].Message)
+
+	_, err = svc.LoadFXRates(ctx, FXRatesInput{Base: "EUR", Rates: map[string]string{"USD": "1.25", "JPY": "-1", "EUR": "1"}})
+	validationErr, ok = err.(ValidationError)
+	require.True(t, ok)
+	require.Contains(t, validationErr.Fields, "rates.JPY")
+	require.Contains(t, validationErr.Fields, "rates.EUR")
+
+	_, err = svc.LoadFXRates(ctx, FXRatesInput{Base: "EUR", Rates: map[string]string{"USD": "1.25", "JPY": "160.5"}})
+	require.NoError(t, err)
+	rates, err := svc.ListFXRates(ctx)
+	require.NoError(t, err)
+	require.Len(t, rates, 2)
+
+	// USD 10.00 is EUR 8.00 through the inverse rate, and JPY 1284 through
+	// EUR.
+	quotes, err = svc.QuotePrices(ctx, "EUR", []domain.Book{book})
+	require.NoError(t, err)
+	require.Equal(t, domain.PriceConverted, quotes[0].Kind)
+	require.Equal(t, "8.00", quotes[0].Price.String())
+	quotes, err = svc.QuotePrices(ctx, "JPY", []domain.Book{book})
+	require.NoError(t, err)
+	require.Equal(t, "1284", quotes[0].Price.String())
+
+	// EUR 0.10 is USD 0.125, which half-even rounding takes to USD 0.12.
+	cheap := book
+	cheap.Price = domain.Money{Amount: 10, Currency: "EUR"}
+	quotes, err = svc.QuotePrices(ctx, "USD", []domain.Book{cheap})
+	require.NoError(t, err)
+	require.Equal(t, "0.12", quotes[0].Price.String())
+
+	// The base currency cannot also have an explicit price.
+	gbp := "GBP"
+	_, err = svc.UpdateBook(ctx, book.ID, BookUpdateInput{Currency: &gbp})
+	validationErr, ok = err.(ValidationError)
+	require.True(t, ok)
+	require.Contains(t, validationErr.Fields, "prices")
+
+	updated, err := svc.UpdateBook(ctx, book.ID, BookUpdateInput{Prices: &[]PriceInput{}})
+	require.NoError(t, err)
+	require.Empty(t, updated.Prices)
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/example/bookapi/internal/domain"
+)
+
+// MaxStockDelta bounds the size of a single stock adjustment.
+const MaxStockDelta = 1_000_000
+
+// StockLedger keeps the append-only record of stock adjustments.
+type StockLedger interface {
+	Append(ctx context.Context, adjustment domain.StockAdjustment) error
+	List(ctx context.Context, query domain.StockLedgerQuery) (domain.StockLedgerPage, error)
+}
+
+type StockAdjustmentInput struct {
+	// Delta is added to the current stock; sales and shrinkage are negative,
+	// returns and restocks positive.
+	Delta  int
+	Reason string
+	// Reference identifies the order, receipt or stock count behind the
+	// adjustment.
+	Reference string
+}
+
+type StockLedgerInput struct {
+	Limit  int
+	Cursor string
+}
+
+// AdjustStock applies a signed change to a book's stock and records it in
+// the stock ledger. The change is applied by the database, so concurrent
+// adjustments cannot lose updates, and stock never drops below zero. It
+// returns the ledger entry and the updated book.
+func (s *BookService) AdjustStock(ctx context.Context, id uuid.UUID, input StockAdjustmentInput) (domain.StockAdjustment, domain.Book, error) {
+	reason := domain.StockReason(strings.ToLower(strings.TrimSpace(input.Reason)))
+	reference := strings.TrimSpace(input.Reference)
+	if err := validateStockAdjustmentInput(input.Delta, reason, reference); err != nil {
+		return domain.StockAdjustment{}, domain.Book{}, err
+	}
+
+	var (
+		adjustment domain.StockAdjustment
+		book       domain.Book
+	)
+	err := s.inTx(ctx, func(ctx context.Context) error {
+		now := s.now().UTC()
+		var err error
+		book, err = s.repo.AdjustStock(ctx, id, input.Delta, now)
+		if err != nil {
+			return err
+		}
+
+		adjustment = domain.StockAdjustment{
+			ID:          uuid.New(),
+			BookID:      book.ID,
+			Delta:       input.Delta,
+			Reason:      reason,
+			Reference:   reference,
+			Actor:       domain.ActorFromContext(ctx),
+			StockAfter:  book.Stock,
+			BookVersion: book.Version,
+			CreatedAt:   now,
+		}
+		if err := s.ledger.Append(ctx, adjustment); err != nil {
+			return fmt.Errorf("record stock adjustment: %w", err)
+		}
+
+		before := book
+		before.Stock -= input.Delta
+		before.Version--
+		if err := s.recordRevision(ctx, domain.RevisionUpdated, &before, book); err != nil {
+			return err
+		}
+		return s.enqueue(ctx, func() (domain.OutboxEvent, error) {
+			return domain.NewStockChangedEvent(adjustment)
+		})
+	})
+	if err != nil {
+		return domain.StockAdjustment{}, domain.Book{}, err
+	}
+
+	s.publish(ctx, domain.EventTypeStockChanged, book.ID, func(ctx context.Context) error {
+		return s.publisher.PublishStockChanged(ctx, adjustment)
+	})
+	return adjustment, book, nil
+}
+
+// ListStockAdjustments returns the stock ledger of a book, newest first.
+func (s *BookService) ListStockAdjustments(ctx context.Context, id uuid.UUID, input StockLedgerInput) (domain.StockLedgerPage, error) {
+	errors := make(map[string]FieldError)
+	query := domain.StockLedgerQuery{BookID: id, Limit: input.Limit}
+
+	if query.Limit == 0 {
+		query.Limit = domain.DefaultPageLimit
+	} else if query.Limit < 1 || query.Limit > domain.MaxPageLimit {
+		errors["limit"] = FieldError{domain.ViolationOutOfRange, fmt.Sprintf("must be between 1 and %d", domain.MaxPageLimit)}
+	}
+	if cursor := strings.TrimSpace(input.Cursor); cursor != "" {
+		version, err := domain.DecodeRevisionCursor(cursor)
+		if err != nil {
+			errors["cursor"] = FieldError{domain.ViolationInvalid, "invalid"}
+		}
+		query.BeforeVersion = &version
+	}
+	if len(errors) > 0 {
+		return domain.StockLedgerPage{}, ValidationError{Fields: errors}
+	}
+
+	page, err := s.ledger.List(ctx, query)
+	if err != nil {
+		return domain.StockLedgerPage{}, err
+	}
+	if len(page.Adjustments) == 0 && query.BeforeVersion == nil {
+		if _, err := s.repo.Get(ctx, id); err != nil {
+			return domain.StockLedgerPage{}, err
+		}
+	}
+	return page, nil
+}
+
+func validateStockAdjustmentInput(delta int, reason domain.StockReason, reference string) error {
+	errors := make(map[string]FieldError)
+
+	switch {
+	case delta == 0:
+		errors["delta"] = FieldError{domain.ViolationOutOfRange, "must not be zero"}
+	case delta < -MaxStockDelta || delta > MaxStockDelta:
+		errors["delta"] = FieldError{domain.ViolationOutOfRange, fmt.Sprintf("must be between -%d and %d", MaxStockDelta, MaxStockDelta)}
+	case reason.Valid() && reason.Decreases() && delta > 0:
+		errors["delta"] = FieldError{domain.ViolationOutOfRange, fmt.Sprintf("must be negative for %s", reason)}
+	case reason.Valid() && !reason.Decreases() && delta < 0:
+		errors["delta"] = FieldError{domain.ViolationOutOfRange, fmt.Sprintf("must be positive for %s", reason)}
+	}
+
+	if !reason.Valid() {
+		errors["reason"] = FieldError{domain.ViolationInvalidChoice, "must be one of sale, return, restock, shrinkage"}
+	}
+
+	if utf8.RuneCountInString(reference) > 200 {
+		errors["reference"] = FieldError{domain.ViolationInvalidLength, "must be at most 200 characters"}
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Fields: errors}
+	}
+	return nil
+}
+
+type noopStockLedger struct{}
+
+func (noopStockLedger) Append(context.Context, domain.StockAdjustment) error {
+	return nil
+}
+
+func (noopStockLedger) List(context.Context, domain.StockLedgerQuery) (domain.StockLedgerPage, error) {
+	return domain.StockLedgerPage{}, nil
+}
+
+// WithStockLedger records every stock adjustment in store. When tx is
+// non-nil the entry is written in the same transaction as the stock change.
+func WithStockLedger(tx Transactor, store StockLedger) BookServiceOption {
+	return func(service *BookService) {
+		if store == nil {
+			return
+		}
+		if tx != nil {
+			service.tx = tx
+		}
+		service.ledger = store
+	}
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/bookapi/internal/domain"
+	"github.com/example/bookapi/internal/repo"
+)
+
+func TestBookServiceAdjustStock(t *testing.T) {
+	ledger := repo.NewMemoryStockLedgerRepository()
+	svc := NewBookService(newMockBookRepo(),
+		WithRevisions(nil, repo.NewMemoryRevisionRepository()),
+		WithStockLedger(nil, ledger),
+	)
+	now := time.Date(2025, 11, 10, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	ctx := domain.ContextWithActor(context.Background(), "till-3")
+
+	book, err := svc.CreateBook(ctx, BookCreateInput{Title: "Dune", Author: "Frank Herbert", Price: "9.99", Stock: 2})
+	require.NoError(t, err)
+
+	adjustment, updated, err := svc.AdjustStock(ctx, book.ID, StockAdjustmentInput{Delta: 5, Reason: "Restock", Reference: " PO-17 "})
+	require.NoError(t, err)
+	require.Equal(t, 7, updated.Stock)
+	require.Equal(t, int64(2), updated.Version)
+	require.Equal(t, domain.StockRestock, adjustment.Reason)
+	require.Equal(t, "PO-17", adjustment.Reference)
+	require.Equal(t, "till-3", adjustment.Actor)
+	require.Equal(t, 7, adjustment.StockAfter)
+	require.Equal(t, int64(2), adjustment.BookVersion)
+
+	_, _, err = svc.AdjustStock(ctx, book.ID, StockAdjustmentInput{Delta: -8, Reason: "sale", Reference: "order-1"})
+	require.ErrorIs(t, err, domain.ErrInsufficientStock)
+	_, updated, err = svc.AdjustStock(ctx, book.ID, StockAdjustmentInput{Delta: -7, Reason: "sale", Reference: "order-1"})
+	require.NoError(t, err)
+	require.Equal(t, 0, updated.Stock)
+
+	_, _, err = svc.AdjustStock(ctx, book.ID, StockAdjustmentInput{Delta: 1, Reason: "shrinkage"})
+	validationErr, ok := err.(ValidationError)
+	require.True(t, ok)
+	require.Equal(t, "must be negative for shrinkage", validationErr.Fields["delta"].Message)
+	_, _, err = svc.AdjustStock(ctx, book.ID, StockAdjustmentInput{Delta: 0, Reason: "gift"})
+	validationErr, ok = err.(ValidationError)
+	require.True(t, ok)
+	require.Contains(t, validationErr.Fields, "delta")
+	require.Contains(t, validationErr.Fields, "reason")
+
+	_, _, err = svc.AdjustStock(ctx, uuid.New(), StockAdjustmentInput{Delta: 1, Reason: "return"})
+	require.ErrorIs(t, err, domain.ErrBookNotFound)
+
+	page, err := svc.ListStockAdjustments(ctx, book.ID, StockLedgerInput{Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, page.Adjustments, 1)
+	require.Equal(t, -7, page.Adjustments[0].Delta)
+	require.NotEmpty(t, page.NextCursor)
+	page, err = svc.ListStockAdjustments(ctx, book.ID, StockLedgerInput{Cursor: page.NextCursor})
+	require.NoError(t, err)
+	require.Len(t, page.Adjustments, 1)
+	require.Equal(t, 5, page.Adjustments[0].Delta)
+	require.Empty(t, page.NextCursor)
+
+	history, err := svc.ListBookHistory(ctx, book.ID, BookHistoryInput{})
+	require.NoError(t, err)
+	require.Len(t, history.Revisions, 3)
+	require.Equal(t, []domain.FieldChange{{Field: "stock", Before: 7, After: 0}}, history.Revisions[0].Changes)
+
+	_, err = svc.ListStockAdjustments(ctx, uuid.New(), StockLedgerInput{})
+	require.ErrorIs(t, err, domain.ErrBookNotFound)
+}
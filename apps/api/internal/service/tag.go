@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/example/bookapi/internal/domain"
+)
+
+// MaxBookTags bounds the number of tags on one book.
+const MaxBookTags = 20
+
+var errTagsNotConfigured = errors.New("tag store is not configured")
+
+// TagStore keeps tags. Books reference tags by name; the BookRepository
+// stores the links.
+type TagStore interface {
+	Create(ctx context.Context, tag domain.Tag) error
+	// Ensure creates the tags in names that do not exist yet.
+	Ensure(ctx context.Context, names []string, createdAt time.Time) error
+	Get(ctx context.Context, id uuid.UUID) (domain.Tag, error)
+	// List returns the tags whose name starts with prefix, ordered by name.
+	List(ctx context.Context, prefix string) ([]domain.Tag, error)
+	Update(ctx context.Context, tag domain.Tag) error
+	// Delete fails with domain.ErrTagInUse while the tag is on a book.
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type TagInput struct {
+	Name string
+}
+
+// CreateTag creates a tag ahead of its first use; books create their tags
+// as needed.
+func (s *BookService) CreateTag(ctx context.Context, input TagInput) (domain.Tag, error) {
+	if s.tags == nil {
+		return domain.Tag{}, errTagsNotConfigured
+	}
+	name, err := validateTagName(input.Name)
+	if err != nil {
+		return domain.Tag{}, err
+	}
+	tag := domain.Tag{ID: uuid.New(), Name: name, CreatedAt: s.now().UTC()}
+	if err := s.tags.Create(ctx, tag); err != nil {
+		return domain.Tag{}, err
+	}
+	return tag, nil
+}
+
+func (s *BookService) GetTag(ctx context.Context, id uuid.UUID) (domain.Tag, error) {
+	if s.tags == nil {
+		return domain.Tag{}, domain.ErrTagNotFound
+	}
+	return s.tags.Get(ctx, id)
+}
+
+// ListTags returns the tags starting with prefix, ignoring case, ordered by
+// name.
+func (s *BookService) ListTags(ctx context.Context, prefix string) ([]domain.Tag, error) {
+	if s.tags == nil {
+		return []domain.Tag{}, nil
+	}
+	return s.tags.List(ctx, domain.NormalizeTag(prefix))
+}
+
+// UpdateTag renames a tag on every book that has it.
+func (s *BookService) UpdateTag(ctx context.Context, id uuid.UUID, input TagInput) (domain.Tag, error) {
+	if s.tags == nil {
+		return domain.Tag{}, errTagsNotConfigured
+	}
+	name, err := validateTagName(input.Name)
+	if err != nil {
+		return domain.Tag{}, err
+	}
+	tag, err := s.tags.Get(ctx, id)
+	if err != nil {
+		return domain.Tag{}, err
+	}
+	tag.Name = name
+	if err := s.tags.Update(ctx, tag); err != nil {
+		return domain.Tag{}, err
+	}
+	return tag, nil
+}
+
+// DeleteTag removes a tag that is on no book, including soft-deleted ones.
+func (s *BookService) DeleteTag(ctx context.Context, id uuid.UUID) error {
+	if s.tags == nil {
+		return errTagsNotConfigured
+	}
+	return s.tags.Delete(ctx, id)
+}
+
+// normalizeTags normalizes tag names, drops duplicates and sorts them,
+// recording errors under tags[i].
+func normalizeTags(names []string, errors map[string]FieldError) []string {
+	if len(names) > MaxBookTags {
+		errors["tags"] = FieldError{domain.ViolationInvalidLength, fmt.Sprintf("must include at most %d tags", MaxBookTags)}
+		return nil
+	}
+
+	var tags []string
+	for i, name := range names {
+		tag := domain.NormalizeTag(name)
+		if !withinLength(tag, 1, 50) {
+			errors[fmt.Sprintf("tags[%d]", i)] = FieldError{domain.ViolationInvalidLength, "must be 1-50 characters"}
+			continue
+		}
+		tags = append(tags, tag)
+	}
+	slices.Sort(tags)
+	return slices.Compact(tags)
+}
+
+func validateTagName(name string) (string, error) {
+	name = domain.NormalizeTag(name)
+	if !withinLength(name, 1, 50) {
+		return "", violation("name", domain.ViolationInvalidLength, "must be 1-50 characters")
+	}
+	return name, nil
+}
+
+// WithTags enables tags: books can be tagged and listed by tag.
+func WithTags(store TagStore) BookServiceOption {
+	return func(service *BookService) {
+		if store == nil {
+			return
+		}
+		service.tags = store
+	}
+}
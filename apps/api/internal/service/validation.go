@@ -0,0 +1,76 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/example/bookapi/internal/domain"
+)
+
+// ValidationError rejects a request for invalid input. Fields maps the path
+// of every invalid field to what is wrong with it.
+type ValidationError struct {
+	Fields map[string]FieldError
+}
+
+// FieldError says what is wrong with a field: one of the domain.Violation*
+// codes and a message for people.
+type FieldError struct {
+	Code    string
+	Message string
+}
+
+// String returns the message, so errors print as they read to people.
+func (f FieldError) String() string {
+	return f.Message
+}
+
+func (v ValidationError) Error() string {
+	return fmt.Sprintf("validation failed: %v", v.Fields)
+}
+
+// Is makes validation errors of the domain.ErrValidation kind.
+func (v ValidationError) Is(target error) bool {
+	return target == domain.ErrValidation
+}
+
+// Violations lists the invalid fields sorted by path.
+func (v ValidationError) Violations() []domain.Violation {
+	violations := make([]domain.Violation, 0, len(v.Fields))
+	for field, fieldErr := range v.Fields {
+		violations = append(violations, domain.Violation{
+			Field:   field,
+			Code:    fieldErr.Code,
+			Message: fieldErr.Message,
+		})
+	}
+	sort.Slice(violations, func(i, j int) bool {
+		return violations[i].Field < violations[j].Field
+	})
+	return violations
+}
+
+// violation rejects a request for a single invalid field.
+func violation(field, code, message string) ValidationError {
+	return ValidationError{Fields: map[string]FieldError{field: {code, message}}}
+}
+
+// isbnError says what is wrong with an ISBN domain.NormalizeISBN rejected
+// with err.
+func isbnError(err error) FieldError {
+	if errors.Is(err, domain.ErrISBNCheckDigit) {
+		return FieldError{domain.ViolationInvalid, err.Error()}
+	}
+	return FieldError{domain.ViolationInvalidFormat, err.Error()}
+}
+
+// moneyError says what is wrong with an amount domain.ParseMoney or
+// domain.ParseDecimal rejected with err.
+func moneyError(err error) FieldError {
+	var precisionErr domain.PrecisionError
+	if errors.As(err, &precisionErr) {
+		return FieldError{domain.ViolationInvalidPrecision, err.Error()}
+	}
+	return FieldError{domain.ViolationInvalidFormat, err.Error()}
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/bookapi/internal/domain"
+	"github.com/example/bookapi/internal/repo"
+)
+
+func TestValidationErrorViolations(t *testing.T) {
+	err := ValidationError{Fields: map[string]FieldError{
+		"title":           {domain.ViolationInvalidLength, "must be 1-200 characters"},
+		"authors[0].role": {domain.ViolationInvalidChoice, "must be one of author, editor, translator"},
+		"categoryIds[0]":  {domain.ViolationUnknownReference, "unknown category"},
+		"author":          {domain.ViolationRequired, "required unless authors are given"},
+	}}
+
+	require.Equal(t, []domain.Violation{
+		{Field: "author", Code: domain.ViolationRequired, Message: "required unless authors are given"},
+		{Field: "authors[0].role", Code: domain.ViolationInvalidChoice, Message: "must be one of author, editor, translator"},
+		{Field: "categoryIds[0]", Code: domain.ViolationUnknownReference, Message: "unknown category"},
+		{Field: "title", Code: domain.ViolationInvalidLength, Message: "must be 1-200 characters"},
+	}, err.Violations())
+}
+
+func TestParseErrorCodes(t *testing.T) {
+	_, err := domain.NormalizeISBN("9780306406158")
+	require.Equal(t, FieldError{domain.ViolationInvalid, domain.ErrISBNCheckDigit.Error()}, isbnError(err))
+	_, err = domain.NormalizeISBN("9770306406157")
+	require.Equal(t, FieldError{domain.ViolationInvalidFormat, domain.ErrUnsupportedPrefix.Error()}, isbnError(err))
+
+	_, err = domain.ParseMoney("1.5", "JPY")
+	require.Equal(t, FieldError{domain.ViolationInvalidPrecision, "must have at most 0 decimal places for JPY"}, moneyError(err))
+	_, err = domain.ParseMoney("1,5", "EUR")
+	require.Equal(t, FieldError{domain.ViolationInvalidFormat, domain.ErrInvalidDecimal.Error()}, moneyError(err))
+}
+
+func TestBookServiceCreate_Violations(t *testing.T) {
+	svc := NewBookService(repo.NewMemoryBookRepository())
+
+	_, err := svc.CreateBook(context.Background(), BookCreateInput{Author: "Frank Herbert", Price: "-1", Currency: "euro"})
+	var validationErr ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	require.Len(t, validationErr.Violations(), 3)
+	for _, violation := range validationErr.Violations() {
+		require.NotEqual(t, domain.ViolationInvalid, violation.Code, "%s: %s", violation.Field, violation.Message)
+	}
+}
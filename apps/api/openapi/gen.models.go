@@ -6,52 +6,634 @@ package openapi
 import (
 	"time"
 
+	"github.com/example/bookapi/internal/domain"
 	openapi_types "github.com/oapi-codegen/runtime/types"
 )
 
-// Book defines model for Book.
-type Book struct {
-	Author    string             `json:"author"`
+// Author defines model for Author.
+type Author struct {
 	CreatedAt time.Time          `json:"createdAt"`
-	Currency  string             `json:"currency"`
 	Id        openapi_types.UUID `json:"id"`
-	Price     float32            `json:"price"`
-	Stock     int                `json:"stock"`
-	Title     string             `json:"title"`
+	Name      string             `json:"name"`
 	UpdatedAt time.Time          `json:"updatedAt"`
 }
 
+// AuthorCreate defines model for AuthorCreate.
+type AuthorCreate struct {
+	Name string `json:"name"`
+}
+
+// Defines values for AuthorRole.
+const (
+	AuthorRoleAuthor     AuthorRole = "author"
+	AuthorRoleEditor     AuthorRole = "editor"
+	AuthorRoleTranslator AuthorRole = "translator"
+)
+
+// AuthorRole Part the author played in the book.
+type AuthorRole string
+
+// AuthorUpdate defines model for AuthorUpdate.
+type AuthorUpdate struct {
+	Name string `json:"name"`
+}
+
+// BatchCreateBookResult defines model for BatchCreateBookResult.
+type BatchCreateBookResult struct {
+	Book *Book `json:"book,omitempty"`
+
+	// Errors The invalid fields of the item, sorted by field; set when the item was not created.
+	Errors *[]Violation `json:"errors,omitempty"`
+
+	// Index Position of the item in the request.
+	Index int `json:"index"`
+}
+
+// BatchCreateBooksRequest defines model for BatchCreateBooksRequest.
+type BatchCreateBooksRequest struct {
+	Items []BookCreate `json:"items"`
+}
+
+// BatchCreateBooksResponse defines model for BatchCreateBooksResponse.
+type BatchCreateBooksResponse struct {
+	// Created Number of books created.
+	Created int `json:"created"`
+
+	// Failed Number of items rejected by validation.
+	Failed int `json:"failed"`
+
+	// Results One entry per request item, in request order.
+	Results []BatchCreateBookResult `json:"results"`
+}
+
+// Book defines model for Book.
+type Book struct {
+	// Author Credit line as printed on the book.
+	Author string `json:"author"`
+
+	// Authors Credited authors in credit order, under their current names.
+	Authors *[]BookAuthor `json:"authors,omitempty"`
+
+	// Categories Categories the book is filed under, ordered by name.
+	Categories *[]BookCategory `json:"categories,omitempty"`
+	CreatedAt  time.Time       `json:"createdAt"`
+	Currency   string          `json:"currency"`
+
+	// DeletedAt Set when the book has been soft-deleted.
+	DeletedAt *time.Time         `json:"deletedAt,omitempty"`
+	Id        openapi_types.UUID `json:"id"`
+
+	// Isbn ISBN-13 without hyphens. Omitted when the book has no ISBN.
+	Isbn *string `json:"isbn,omitempty"`
+
+	// Price Exact decimal amount in major units of `currency`, with at most the currency's minor-unit decimal places (0 for JPY, 2 for USD, 3 for KWD).
+	Price string `json:"price"`
+
+	// Prices Explicit prices in currencies other than `currency`, ordered by currency.
+	Prices *[]Price `json:"prices,omitempty"`
+
+	// QuotedPrice Price in the currency requested with `currency`.
+	QuotedPrice *QuotedPrice `json:"quotedPrice,omitempty"`
+	Stock       int          `json:"stock"`
+
+	// Tags Tags of the book, in lowercase and ordered by name.
+	Tags      *[]string `json:"tags,omitempty"`
+	Title     string    `json:"title"`
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	// Version Incremented on every update; also returned as the `ETag` header.
+	Version int64 `json:"version"`
+}
+
+// BookAuthor defines model for BookAuthor.
+type BookAuthor struct {
+	AuthorId openapi_types.UUID `json:"authorId"`
+
+	// Name Current name of the author.
+	Name string `json:"name"`
+
+	// Role Part the author played in the book.
+	Role AuthorRole `json:"role"`
+}
+
+// BookAuthorCreate Credits an author on a book. `role` defaults to `author`.
+type BookAuthorCreate struct {
+	AuthorId openapi_types.UUID `json:"authorId"`
+
+	// Role Part the author played in the book.
+	Role *AuthorRole `json:"role,omitempty"`
+}
+
+// BookCategory defines model for BookCategory.
+type BookCategory struct {
+	CategoryId openapi_types.UUID `json:"categoryId"`
+
+	// Name Current name of the category.
+	Name string `json:"name"`
+}
+
 // BookCreate defines model for BookCreate.
 type BookCreate struct {
-	Author   string  `json:"author"`
-	Currency string  `json:"currency"`
-	Price    float32 `json:"price"`
-	Stock    int     `json:"stock"`
-	Title    string  `json:"title"`
+	// Author Credit line. Required unless `authors` is given, in which case it defaults to the names of those credited as author. Without `authors`, the book is credited to the author of this name, who is created if needed.
+	Author *string `json:"author,omitempty"`
+
+	// Authors Authors to credit, in credit order.
+	Authors *[]BookAuthorCreate `json:"authors,omitempty"`
+
+	// CategoryIds Categories to file the book under.
+	CategoryIds *[]openapi_types.UUID `json:"categoryIds,omitempty"`
+	Currency    string                `json:"currency"`
+
+	// Isbn ISBN-10 or ISBN-13, with or without hyphens. Stored and returned as ISBN-13; must be unique across books.
+	Isbn *string `json:"isbn,omitempty"`
+
+	// Price Exact decimal amount in major units of `currency`, with at most the currency's minor-unit decimal places (0 for JPY, 2 for USD, 3 for KWD).
+	Price string `json:"price"`
+
+	// Prices Explicit prices in currencies other than `currency`. Other currencies are converted from the base price.
+	Prices *[]Price `json:"prices,omitempty"`
+	Stock  int      `json:"stock"`
+
+	// Tags Tags of the book. Names are stored in lowercase; tags that do not exist yet are created.
+	Tags  *[]string `json:"tags,omitempty"`
+	Title string    `json:"title"`
+}
+
+// Defines values for BookRevisionAction.
+const (
+	Create  BookRevisionAction = "create"
+	Delete  BookRevisionAction = "delete"
+	Restore BookRevisionAction = "restore"
+	Update  BookRevisionAction = "update"
+)
+
+// BookRevision defines model for BookRevision.
+type BookRevision struct {
+	Action BookRevisionAction `json:"action"`
+
+	// Actor Caller named by the `X-Actor` header, or `anonymous`.
+	Actor     string        `json:"actor"`
+	ChangedAt time.Time     `json:"changedAt"`
+	Changes   []FieldChange `json:"changes"`
+	Snapshot  Book          `json:"snapshot"`
+
+	// Version Book version produced by this change.
+	Version int64 `json:"version"`
+}
+
+// BookRevisionAction defines model for BookRevision.Action.
+type BookRevisionAction string
+
+// BookSearchResult defines model for BookSearchResult.
+type BookSearchResult struct {
+	Book Book `json:"book"`
+
+	// Rank Relevance score; higher is better.
+	Rank float32 `json:"rank"`
+
+	// Snippet Title and author, HTML-escaped, with matching terms wrapped in `<mark>` tags.
+	Snippet string `json:"snippet"`
 }
 
 // BookUpdate defines model for BookUpdate.
 type BookUpdate struct {
-	Author   *string  `json:"author,omitempty"`
-	Currency *string  `json:"currency,omitempty"`
-	Price    *float32 `json:"price,omitempty"`
-	Stock    *int     `json:"stock,omitempty"`
-	Title    *string  `json:"title,omitempty"`
+	// Author Credit line. Changing it without `authors` credits the book to the author of this name in place of its previous authors.
+	Author *string `json:"author,omitempty"`
+
+	// Authors Replaces the credited authors. Without `author`, the credit line is derived from their names.
+	Authors *[]BookAuthorCreate `json:"authors,omitempty"`
+
+	// CategoryIds Replaces the categories; an empty list removes them all.
+	CategoryIds *[]openapi_types.UUID `json:"categoryIds,omitempty"`
+	Currency    *string               `json:"currency,omitempty"`
+
+	// Isbn ISBN-10 or ISBN-13, with or without hyphens. An empty string removes the ISBN.
+	Isbn *string `json:"isbn,omitempty"`
+
+	// Price Exact decimal amount in major units of `currency`, with at most the currency's minor-unit decimal places (0 for JPY, 2 for USD, 3 for KWD).
+	Price *string `json:"price,omitempty"`
+
+	// Prices Replaces the explicit prices; an empty list removes them all.
+	Prices *[]Price `json:"prices,omitempty"`
+	Stock  *int     `json:"stock,omitempty"`
+
+	// Tags Replaces the tags; an empty list removes them all.
+	Tags  *[]string `json:"tags,omitempty"`
+	Title *string   `json:"title,omitempty"`
+}
+
+// Category defines model for Category.
+type Category struct {
+	CreatedAt time.Time          `json:"createdAt"`
+	Id        openapi_types.UUID `json:"id"`
+	Name      string             `json:"name"`
+
+	// ParentId Parent category; omitted for top-level categories.
+	ParentId  *openapi_types.UUID `json:"parentId,omitempty"`
+	UpdatedAt time.Time           `json:"updatedAt"`
+}
+
+// CategoryCreate defines model for CategoryCreate.
+type CategoryCreate struct {
+	// Name Unique among the categories sharing the parent, ignoring case.
+	Name string `json:"name"`
+
+	// ParentId Parent category; omit to create a top-level category.
+	ParentId *openapi_types.UUID `json:"parentId,omitempty"`
+}
+
+// CategoryUpdate defines model for CategoryUpdate.
+type CategoryUpdate struct {
+	Name string `json:"name"`
+
+	// ParentId New parent category; omit to make the category top-level.
+	ParentId *openapi_types.UUID `json:"parentId,omitempty"`
+}
+
+// FieldChange defines model for FieldChange.
+type FieldChange struct {
+	// After Value after the change.
+	After interface{} `json:"after"`
+
+	// Before Value before the change; null for fields set on create.
+	Before interface{} `json:"before"`
+	Field  string      `json:"field"`
+}
+
+// FxRate defines model for FxRate.
+type FxRate struct {
+	Base  string `json:"base"`
+	Quote string `json:"quote"`
+
+	// Rate Units of `quote` one unit of `base` is worth.
+	Rate      string    `json:"rate"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// FxRatesLoad defines model for FxRatesLoad.
+type FxRatesLoad struct {
+	Base string `json:"base"`
+
+	// Rates Rates by quote currency: units of the quote currency one unit of `base` is worth, as decimals with at most 12 places.
+	Rates map[string]string `json:"rates"`
+}
+
+// Price defines model for Price.
+type Price struct {
+	Currency string `json:"currency"`
+
+	// Price Exact decimal amount in major units of `currency`, with at most the currency's minor-unit decimal places.
+	Price string `json:"price"`
+}
+
+// Defines values for PriceKind.
+const (
+	Converted PriceKind = "converted"
+	Explicit  PriceKind = "explicit"
+)
+
+// PriceKind `explicit` for the book's base price or a price set for the currency, `converted` for the base price converted with the stored exchange rates.
+type PriceKind string
+
+// Problem RFC 9457 problem details.
+type Problem struct {
+	// Code Stable, machine-readable kind of problem: `validation_failed` for requests with invalid fields, otherwise the reason phrase of the status in snake case, such as `not_found`, `conflict` or `precondition_failed`.
+	Code string `json:"code"`
+
+	// Detail Explanation of this occurrence of the problem.
+	Detail *string `json:"detail,omitempty"`
+
+	// RequestId ID of the request, as in the `X-Request-ID` header.
+	RequestId *string `json:"requestId,omitempty"`
+	Status    int     `json:"status"`
+
+	// Title Reason phrase of the status code.
+	Title string `json:"title"`
+
+	// Type URI naming the kind of problem; ends in `code`.
+	Type string `json:"type"`
+
+	// Violations The invalid fields of a `validation_failed` problem.
+	Violations *[]Violation `json:"violations,omitempty"`
+}
+
+// QuotedPrice Price in the currency requested with `currency`.
+type QuotedPrice struct {
+	Currency string `json:"currency"`
+
+	// Kind `explicit` for the book's base price or a price set for the currency, `converted` for the base price converted with the stored exchange rates.
+	Kind  PriceKind `json:"kind"`
+	Price string    `json:"price"`
+
+	// Rate Exchange rate from the base currency applied to a converted price; omitted for explicit prices.
+	Rate *string `json:"rate,omitempty"`
 }
 
-// Error defines model for Error.
-type Error struct {
-	Message string `json:"message"`
+// StockAdjustment defines model for StockAdjustment.
+type StockAdjustment struct {
+	// Actor Caller named by the `X-Actor` header, or `anonymous`.
+	Actor  string             `json:"actor"`
+	BookId openapi_types.UUID `json:"bookId"`
+
+	// BookVersion Book version produced by the adjustment.
+	BookVersion int64              `json:"bookVersion"`
+	CreatedAt   time.Time          `json:"createdAt"`
+	Delta       int                `json:"delta"`
+	Id          openapi_types.UUID `json:"id"`
+
+	// Reason Why stock changed. `sale` and `shrinkage` take stock out and need a negative delta; `return` and `restock` need a positive one.
+	Reason    StockReason `json:"reason"`
+	Reference string      `json:"reference"`
+
+	// StockAfter Stock of the book once the adjustment was applied.
+	StockAfter int `json:"stockAfter"`
 }
 
+// StockAdjustmentCreate defines model for StockAdjustmentCreate.
+type StockAdjustmentCreate struct {
+	// Delta Signed, non-zero change to the stock.
+	Delta int `json:"delta"`
+
+	// Reason Why stock changed. `sale` and `shrinkage` take stock out and need a negative delta; `return` and `restock` need a positive one.
+	Reason StockReason `json:"reason"`
+
+	// Reference Order, receipt or stock count behind the adjustment.
+	Reference *string `json:"reference,omitempty"`
+}
+
+// Defines values for StockReason.
+const (
+	Restock   StockReason = "restock"
+	Return    StockReason = "return"
+	Sale      StockReason = "sale"
+	Shrinkage StockReason = "shrinkage"
+)
+
+// StockReason Why stock changed. `sale` and `shrinkage` take stock out and need a negative delta; `return` and `restock` need a positive one.
+type StockReason string
+
+// Tag defines model for Tag.
+type Tag struct {
+	CreatedAt time.Time          `json:"createdAt"`
+	Id        openapi_types.UUID `json:"id"`
+
+	// Name Tag name in lowercase.
+	Name string `json:"name"`
+}
+
+// TagCreate defines model for TagCreate.
+type TagCreate struct {
+	Name string `json:"name"`
+}
+
+// TagUpdate defines model for TagUpdate.
+type TagUpdate struct {
+	Name string `json:"name"`
+}
+
+// Violation defines model for Violation.
+type Violation = domain.Violation
+
+// IfMatch defines model for IfMatch.
+type IfMatch = string
+
+// PriceCurrency defines model for PriceCurrency.
+type PriceCurrency = string
+
 // BadRequest defines model for BadRequest.
-type BadRequest = Error
+type BadRequest = Problem
+
+// Conflict defines model for Conflict.
+type Conflict = Problem
 
 // NotFound defines model for NotFound.
-type NotFound = Error
+type NotFound = Problem
+
+// PreconditionFailed defines model for PreconditionFailed.
+type PreconditionFailed = Problem
+
+// PreconditionRequired defines model for PreconditionRequired.
+type PreconditionRequired = Problem
+
+// ListBooksParams defines parameters for ListBooks.
+type ListBooksParams struct {
+	// Author Case-insensitive substring of the author name.
+	Author *string `form:"author,omitempty" json:"author,omitempty"`
+
+	// Category Only return books filed under this category or one of its subcategories.
+	Category *openapi_types.UUID `form:"category,omitempty" json:"category,omitempty"`
+
+	// Tag Only return books with this tag, ignoring case.
+	Tag *string `form:"tag,omitempty" json:"tag,omitempty"`
+
+	// Currency Only return books whose base price is in this ISO 4217 currency. Unlike on other read endpoints, prices are not converted.
+	Currency *string `form:"currency,omitempty" json:"currency,omitempty"`
+
+	// MinPrice Minimum price, inclusive.
+	MinPrice *string `form:"minPrice,omitempty" json:"minPrice,omitempty"`
+
+	// MaxPrice Maximum price, inclusive.
+	MaxPrice *string `form:"maxPrice,omitempty" json:"maxPrice,omitempty"`
+
+	// InStock Only return books with stock greater than zero.
+	InStock *bool `form:"inStock,omitempty" json:"inStock,omitempty"`
+
+	// CreatedAfter Only return books created at or after this time.
+	CreatedAfter *time.Time `form:"createdAfter,omitempty" json:"createdAfter,omitempty"`
+
+	// CreatedBefore Only return books created before this time.
+	CreatedBefore *time.Time `form:"createdBefore,omitempty" json:"createdBefore,omitempty"`
+
+	// UpdatedAfter Only return books updated at or after this time.
+	UpdatedAfter *time.Time `form:"updatedAfter,omitempty" json:"updatedAfter,omitempty"`
+
+	// UpdatedBefore Only return books updated before this time.
+	UpdatedBefore *time.Time `form:"updatedBefore,omitempty" json:"updatedBefore,omitempty"`
+
+	// IncludeDeleted Also return soft-deleted books.
+	IncludeDeleted *bool `form:"includeDeleted,omitempty" json:"includeDeleted,omitempty"`
+
+	// Sort Comma-separated sort fields (`title`, `price`, `stock`, `createdAt`, `updatedAt`), at most three. Prefix a field with `-` for descending order. Defaults to `createdAt`.
+	Sort *string `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// Limit Maximum number of books to return.
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Opaque cursor taken from `nextCursor` or `prevCursor` of a previous page.
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// SearchBooksParams defines parameters for SearchBooks.
+type SearchBooksParams struct {
+	// Q Search terms; supports quoted phrases, `OR` and `-` exclusions.
+	Q string `form:"q" json:"q"`
+
+	// Limit Maximum number of results to return.
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Offset Number of results to skip.
+	Offset *int `form:"offset,omitempty" json:"offset,omitempty"`
+
+	// Currency ISO 4217 currency to quote prices in, as `quotedPrice`: the book's explicit price in that currency, or its base price converted with the stored exchange rates. Fails with 400 when a book cannot be converted.
+	Currency *PriceCurrency `form:"currency,omitempty" json:"currency,omitempty"`
+}
+
+// ExportBooksParams defines parameters for ExportBooks.
+type ExportBooksParams struct {
+	// Author Case-insensitive substring of the author name.
+	Author *string `form:"author,omitempty" json:"author,omitempty"`
+
+	// Category Only return books filed under this category or one of its subcategories.
+	Category *openapi_types.UUID `form:"category,omitempty" json:"category,omitempty"`
+
+	// Tag Only return books with this tag, ignoring case.
+	Tag *string `form:"tag,omitempty" json:"tag,omitempty"`
+
+	// Currency Only return books whose base price is in this ISO 4217 currency. Unlike on other read endpoints, prices are not converted.
+	Currency *string `form:"currency,omitempty" json:"currency,omitempty"`
+
+	// MinPrice Minimum price, inclusive.
+	MinPrice *string `form:"minPrice,omitempty" json:"minPrice,omitempty"`
+
+	// MaxPrice Maximum price, inclusive.
+	MaxPrice *string `form:"maxPrice,omitempty" json:"maxPrice,omitempty"`
+
+	// InStock Only return books with stock greater than zero.
+	InStock *bool `form:"inStock,omitempty" json:"inStock,omitempty"`
+
+	// CreatedAfter Only return books created at or after this time.
+	CreatedAfter *time.Time `form:"createdAfter,omitempty" json:"createdAfter,omitempty"`
+
+	// CreatedBefore Only return books created before this time.
+	CreatedBefore *time.Time `form:"createdBefore,omitempty" json:"createdBefore,omitempty"`
+
+	// UpdatedAfter Only return books updated at or after this time.
+	UpdatedAfter *time.Time `form:"updatedAfter,omitempty" json:"updatedAfter,omitempty"`
+
+	// UpdatedBefore Only return books updated before this time.
+	UpdatedBefore *time.Time `form:"updatedBefore,omitempty" json:"updatedBefore,omitempty"`
+
+	// IncludeDeleted Also return soft-deleted books.
+	IncludeDeleted *bool `form:"includeDeleted,omitempty" json:"includeDeleted,omitempty"`
+
+	// Sort Comma-separated sort fields (`title`, `price`, `stock`, `createdAt`, `updatedAt`), at most three. Prefix a field with `-` for descending order. Defaults to `createdAt`.
+	Sort *string `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// Format File format of the export.
+	Format *ExportBooksParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+}
+
+// Defines values for ExportBooksParamsFormat.
+const (
+	Csv    ExportBooksParamsFormat = "csv"
+	Json   ExportBooksParamsFormat = "json"
+	Ndjson ExportBooksParamsFormat = "ndjson"
+)
+
+// ExportBooksParamsFormat defines parameters for ExportBooks.
+type ExportBooksParamsFormat string
+
+// GetBookByIsbnParams defines parameters for GetBookByIsbn.
+type GetBookByIsbnParams struct {
+	// Currency ISO 4217 currency to quote prices in, as `quotedPrice`: the book's explicit price in that currency, or its base price converted with the stored exchange rates. Fails with 400 when a book cannot be converted.
+	Currency *PriceCurrency `form:"currency,omitempty" json:"currency,omitempty"`
+}
+
+// ListBookHistoryParams defines parameters for ListBookHistory.
+type ListBookHistoryParams struct {
+	// Limit Maximum number of revisions to return.
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Opaque cursor taken from `nextCursor` of a previous page.
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// ListStockAdjustmentsParams defines parameters for ListStockAdjustments.
+type ListStockAdjustmentsParams struct {
+	// Limit Maximum number of adjustments to return.
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Opaque cursor taken from `nextCursor` of a previous page.
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// DeleteBookParams defines parameters for DeleteBook.
+type DeleteBookParams struct {
+	// IfMatch ETag of the book version the change applies to, or `*`. The request fails with 412 if the book has been modified since, and with 400 if the header is not `*` or a single strong entity tag.
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// GetBookParams defines parameters for GetBook.
+type GetBookParams struct {
+	// Currency ISO 4217 currency to quote prices in, as `quotedPrice`: the book's explicit price in that currency, or its base price converted with the stored exchange rates. Fails with 400 when a book cannot be converted.
+	Currency *PriceCurrency `form:"currency,omitempty" json:"currency,omitempty"`
+}
+
+// UpdateBookParams defines parameters for UpdateBook.
+type UpdateBookParams struct {
+	// IfMatch ETag of the book version the change applies to, or `*`. The request fails with 412 if the book has been modified since, and with 400 if the header is not `*` or a single strong entity tag.
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// ListAuthorsParams defines parameters for ListAuthors.
+type ListAuthorsParams struct {
+	// Name Case-insensitive substring of the author name.
+	Name *string `form:"name,omitempty" json:"name,omitempty"`
+
+	// Limit Maximum number of authors to return.
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Opaque cursor taken from `nextCursor` of a previous page.
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// ListAuthorBooksParams defines parameters for ListAuthorBooks.
+type ListAuthorBooksParams struct {
+	// Limit Maximum number of books to return.
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Opaque cursor taken from `nextCursor` or `prevCursor` of a previous page.
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// Currency ISO 4217 currency to quote prices in, as `quotedPrice`: the book's explicit price in that currency, or its base price converted with the stored exchange rates. Fails with 400 when a book cannot be converted.
+	Currency *PriceCurrency `form:"currency,omitempty" json:"currency,omitempty"`
+}
+
+// ListTagsParams defines parameters for ListTags.
+type ListTagsParams struct {
+	// Prefix Only return tags starting with this prefix, ignoring case.
+	Prefix *string `form:"prefix,omitempty" json:"prefix,omitempty"`
+}
 
 // CreateBookJSONRequestBody defines body for CreateBook for application/json ContentType.
 type CreateBookJSONRequestBody = BookCreate
 
 // UpdateBookJSONRequestBody defines body for UpdateBook for application/json ContentType.
 type UpdateBookJSONRequestBody = BookUpdate
+
+// AdjustStockJSONRequestBody defines body for AdjustStock for application/json ContentType.
+type AdjustStockJSONRequestBody = StockAdjustmentCreate
+
+// BatchCreateBooksJSONRequestBody defines body for BatchCreateBooks for application/json ContentType.
+type BatchCreateBooksJSONRequestBody = BatchCreateBooksRequest
+
+// CreateAuthorJSONRequestBody defines body for CreateAuthor for application/json ContentType.
+type CreateAuthorJSONRequestBody = AuthorCreate
+
+// UpdateAuthorJSONRequestBody defines body for UpdateAuthor for application/json ContentType.
+type UpdateAuthorJSONRequestBody = AuthorUpdate
+
+// CreateCategoryJSONRequestBody defines body for CreateCategory for application/json ContentType.
+type CreateCategoryJSONRequestBody = CategoryCreate
+
+// UpdateCategoryJSONRequestBody defines body for UpdateCategory for application/json ContentType.
+type UpdateCategoryJSONRequestBody = CategoryUpdate
+
+// CreateTagJSONRequestBody defines body for CreateTag for application/json ContentType.
+type CreateTagJSONRequestBody = TagCreate
+
+// UpdateTagJSONRequestBody defines body for UpdateTag for application/json ContentType.
+type UpdateTagJSONRequestBody = TagUpdate
+
+// LoadFxRatesJSONRequestBody defines body for LoadFxRates for application/json ContentType.
+type LoadFxRatesJSONRequestBody = FxRatesLoad
@@ -62,7 +62,7 @@ export function BookForm({ mode, book }: BookFormProps) {
       const payloadBase: BookCreate = {
         title: form.title.trim(),
         author: form.author.trim(),
-        price: Number(form.price),
+        price: form.price.trim(),
         currency: form.currency.trim().toUpperCase(),
         stock: Number(form.stock)
       };
@@ -129,7 +129,7 @@ export function BookForm({ mode, book }: BookFormProps) {
             name="price"
             type="number"
             min="0"
-            step="0.01"
+            step="any"
             required
             value={form.price}
             onChange={handleChange}
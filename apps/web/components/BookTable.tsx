@@ -28,7 +28,7 @@ export function BookTable({ books }: BookTableProps) {
               <td>{book.title}</td>
               <td>{book.author}</td>
               <td>
-                <strong>{book.currency}</strong> {Number(book.price).toFixed(2)}
+                <strong>{book.currency}</strong> {book.price}
               </td>
               <td>
                 <span className="status-pill">{book.stock} in stock</span>
@@ -33,16 +33,18 @@ export const client = createClient<paths>({
 export type Book = components['schemas']['Book'];
 export type BookCreate = components['schemas']['BookCreate'];
 export type BookUpdate = components['schemas']['BookUpdate'];
-export type ErrorResponse = components['schemas']['Error'];
+export type Problem = components['schemas']['Problem'];
+export type Violation = components['schemas']['Violation'];
 
 function extractErrorMessage(error: unknown): string {
-  if (error && typeof error === 'object' && 'message' in error && typeof error.message === 'string') {
-    return error.message;
+  if (!error || typeof error !== 'object') {
+    return 'Unknown error';
   }
-  if (error && typeof error === 'object' && 'detail' in error && typeof error.detail === 'string') {
-    return error.detail;
+  const problem = error as Partial<Problem>;
+  if (problem.violations?.length) {
+    return problem.violations.map((violation) => `${violation.field}: ${violation.message}`).join('; ');
   }
-  return 'Unknown error';
+  return problem.detail ?? problem.title ?? 'Unknown error';
 }
 
 export async function listBooks() {
@@ -11,6 +11,27 @@ export interface paths {
     /** Create a book */
     post: operations["createBook"];
   };
+  "/books/search": {
+    /** Search books by title and author */
+    get: operations["searchBooks"];
+  };
+  "/books/export": {
+    /**
+     * Export books as CSV, NDJSON or JSON
+     * @description Streams every book matching the filters as a file attachment. Takes the same filters and sort as listing books, without pagination.
+     */
+    get: operations["exportBooks"];
+  };
+  "/books/by-isbn/{isbn}": {
+    /** Get a book by ISBN */
+    get: operations["getBookByIsbn"];
+    parameters: {
+      path: {
+        /** @description ISBN-10 or ISBN-13, with or without hyphens. */
+        isbn: string;
+      };
+    };
+  };
   "/books/{id}": {
     /** Get a book */
     get: operations["getBook"];
@@ -24,6 +45,162 @@ export interface paths {
       };
     };
   };
+  "/books/{id}/history": {
+    /**
+     * List the revision history of a book
+     * @description Returns one revision per create, update, delete and restore, newest first. Deleted books keep their history.
+     */
+    get: operations["listBookHistory"];
+    parameters: {
+      path: {
+        id: string;
+      };
+    };
+  };
+  "/books/{id}/stock/adjustments": {
+    /**
+     * List the stock ledger of a book
+     * @description Returns the stock adjustments of a book, newest first.
+     */
+    get: operations["listStockAdjustments"];
+    /**
+     * Adjust the stock of a book
+     * @description Adds a signed delta to the current stock and records it in the stock ledger. Concurrent adjustments never overwrite each other, and an adjustment that would take stock below zero fails with 409.
+     */
+    post: operations["adjustStock"];
+    parameters: {
+      path: {
+        id: string;
+      };
+    };
+  };
+  "/books/{id}:restore": {
+    /** Restore a deleted book */
+    post: operations["restoreBook"];
+    parameters: {
+      path: {
+        id: string;
+      };
+    };
+  };
+  "/books:batchCreate": {
+    /**
+     * Create many books at once
+     * @description Validates each item on its own and creates the valid ones in a single transaction. Invalid items are reported in `results` and do not stop the rest of the batch.
+     */
+    post: operations["batchCreateBooks"];
+  };
+  "/authors": {
+    /**
+     * List authors
+     * @description Returns authors ordered by name, ignoring case.
+     */
+    get: operations["listAuthors"];
+    /** Create an author */
+    post: operations["createAuthor"];
+  };
+  "/authors/{id}": {
+    /** Get an author */
+    get: operations["getAuthor"];
+    /**
+     * Rename an author
+     * @description Books list the author under the new name. Their `author` credit line keeps the name as it was printed.
+     */
+    put: operations["updateAuthor"];
+    /**
+     * Delete an author
+     * @description Fails with 409 while the author is credited on any book, including deleted ones.
+     */
+    delete: operations["deleteAuthor"];
+    parameters: {
+      path: {
+        id: string;
+      };
+    };
+  };
+  "/authors/{id}/books": {
+    /**
+     * List the books of an author
+     * @description Returns the books crediting the author in any role, ordered like `GET /books`.
+     */
+    get: operations["listAuthorBooks"];
+    parameters: {
+      path: {
+        id: string;
+      };
+    };
+  };
+  "/categories": {
+    /**
+     * List categories
+     * @description Returns every category ordered by name, ignoring case. Build the tree from `parentId`.
+     */
+    get: operations["listCategories"];
+    /** Create a category */
+    post: operations["createCategory"];
+  };
+  "/categories/{id}": {
+    /** Get a category */
+    get: operations["getCategory"];
+    /**
+     * Rename or move a category
+     * @description Moves the category, with its subcategories, under `parentId`. The new parent cannot be the category itself or one of its subcategories.
+     */
+    put: operations["updateCategory"];
+    /**
+     * Delete a category
+     * @description Fails with 409 while the category has subcategories or books, including deleted ones.
+     */
+    delete: operations["deleteCategory"];
+    parameters: {
+      path: {
+        id: string;
+      };
+    };
+  };
+  "/tags": {
+    /**
+     * List tags
+     * @description Returns tags ordered by name.
+     */
+    get: operations["listTags"];
+    /**
+     * Create a tag
+     * @description Tags are also created when a book first uses them.
+     */
+    post: operations["createTag"];
+  };
+  "/tags/{id}": {
+    /**
+     * Rename a tag
+     * @description Books with the tag list it under the new name.
+     */
+    put: operations["updateTag"];
+    /**
+     * Delete a tag
+     * @description Fails with 409 while any book, including deleted ones, has the tag.
+     */
+    delete: operations["deleteTag"];
+    parameters: {
+      path: {
+        id: string;
+      };
+    };
+  };
+  "/fx-rates": {
+    /**
+     * List exchange rates
+     * @description Returns every stored rate ordered by base and quote currency.
+     */
+    get: operations["listFxRates"];
+  };
+  "/fx-rates:load": {
+    /**
+     * Load exchange rates
+     * @description Stores rates quoted against one base currency, replacing the previous rate of each pair. Pairs not in the request keep their rate. The `api fx-rates -source FILE_OR_URL` command loads the same document.
+     */
+    post: operations["loadFxRates"];
+  };
 }
 
 export type webhooks = Record<string, never>;
@@ -34,52 +211,421 @@ export interface components {
       /** Format: uuid */
       id: string;
       title: string;
+      /** @description Credit line as printed on the book. */
       author: string;
-      price: number;
+      /** @description Credited authors in credit order, under their current names. */
+      authors?: components["schemas"]["BookAuthor"][];
+      /** @description Categories the book is filed under, ordered by name. */
+      categories?: components["schemas"]["BookCategory"][];
+      /** @description Tags of the book, in lowercase and ordered by name. */
+      tags?: string[];
+      /**
+       * @description ISBN-13 without hyphens. Omitted when the book has no ISBN.
+       * @example 9780306406157
+       */
+      isbn?: string;
+      /**
+       * @description Exact decimal amount in major units of `currency`, with at most the currency's minor-unit decimal places (0 for JPY, 2 for USD, 3 for KWD).
+       * @example 24.99
+       */
+      price: string;
       /** @default USD */
       currency: string;
+      /** @description Explicit prices in currencies other than `currency`, ordered by currency. */
+      prices?: components["schemas"]["Price"][];
+      quotedPrice?: components["schemas"]["QuotedPrice"];
       stock: number;
       /** Format: date-time */
       createdAt: string;
       /** Format: date-time */
       updatedAt: string;
+      /**
+       * Format: int64
+       * @description Incremented on every update; also returned as the `ETag` header.
+       */
+      version: number;
+      /**
+       * Format: date-time
+       * @description Set when the book has been soft-deleted.
+       */
+      deletedAt?: string;
+    };
+    BookSearchResult: {
+      book: components["schemas"]["Book"];
+      /**
+       * Format: float
+       * @description Relevance score; higher is better.
+       */
+      rank: number;
+      /** @description Title and author, HTML-escaped, with matching terms wrapped in `<mark>` tags. */
+      snippet: string;
+    };
+    BookRevision: {
+      /**
+       * Format: int64
+       * @description Book version produced by this change.
+       */
+      version: number;
+      /** @enum {string} */
+      action: "create" | "update" | "delete" | "restore";
+      /** @description Caller named by the `X-Actor` header, or `anonymous`. */
+      actor: string;
+      /** Format: date-time */
+      changedAt: string;
+      changes: components["schemas"]["FieldChange"][];
+      snapshot: components["schemas"]["Book"];
+    };
+    FieldChange: {
+      field: string;
+      /** @description Value before the change; null for fields set on create. */
+      before: unknown;
+      /** @description Value after the change. */
+      after: unknown;
     };
     BookCreate: {
       title: string;
-      author: string;
-      price: number;
+      /** @description Credit line. Required unless `authors` is given, in which case it defaults to the names of those credited as author. Without `authors`, the book is credited to the author of this name, who is created if needed. */
+      author?: string;
+      /** @description Authors to credit, in credit order. */
+      authors?: components["schemas"]["BookAuthorCreate"][];
+      /** @description Categories to file the book under. */
+      categoryIds?: string[];
+      /** @description Tags of the book. Names are stored in lowercase; tags that do not exist yet are created. */
+      tags?: string[];
+      /**
+       * @description ISBN-10 or ISBN-13, with or without hyphens. Stored and returned as ISBN-13; must be unique across books.
+       * @example 978-0-306-40615-7
+       */
+      isbn?: string;
+      /**
+       * @description Exact decimal amount in major units of `currency`, with at most the currency's minor-unit decimal places (0 for JPY, 2 for USD, 3 for KWD).
+       * @example 24.99
+       */
+      price: string;
       /** @default USD */
       currency: string;
+      /** @description Explicit prices in currencies other than `currency`. Other currencies are converted from the base price. */
+      prices?: components["schemas"]["Price"][];
       stock: number;
     };
     BookUpdate: {
       title?: string;
+      /** @description Credit line. Changing it without `authors` credits the book to the author of this name in place of its previous authors. */
       author?: string;
-      price?: number;
+      /** @description Replaces the credited authors. Without `author`, the credit line is derived from their names. */
+      authors?: components["schemas"]["BookAuthorCreate"][];
+      /** @description Replaces the categories; an empty list removes them all. */
+      categoryIds?: string[];
+      /** @description Replaces the tags; an empty list removes them all. */
+      tags?: string[];
+      /** @description ISBN-10 or ISBN-13, with or without hyphens. An empty string removes the ISBN. */
+      isbn?: string;
+      /**
+       * @description Exact decimal amount in major units of `currency`, with at most the currency's minor-unit decimal places (0 for JPY, 2 for USD, 3 for KWD).
+       * @example 24.99
+       */
+      price?: string;
       currency?: string;
+      /** @description Replaces the explicit prices; an empty list removes them all. */
+      prices?: components["schemas"]["Price"][];
       stock?: number;
     };
-    Error: {
+    BatchCreateBooksRequest: {
+      items: components["schemas"]["BookCreate"][];
+    };
+    BatchCreateBooksResponse: {
+      /** @description Number of books created. */
+      created: number;
+      /** @description Number of items rejected by validation. */
+      failed: number;
+      /** @description One entry per request item, in request order. */
+      results: components["schemas"]["BatchCreateBookResult"][];
+    };
+    BatchCreateBookResult: {
+      /** @description Position of the item in the request. */
+      index: number;
+      book?: components["schemas"]["Book"];
+      /** @description The invalid fields of the item, sorted by field; set when the item was not created. */
+      errors?: components["schemas"]["Violation"][];
+    };
+    /**
+     * @description Why stock changed. `sale` and `shrinkage` take stock out and need a negative delta; `return` and `restock` need a positive one.
+     * @enum {string}
+     */
+    StockReason: "sale" | "return" | "restock" | "shrinkage";
+    StockAdjustmentCreate: {
+      /** @description Signed, non-zero change to the stock. */
+      delta: number;
+      reason: components["schemas"]["StockReason"];
+      /** @description Order, receipt or stock count behind the adjustment. */
+      reference?: string;
+    };
+    StockAdjustment: {
+      /** Format: uuid */
+      id: string;
+      /** Format: uuid */
+      bookId: string;
+      delta: number;
+      reason: components["schemas"]["StockReason"];
+      reference: string;
+      /** @description Caller named by the `X-Actor` header, or `anonymous`. */
+      actor: string;
+      /** @description Stock of the book once the adjustment was applied. */
+      stockAfter: number;
+      /**
+       * Format: int64
+       * @description Book version produced by the adjustment.
+       */
+      bookVersion: number;
+      /** Format: date-time */
+      createdAt: string;
+    };
+    Author: {
+      /** Format: uuid */
+      id: string;
+      name: string;
+      /** Format: date-time */
+      createdAt: string;
+      /** Format: date-time */
+      updatedAt: string;
+    };
+    AuthorCreate: {
+      name: string;
+    };
+    AuthorUpdate: {
+      name: string;
+    };
+    /**
+     * @description Part the author played in the book.
+     * @enum {string}
+     */
+    AuthorRole: "author" | "editor" | "translator";
+    BookAuthor: {
+      /** Format: uuid */
+      authorId: string;
+      /** @description Current name of the author. */
+      name: string;
+      role: components["schemas"]["AuthorRole"];
+    };
+    /** @description Credits an author on a book. `role` defaults to `author`. */
+    BookAuthorCreate: {
+      /** Format: uuid */
+      authorId: string;
+      role?: components["schemas"]["AuthorRole"];
+    };
+    Category: {
+      /** Format: uuid */
+      id: string;
+      /**
+       * Format: uuid
+       * @description Parent category; omitted for top-level categories.
+       */
+      parentId?: string;
+      name: string;
+      /** Format: date-time */
+      createdAt: string;
+      /** Format: date-time */
+      updatedAt: string;
+    };
+    CategoryCreate: {
+      /**
+       * Format: uuid
+       * @description Parent category; omit to create a top-level category.
+       */
+      parentId?: string;
+      /** @description Unique among the categories sharing the parent, ignoring case. */
+      name: string;
+    };
+    CategoryUpdate: {
+      /**
+       * Format: uuid
+       * @description New parent category; omit to make the category top-level.
+       */
+      parentId?: string;
+      name: string;
+    };
+    BookCategory: {
+      /** Format: uuid */
+      categoryId: string;
+      /** @description Current name of the category. */
+      name: string;
+    };
+    Tag: {
+      /** Format: uuid */
+      id: string;
+      /** @description Tag name in lowercase. */
+      name: string;
+      /** Format: date-time */
+      createdAt: string;
+    };
+    TagCreate: {
+      name: string;
+    };
+    TagUpdate: {
+      name: string;
+    };
+    Price: {
+      /**
+       * @description Exact decimal amount in major units of `currency`, with at most the currency's minor-unit decimal places.
+       * @example 19.99
+       */
+      price: string;
+      currency: string;
+    };
+    /**
+     * @description `explicit` for the book's base price or a price set for the currency, `converted` for the base price converted with the stored exchange rates.
+     * @enum {string}
+     */
+    PriceKind: "explicit" | "converted";
+    /** @description Price in the currency requested with `currency`. */
+    QuotedPrice: {
+      /** @example 21.65 */
+      price: string;
+      currency: string;
+      kind: components["schemas"]["PriceKind"];
+      /**
+       * @description Exchange rate from the base currency applied to a converted price; omitted for explicit prices.
+       * @example 1.0832
+       */
+      rate?: string;
+    };
+    FxRate: {
+      base: string;
+      quote: string;
+      /**
+       * @description Units of `quote` one unit of `base` is worth.
+       * @example 1.0832
+       */
+      rate: string;
+      /** Format: date-time */
+      updatedAt: string;
+    };
+    FxRatesLoad: {
+      /** @example EUR */
+      base: string;
+      /**
+       * @description Rates by quote currency: units of the quote currency one unit of `base` is worth, as decimals with at most 12 places.
+       * @example {
+       *   "USD": "1.0832",
+       *   "GBP": "0.8571"
+       * }
+       */
+      rates: {
+        [key: string]: string;
+      };
+    };
+    /** @description RFC 9457 problem details. */
+    Problem: {
+      /**
+       * Format: uri
+       * @description URI naming the kind of problem; ends in `code`.
+       * @example https://bookapi.example.com/problems/validation_failed
+       */
+      type: string;
+      /**
+       * @description Reason phrase of the status code.
+       * @example Bad Request
+       */
+      title: string;
+      /** @example 400 */
+      status: number;
+      /**
+       * @description Stable, machine-readable kind of problem: `validation_failed` for requests with invalid fields, otherwise the reason phrase of the status in snake case, such as `not_found`, `conflict` or `precondition_failed`.
+       * @example validation_failed
+       */
+      code: string;
+      /**
+       * @description Explanation of this occurrence of the problem.
+       * @example the request has invalid fields
+       */
+      detail?: string;
+      /**
+       * @description ID of the request, as in the `X-Request-ID` header.
+       * @example 3f0c7a52-5d8e-4b59-9a43-1d2c7be0f6a1
+       */
+      requestId?: string;
+      /** @description The invalid fields of a `validation_failed` problem. */
+      violations?: components["schemas"]["Violation"][];
+    };
+    Violation: {
+      /**
+       * @description Path of the invalid field in the body or the name of the parameter.
+       * @example authors[1].role
+       */
+      field: string;
+      /**
+       * @description What is wrong with the field. `required`: missing or empty. `invalid`: wrong in a way no other code covers. `invalid_type`: wrong JSON type. `invalid_format`: not a valid UUID, decimal, currency code, ISBN or the like. `invalid_length`: too short or too long, or too few or too many items. `out_of_range`: number or time outside the allowed range. `invalid_choice`: not one of the allowed values. `invalid_precision`: too many decimal places. `duplicate`: repeats another value of the request. `unknown_reference`: refers to something that does not exist. `unknown_field`: not a field of the request.
+       * @example invalid_choice
+       * @enum {string}
+       */
+      code: "required" | "invalid" | "invalid_type" | "invalid_format" | "invalid_length" | "out_of_range" | "invalid_choice" | "invalid_precision" | "duplicate" | "unknown_reference" | "unknown_field";
+      /**
+       * @description Explanation for people; may change, unlike `code`.
+       * @example must be one of author, editor, translator
+       */
       message: string;
     };
   };
   responses: {
     /** @description Bad request */
     BadRequest: {
+      headers: {
+        "X-Request-ID": components["headers"]["RequestID"];
+      };
       content: {
-        "application/json": components["schemas"]["Error"];
+        "application/problem+json": components["schemas"]["Problem"];
+      };
+    };
+    /** @description The request conflicts with the current state of the resource */
+    Conflict: {
+      headers: {
+        "X-Request-ID": components["headers"]["RequestID"];
+      };
+      content: {
+        "application/problem+json": components["schemas"]["Problem"];
       };
     };
     /** @description Resource not found */
     NotFound: {
+      headers: {
+        "X-Request-ID": components["headers"]["RequestID"];
+      };
+      content: {
+        "application/problem+json": components["schemas"]["Problem"];
+      };
+    };
+    /** @description The If-Match header does not match the current book version */
+    PreconditionFailed: {
+      headers: {
+        "X-Request-ID": components["headers"]["RequestID"];
+      };
+      content: {
+        "application/problem+json": components["schemas"]["Problem"];
+      };
+    };
+    /** @description The server requires an If-Match header for this request */
+    PreconditionRequired: {
+      headers: {
+        "X-Request-ID": components["headers"]["RequestID"];
+      };
       content: {
-        "application/json": components["schemas"]["Error"];
+        "application/problem+json": components["schemas"]["Problem"];
       };
     };
   };
-  parameters: never;
+  parameters: {
+    /** @description ETag of the book version the change applies to, or `*`. The request fails with 412 if the book has been modified since, and with 400 if the header is not `*` or a single strong entity tag. */
+    IfMatch?: string;
+    /** @description ISO 4217 currency to quote prices in, as `quotedPrice`: the book's explicit price in that currency, or its base price converted with the stored exchange rates. Fails with 400 when a book cannot be converted. */
+    PriceCurrency?: string;
+  };
   requestBodies: never;
-  headers: never;
+  headers: {
+    /** @description Strong entity tag of the returned book version. */
+    ETag: string;
+    /** @description ID of the request, repeated as `requestId` in error bodies. */
+    RequestID: string;
+  };
   pathItems: never;
 }
 
@@ -91,12 +637,50 @@ export interface operations {
 
   /** List books */
   listBooks: {
+    parameters: {
+      query?: {
+        /** @description Case-insensitive substring of the author name. */
+        author?: string;
+        /** @description Only return books filed under this category or one of its subcategories. */
+        category?: string;
+        /** @description Only return books with this tag, ignoring case. */
+        tag?: string;
+        /** @description Only return books whose base price is in this ISO 4217 currency. Unlike on other read endpoints, prices are not converted. */
+        currency?: string;
+        /** @description Minimum price, inclusive. */
+        minPrice?: string;
+        /** @description Maximum price, inclusive. */
+        maxPrice?: string;
+        /** @description Only return books with stock greater than zero. */
+        inStock?: boolean;
+        /** @description Only return books created at or after this time. */
+        createdAfter?: string;
+        /** @description Only return books created before this time. */
+        createdBefore?: string;
+        /** @description Only return books updated at or after this time. */
+        updatedAfter?: string;
+        /** @description Only return books updated before this time. */
+        updatedBefore?: string;
+        /** @description Also return soft-deleted books. */
+        includeDeleted?: boolean;
+        /** @description Comma-separated sort fields (`title`, `price`, `stock`, `createdAt`, `updatedAt`), at most three. Prefix a field with `-` for descending order. Defaults to `createdAt`. */
+        sort?: string;
+        /** @description Maximum number of books to return. */
+        limit?: number;
+        /** @description Opaque cursor taken from `nextCursor` or `prevCursor` of a previous page. */
+        cursor?: string;
+      };
+    };
     responses: {
-      /** @description A list of books */
+      /** @description A page of books matching the filters */
       200: {
         content: {
           "application/json": {
             books: components["schemas"]["Book"][];
+            /** @description Cursor for the following page; omitted on the last page. */
+            nextCursor?: string;
+            /** @description Cursor for the preceding page; omitted on the first page. */
+            prevCursor?: string;
           };
         };
       };
@@ -113,16 +697,128 @@ export interface operations {
     responses: {
       /** @description Book created */
       201: {
+        headers: {
+          ETag: components["headers"]["ETag"];
+        };
         content: {
           "application/json": components["schemas"]["Book"];
         };
       };
       400: components["responses"]["BadRequest"];
+      409: components["responses"]["Conflict"];
+    };
+  };
+  /** Search books by title and author */
+  searchBooks: {
+    parameters: {
+      query: {
+        /** @description Search terms; supports quoted phrases, `OR` and `-` exclusions. */
+        q: string;
+        /** @description Maximum number of results to return. */
+        limit?: number;
+        /** @description Number of results to skip. */
+        offset?: number;
+        currency?: components["parameters"]["PriceCurrency"];
+      };
+    };
+    responses: {
+      /** @description Matching books, most relevant first */
+      200: {
+        content: {
+          "application/json": {
+            results: components["schemas"]["BookSearchResult"][];
+          };
+        };
+      };
+      400: components["responses"]["BadRequest"];
+    };
+  };
+  /**
+   * Export books as CSV, NDJSON or JSON
+   * @description Streams every book matching the filters as a file attachment. Takes the same filters and sort as listing books, without pagination.
+   */
+  exportBooks: {
+    parameters: {
+      query?: {
+        /** @description Case-insensitive substring of the author name. */
+        author?: string;
+        /** @description Only return books filed under this category or one of its subcategories. */
+        category?: string;
+        /** @description Only return books with this tag, ignoring case. */
+        tag?: string;
+        /** @description Only return books whose base price is in this ISO 4217 currency. Unlike on other read endpoints, prices are not converted. */
+        currency?: string;
+        /** @description Minimum price, inclusive. */
+        minPrice?: string;
+        /** @description Maximum price, inclusive. */
+        maxPrice?: string;
+        /** @description Only return books with stock greater than zero. */
+        inStock?: boolean;
+        /** @description Only return books created at or after this time. */
+        createdAfter?: string;
+        /** @description Only return books created before this time. */
+        createdBefore?: string;
+        /** @description Only return books updated at or after this time. */
+        updatedAfter?: string;
+        /** @description Only return books updated before this time. */
+        updatedBefore?: string;
+        /** @description Also return soft-deleted books. */
+        includeDeleted?: boolean;
+        /** @description Comma-separated sort fields (`title`, `price`, `stock`, `createdAt`, `updatedAt`), at most three. Prefix a field with `-` for descending order. Defaults to `createdAt`. */
+        sort?: string;
+        /** @description File format of the export. */
+        format?: "csv" | "ndjson" | "json";
+      };
+    };
+    responses: {
+      /** @description Every matching book, streamed as an attachment */
+      200: {
+        headers: {
+          /** @description `attachment` with a timestamped file name, e.g. `books-20240101T120000Z.csv`. */
+          "Content-Disposition"?: string;
+        };
+        content: {
+          /** @description Header row `id,title,author,isbn,price,currency,stock,createdAt,updatedAt,version,deletedAt` followed by one row per book. */
+          "text/csv": string;
+          /** @description One JSON-encoded Book per line. */
+          "application/x-ndjson": string;
+          "application/json": components["schemas"]["Book"][];
+        };
+      };
+      400: components["responses"]["BadRequest"];
+    };
+  };
+  /** Get a book by ISBN */
+  getBookByIsbn: {
+    parameters: {
+      query?: {
+        currency?: components["parameters"]["PriceCurrency"];
+      };
+      path: {
+        /** @description ISBN-10 or ISBN-13, with or without hyphens. */
+        isbn: string;
+      };
+    };
+    responses: {
+      /** @description Book details */
+      200: {
+        headers: {
+          ETag: components["headers"]["ETag"];
+        };
+        content: {
+          "application/json": components["schemas"]["Book"];
+        };
+      };
+      400: components["responses"]["BadRequest"];
+      404: components["responses"]["NotFound"];
     };
   };
   /** Get a book */
   getBook: {
     parameters: {
+      query?: {
+        currency?: components["parameters"]["PriceCurrency"];
+      };
       path: {
         id: string;
       };
@@ -130,16 +826,23 @@ export interface operations {
     responses: {
       /** @description Book details */
       200: {
+        headers: {
+          ETag: components["headers"]["ETag"];
+        };
         content: {
           "application/json": components["schemas"]["Book"];
         };
       };
+      400: components["responses"]["BadRequest"];
       404: components["responses"]["NotFound"];
     };
   };
   /** Update a book */
   updateBook: {
     parameters: {
+      header?: {
+        "If-Match"?: components["parameters"]["IfMatch"];
+      };
       path: {
         id: string;
       };
@@ -152,27 +855,538 @@ export interface operations {
     responses: {
       /** @description Updated book */
       200: {
+        headers: {
+          ETag: components["headers"]["ETag"];
+        };
         content: {
           "application/json": components["schemas"]["Book"];
         };
       };
       400: components["responses"]["BadRequest"];
       404: components["responses"]["NotFound"];
+      409: components["responses"]["Conflict"];
+      412: components["responses"]["PreconditionFailed"];
+      428: components["responses"]["PreconditionRequired"];
     };
   };
   /** Delete a book */
   deleteBook: {
+    parameters: {
+      header?: {
+        "If-Match"?: components["parameters"]["IfMatch"];
+      };
+      path: {
+        id: string;
+      };
+    };
+    responses: {
+      /** @description Book deleted. The book is soft-deleted and can be restored until it is purged. */
+      204: {
+        content: never;
+      };
+      404: components["responses"]["NotFound"];
+      412: components["responses"]["PreconditionFailed"];
+      428: components["responses"]["PreconditionRequired"];
+    };
+  };
+  /**
+   * List the revision history of a book
+   * @description Returns one revision per create, update, delete and restore, newest first. Deleted books keep their history.
+   */
+  listBookHistory: {
+    parameters: {
+      query?: {
+        /** @description Maximum number of revisions to return. */
+        limit?: number;
+        /** @description Opaque cursor taken from `nextCursor` of a previous page. */
+        cursor?: string;
+      };
+      path: {
+        id: string;
+      };
+    };
+    responses: {
+      /** @description A page of revisions, newest first */
+      200: {
+        content: {
+          "application/json": {
+            revisions: components["schemas"]["BookRevision"][];
+            /** @description Cursor for older revisions; absent on the last page. */
+            nextCursor?: string;
+          };
+        };
+      };
+      400: components["responses"]["BadRequest"];
+      404: components["responses"]["NotFound"];
+    };
+  };
+  /**
+   * List the stock ledger of a book
+   * @description Returns the stock adjustments of a book, newest first.
+   */
+  listStockAdjustments: {
+    parameters: {
+      query?: {
+        /** @description Maximum number of adjustments to return. */
+        limit?: number;
+        /** @description Opaque cursor taken from `nextCursor` of a previous page. */
+        cursor?: string;
+      };
+      path: {
+        id: string;
+      };
+    };
+    responses: {
+      /** @description A page of stock adjustments, newest first */
+      200: {
+        content: {
+          "application/json": {
+            adjustments: components["schemas"]["StockAdjustment"][];
+            /** @description Cursor for older adjustments; absent on the last page. */
+            nextCursor?: string;
+          };
+        };
+      };
+      400: components["responses"]["BadRequest"];
+      404: components["responses"]["NotFound"];
+    };
+  };
+  /**
+   * Adjust the stock of a book
+   * @description Adds a signed delta to the current stock and records it in the stock ledger. Concurrent adjustments never overwrite each other, and an adjustment that would take stock below zero fails with 409.
+   */
+  adjustStock: {
+    parameters: {
+      path: {
+        id: string;
+      };
+    };
+    requestBody: {
+      content: {
+        "application/json": components["schemas"]["StockAdjustmentCreate"];
+      };
+    };
+    responses: {
+      /** @description Adjustment recorded */
+      201: {
+        headers: {
+          ETag: components["headers"]["ETag"];
+        };
+        content: {
+          "application/json": components["schemas"]["StockAdjustment"];
+        };
+      };
+      400: components["responses"]["BadRequest"];
+      404: components["responses"]["NotFound"];
+      409: components["responses"]["Conflict"];
+    };
+  };
+  /** Restore a deleted book */
+  restoreBook: {
+    parameters: {
+      path: {
+        id: string;
+      };
+    };
+    responses: {
+      /** @description Restored book */
+      200: {
+        headers: {
+          ETag: components["headers"]["ETag"];
+        };
+        content: {
+          "application/json": components["schemas"]["Book"];
+        };
+      };
+      404: components["responses"]["NotFound"];
+      409: components["responses"]["Conflict"];
+    };
+  };
+  /**
+   * Create many books at once
+   * @description Validates each item on its own and creates the valid ones in a single transaction. Invalid items are reported in `results` and do not stop the rest of the batch.
+   */
+  batchCreateBooks: {
+    requestBody: {
+      content: {
+        "application/json": components["schemas"]["BatchCreateBooksRequest"];
+      };
+    };
+    responses: {
+      /** @description Outcome of every item */
+      200: {
+        content: {
+          "application/json": components["schemas"]["BatchCreateBooksResponse"];
+        };
+      };
+      400: components["responses"]["BadRequest"];
+      409: components["responses"]["Conflict"];
+    };
+  };
+  /**
+   * List authors
+   * @description Returns authors ordered by name, ignoring case.
+   */
+  listAuthors: {
+    parameters: {
+      query?: {
+        /** @description Case-insensitive substring of the author name. */
+        name?: string;
+        /** @description Maximum number of authors to return. */
+        limit?: number;
+        /** @description Opaque cursor taken from `nextCursor` of a previous page. */
+        cursor?: string;
+      };
+    };
+    responses: {
+      /** @description A page of authors */
+      200: {
+        content: {
+          "application/json": {
+            authors: components["schemas"]["Author"][];
+            /** @description Cursor for the following page; omitted on the last page. */
+            nextCursor?: string;
+          };
+        };
+      };
+      400: components["responses"]["BadRequest"];
+    };
+  };
+  /** Create an author */
+  createAuthor: {
+    requestBody: {
+      content: {
+        "application/json": components["schemas"]["AuthorCreate"];
+      };
+    };
+    responses: {
+      /** @description Author created */
+      201: {
+        content: {
+          "application/json": components["schemas"]["Author"];
+        };
+      };
+      400: components["responses"]["BadRequest"];
+    };
+  };
+  /** Get an author */
+  getAuthor: {
+    parameters: {
+      path: {
+        id: string;
+      };
+    };
+    responses: {
+      /** @description Author details */
+      200: {
+        content: {
+          "application/json": components["schemas"]["Author"];
+        };
+      };
+      404: components["responses"]["NotFound"];
+    };
+  };
+  /**
+   * Rename an author
+   * @description Books list the author under the new name. Their `author` credit line keeps the name as it was printed.
+   */
+  updateAuthor: {
+    parameters: {
+      path: {
+        id: string;
+      };
+    };
+    requestBody: {
+      content: {
+        "application/json": components["schemas"]["AuthorUpdate"];
+      };
+    };
+    responses: {
+      /** @description Updated author */
+      200: {
+        content: {
+          "application/json": components["schemas"]["Author"];
+        };
+      };
+      400: components["responses"]["BadRequest"];
+      404: components["responses"]["NotFound"];
+    };
+  };
+  /**
+   * Delete an author
+   * @description Fails with 409 while the author is credited on any book, including deleted ones.
+   */
+  deleteAuthor: {
+    parameters: {
+      path: {
+        id: string;
+      };
+    };
+    responses: {
+      /** @description Author deleted */
+      204: {
+        content: never;
+      };
+      404: components["responses"]["NotFound"];
+      409: components["responses"]["Conflict"];
+    };
+  };
+  /**
+   * List the books of an author
+   * @description Returns the books crediting the author in any role, ordered like `GET /books`.
+   */
+  listAuthorBooks: {
+    parameters: {
+      query?: {
+        /** @description Maximum number of books to return. */
+        limit?: number;
+        /** @description Opaque cursor taken from `nextCursor` or `prevCursor` of a previous page. */
+        cursor?: string;
+        currency?: components["parameters"]["PriceCurrency"];
+      };
+      path: {
+        id: string;
+      };
+    };
+    responses: {
+      /** @description A page of books crediting the author */
+      200: {
+        content: {
+          "application/json": {
+            books: components["schemas"]["Book"][];
+            /** @description Cursor for the following page; omitted on the last page. */
+            nextCursor?: string;
+            /** @description Cursor for the preceding page; omitted on the first page. */
+            prevCursor?: string;
+          };
+        };
+      };
+      400: components["responses"]["BadRequest"];
+      404: components["responses"]["NotFound"];
+    };
+  };
+  /**
+   * List categories
+   * @description Returns every category ordered by name, ignoring case. Build the tree from `parentId`.
+   */
+  listCategories: {
+    responses: {
+      /** @description All categories */
+      200: {
+        content: {
+          "application/json": {
+            categories: components["schemas"]["Category"][];
+          };
+        };
+      };
+    };
+  };
+  /** Create a category */
+  createCategory: {
+    requestBody: {
+      content: {
+        "application/json": components["schemas"]["CategoryCreate"];
+      };
+    };
+    responses: {
+      /** @description Category created */
+      201: {
+        content: {
+          "application/json": components["schemas"]["Category"];
+        };
+      };
+      400: components["responses"]["BadRequest"];
+      409: components["responses"]["Conflict"];
+    };
+  };
+  /** Get a category */
+  getCategory: {
+    parameters: {
+      path: {
+        id: string;
+      };
+    };
+    responses: {
+      /** @description Category details */
+      200: {
+        content: {
+          "application/json": components["schemas"]["Category"];
+        };
+      };
+      404: components["responses"]["NotFound"];
+    };
+  };
+  /**
+   * Rename or move a category
+   * @description Moves the category, with its subcategories, under `parentId`. The new parent cannot be the category itself or one of its subcategories.
+   */
+  updateCategory: {
+    parameters: {
+      path: {
+        id: string;
+      };
+    };
+    requestBody: {
+      content: {
+        "application/json": components["schemas"]["CategoryUpdate"];
+      };
+    };
+    responses: {
+      /** @description Updated category */
+      200: {
+        content: {
+          "application/json": components["schemas"]["Category"];
+        };
+      };
+      400: components["responses"]["BadRequest"];
+      404: components["responses"]["NotFound"];
+      409: components["responses"]["Conflict"];
+    };
+  };
+  /**
+   * Delete a category
+   * @description Fails with 409 while the category has subcategories or books, including deleted ones.
+   */
+  deleteCategory: {
+    parameters: {
+      path: {
+        id: string;
+      };
+    };
+    responses: {
+      /** @description Category deleted */
+      204: {
+        content: never;
+      };
+      404: components["responses"]["NotFound"];
+      409: components["responses"]["Conflict"];
+    };
+  };
+  /**
+   * List tags
+   * @description Returns tags ordered by name.
+   */
+  listTags: {
+    parameters: {
+      query?: {
+        /** @description Only return tags starting with this prefix, ignoring case. */
+        prefix?: string;
+      };
+    };
+    responses: {
+      /** @description Matching tags */
+      200: {
+        content: {
+          "application/json": {
+            tags: components["schemas"]["Tag"][];
+          };
+        };
+      };
+    };
+  };
+  /**
+   * Create a tag
+   * @description Tags are also created when a book first uses them.
+   */
+  createTag: {
+    requestBody: {
+      content: {
+        "application/json": components["schemas"]["TagCreate"];
+      };
+    };
+    responses: {
+      /** @description Tag created */
+      201: {
+        content: {
+          "application/json": components["schemas"]["Tag"];
+        };
+      };
+      400: components["responses"]["BadRequest"];
+      409: components["responses"]["Conflict"];
+    };
+  };
+  /**
+   * Rename a tag
+   * @description Books with the tag list it under the new name.
+   */
+  updateTag: {
     parameters: {
       path: {
         id: string;
       };
     };
+    requestBody: {
+      content: {
+        "application/json": components["schemas"]["TagUpdate"];
+      };
+    };
     responses: {
-      /** @description Book deleted */
+      /** @description Updated tag */
+      200: {
+        content: {
+          "application/json": components["schemas"]["Tag"];
+        };
+      };
+      400: components["responses"]["BadRequest"];
+      404: components["responses"]["NotFound"];
+      409: components["responses"]["Conflict"];
+    };
+  };
+  /**
+   * Delete a tag
+   * @description Fails with 409 while any book, including deleted ones, has the tag.
+   */
+  deleteTag: {
+    parameters: {
+      path: {
+        id: string;
+      };
+    };
+    responses: {
+      /** @description Tag deleted */
       204: {
         content: never;
       };
       404: components["responses"]["NotFound"];
+      409: components["responses"]["Conflict"];
+    };
+  };
+  /**
+   * List exchange rates
+   * @description Returns every stored rate ordered by base and quote currency.
+   */
+  listFxRates: {
+    responses: {
+      /** @description All exchange rates */
+      200: {
+        content: {
+          "application/json": {
+            rates: components["schemas"]["FxRate"][];
+          };
+        };
+      };
+    };
+  };
+  /**
+   * Load exchange rates
+   * @description Stores rates quoted against one base currency, replacing the previous rate of each pair. Pairs not in the request keep their rate. The `api fx-rates -source FILE_OR_URL` command loads the same document.
+   */
+  loadFxRates: {
+    requestBody: {
+      content: {
+        "application/json": components["schemas"]["FxRatesLoad"];
+      };
+    };
+    responses: {
+      /** @description The stored rates */
+      200: {
+        content: {
+          "application/json": {
+            rates: components["schemas"]["FxRate"][];
+          };
+        };
+      };
+      400: components["responses"]["BadRequest"];
     };
   };
 }
@@ -25,7 +25,7 @@ describe('BookForm', () => {
       id: '123',
       title: 'New Book',
       author: 'Jane Doe',
-      price: 10,
+      price: '10',
       currency: 'USD',
       stock: 5,
       createdAt: new Date().toISOString(),
@@ -67,7 +67,7 @@ describe('BookForm', () => {
       id: 'abc',
       title: 'Original',
       author: 'Author',
-      price: 20,
+      price: '20',
       currency: 'USD',
       stock: 3,
       createdAt: new Date().toISOString(),
@@ -16,7 +16,7 @@ describe('Home page table', () => {
         id: '1',
         title: 'Go in Action',
         author: 'William Kennedy',
-        price: 39.99,
+        price: '39.99',
         currency: 'USD',
         stock: 3,
         createdAt: new Date().toISOString(),
@@ -26,7 +26,7 @@ describe('Home page table', () => {
         id: '2',
         title: 'Concurrency in Go',
         author: 'Katherine Cox-Buday',
-        price: 29.99,
+        price: '29.99',
         currency: 'USD',
         stock: 2,
         createdAt: new Date().toISOString(),